@@ -0,0 +1,69 @@
+package reader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// InvalidTokenError is returned by VerifyToken when the API rejects the token
+type InvalidTokenError struct {
+	// StatusCode is the HTTP status code of the response
+	StatusCode int
+}
+
+func (e *InvalidTokenError) Error() string {
+	return fmt.Sprintf("invalid token (status %d)", e.StatusCode)
+}
+
+// TokenVerifier is implemented by clients that can check their token, such
+// as the clients returned by NewClient
+type TokenVerifier interface {
+	VerifyToken(ctx context.Context) error
+}
+
+// VerifyToken checks that the token of c is valid using the Readwise token
+// check endpoint (/api/v2/auth/), without touching any document. It returns
+// an *InvalidTokenError if the token is rejected, and an *APIError for any
+// other unexpected response. It returns an error wrapping
+// errors.ErrUnsupported if c does not implement TokenVerifier.
+func VerifyToken(ctx context.Context, c Client) error {
+	v, ok := c.(TokenVerifier)
+	if !ok {
+		return fmt.Errorf("failed to verify token: %w", errors.ErrUnsupported)
+	}
+	return v.VerifyToken(ctx)
+}
+
+// VerifyToken implements TokenVerifier
+func (c *client) VerifyToken(ctx context.Context) error {
+	// The token check is part of the v2 API, next to the v3 Reader API
+	url := strings.TrimSuffix(c.baseURL, "/v3") + "/v2/auth/"
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Token "+c.token)
+
+	resp, err := c.do(req, "auth", "")
+	if err != nil {
+		return fmt.Errorf("failed to verify token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNoContent:
+		return nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return &InvalidTokenError{StatusCode: resp.StatusCode}
+	default:
+		return &APIError{
+			StatusCode: resp.StatusCode,
+			Message:    fmt.Sprintf("unexpected status code: %d", resp.StatusCode),
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package reader
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_VerifyToken(t *testing.T) {
+	tests := []struct {
+		name          string
+		serverStatus  int
+		wantErr       bool
+		wantInvalid   bool
+		wantAPIStatus int
+	}{
+		{
+			name:         "valid_token",
+			serverStatus: http.StatusNoContent,
+		},
+		{
+			name:         "invalid_token",
+			serverStatus: http.StatusUnauthorized,
+			wantErr:      true,
+			wantInvalid:  true,
+		},
+		{
+			name:         "forbidden",
+			serverStatus: http.StatusForbidden,
+			wantErr:      true,
+			wantInvalid:  true,
+		},
+		{
+			name:          "server_error",
+			serverStatus:  http.StatusInternalServerError,
+			wantErr:       true,
+			wantAPIStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodGet {
+					t.Errorf("Expected GET request, got %s", r.Method)
+				}
+				if r.URL.Path != "/api/v2/auth/" {
+					t.Errorf("Expected path /api/v2/auth/, got %s", r.URL.Path)
+				}
+				if auth := r.Header.Get("Authorization"); auth != "Token test-token" {
+					t.Errorf("Expected Authorization header Token test-token, got %s", auth)
+				}
+				w.WriteHeader(tt.serverStatus)
+			}))
+			defer server.Close()
+
+			client := &client{
+				baseURL:    server.URL + "/api/v3",
+				token:      "test-token",
+				httpClient: &http.Client{},
+			}
+
+			err := VerifyToken(context.Background(), client)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("VerifyToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			var invalidErr *InvalidTokenError
+			if got := errors.As(err, &invalidErr); got != tt.wantInvalid {
+				t.Errorf("errors.As(InvalidTokenError) = %v, want %v", got, tt.wantInvalid)
+			}
+
+			if tt.wantAPIStatus != 0 {
+				var apiErr *APIError
+				if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.wantAPIStatus {
+					t.Errorf("Expected APIError with status %d, got %v", tt.wantAPIStatus, err)
+				}
+			}
+		})
+	}
+}
+
+func TestVerifyToken_Unsupported(t *testing.T) {
+	var c struct{ Client }
+	if err := VerifyToken(context.Background(), c); !errors.Is(err, errors.ErrUnsupported) {
+		t.Errorf("VerifyToken() error = %v, want errors.ErrUnsupported", err)
+	}
+}
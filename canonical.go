@@ -0,0 +1,133 @@
+package reader
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// trackingParams are query parameters that only carry tracking information and
+// never change the content a URL points at.
+var trackingParams = map[string]bool{
+	"fbclid":     true,
+	"gclid":      true,
+	"dclid":      true,
+	"msclkid":    true,
+	"yclid":      true,
+	"igshid":     true,
+	"mc_cid":     true,
+	"mc_eid":     true,
+	"_hsenc":     true,
+	"_hsmi":      true,
+	"mkt_tok":    true,
+	"ref":        true,
+	"ref_src":    true,
+	"ref_url":    true,
+	"amp":        true,
+	"outputtype": true,
+}
+
+// trackingParamPrefixes are prefixes of query parameter names used for tracking
+var trackingParamPrefixes = []string{"utm_", "pk_", "mtm_"}
+
+// hostPrefixes are host name prefixes for mobile and AMP mirrors of a site.
+// They are only removed from hosts with at least two labels after them, so
+// that e.g. amp.dev is kept.
+var hostPrefixes = []string{"www.", "m.", "mobile.", "amp."}
+
+// Canonicalize normalizes a URL so that different links to the same content
+// compare equal. It lowercases the scheme and host, upgrades http to https,
+// drops default ports, fragments, tracking query parameters, "www." and
+// mobile/AMP host prefixes, AMP path segments and trailing slashes, and sorts
+// the remaining query parameters.
+func Canonicalize(rawURL string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse URL: %w", err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf("URL must be absolute: %s", rawURL)
+	}
+
+	// Scheme
+	u.Scheme = strings.ToLower(u.Scheme)
+	if u.Scheme == "http" {
+		u.Scheme = "https"
+	}
+
+	// Host
+	host := strings.ToLower(u.Hostname())
+	for _, prefix := range hostPrefixes {
+		if rest, ok := strings.CutPrefix(host, prefix); ok && strings.Contains(rest, ".") {
+			host = rest
+		}
+	}
+	if port := u.Port(); port != "" && port != "80" && port != "443" {
+		host = host + ":" + port
+	}
+	u.Host = host
+	u.User = nil
+
+	// Path
+	path := u.EscapedPath()
+	path = strings.TrimPrefix(path, "/amp/")
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	path = strings.TrimSuffix(path, "/")
+	path = strings.TrimSuffix(path, "/amp")
+	path = strings.TrimSuffix(path, ".amp")
+	u.RawPath = ""
+	u.Path, err = url.PathUnescape(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to unescape path: %w", err)
+	}
+
+	// Query
+	q := u.Query()
+	for key := range q {
+		if isTrackingParam(key) {
+			q.Del(key)
+		}
+	}
+	keys := make([]string, 0, len(q))
+	for key := range q {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, key := range keys {
+		values := q[key]
+		sort.Strings(values)
+		for _, value := range values {
+			if b.Len() > 0 {
+				b.WriteByte('&')
+			}
+			b.WriteString(url.QueryEscape(key))
+			b.WriteByte('=')
+			b.WriteString(url.QueryEscape(value))
+		}
+	}
+	u.RawQuery = b.String()
+	u.ForceQuery = false
+
+	// Fragment
+	u.Fragment = ""
+	u.RawFragment = ""
+
+	return u.String(), nil
+}
+
+func isTrackingParam(key string) bool {
+	key = strings.ToLower(key)
+	if trackingParams[key] {
+		return true
+	}
+	for _, prefix := range trackingParamPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
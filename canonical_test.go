@@ -0,0 +1,93 @@
+package reader
+
+import (
+	"testing"
+)
+
+func TestCanonicalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "already canonical",
+			url:  "https://example.com/article",
+			want: "https://example.com/article",
+		},
+		{
+			name: "trailing slash",
+			url:  "https://example.com/article/",
+			want: "https://example.com/article",
+		},
+		{
+			name: "tracking parameters",
+			url:  "https://example.com/article?utm_source=twitter&utm_medium=social&fbclid=abc&id=42",
+			want: "https://example.com/article?id=42",
+		},
+		{
+			name: "scheme, host case and www",
+			url:  "HTTP://WWW.Example.COM/Article",
+			want: "https://example.com/Article",
+		},
+		{
+			name: "AMP path suffix",
+			url:  "https://example.com/article/amp/",
+			want: "https://example.com/article",
+		},
+		{
+			name: "AMP path prefix and host",
+			url:  "https://amp.example.com/amp/article",
+			want: "https://example.com/article",
+		},
+		{
+			name: "mobile host and fragment",
+			url:  "https://m.example.com/article#comments",
+			want: "https://example.com/article",
+		},
+		{
+			name: "default port dropped and query sorted",
+			url:  "https://example.com:443/search?q=go&a=1",
+			want: "https://example.com/search?a=1&q=go",
+		},
+		{
+			name: "AMP host prefix of a two-label host kept",
+			url:  "https://amp.dev/documentation",
+			want: "https://amp.dev/documentation",
+		},
+		{
+			name: "non-default port kept",
+			url:  "https://example.com:8080/article",
+			want: "https://example.com:8080/article",
+		},
+		{
+			name: "root path",
+			url:  "https://example.com/",
+			want: "https://example.com",
+		},
+		{
+			name:    "relative URL",
+			url:     "/article",
+			wantErr: true,
+		},
+		{
+			name:    "invalid URL",
+			url:     "://bad",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Canonicalize(tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Canonicalize() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Canonicalize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
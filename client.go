@@ -3,6 +3,7 @@ package reader
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"time"
 )
@@ -22,13 +23,60 @@ type Client interface {
 
 // client is the implementation of the Client interface
 type client struct {
-	baseURL    string
-	token      string
-	httpClient *http.Client
+	baseURL         string
+	token           string
+	httpClient      *http.Client
+	instrumentation Instrumentation
+	maxRetries      int
+	logger          *slog.Logger
+	limiter         *rateLimiter
+}
+
+// Option configures a client created by NewClient
+type Option func(*client)
+
+// WithHTTPClient sets the HTTP client used to make requests
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithBaseURL sets the base URL of the API, e.g. for testing against a stand-in server
+func WithBaseURL(baseURL string) Option {
+	return func(c *client) {
+		c.baseURL = baseURL
+	}
+}
+
+// WithInstrumentation reports every request the client makes to the given
+// instrumentations, e.g. for metrics and tracing
+func WithInstrumentation(instrumentations ...Instrumentation) Option {
+	return func(c *client) {
+		c.instrumentation = multiInstrumentation(append(c.instrumentations(), instrumentations...))
+	}
+}
+
+// WithMaxRetries retries requests rejected by rate limiting (HTTP 429) up to
+// n times, waiting as long as the Retry-After header asks. Defaults to 0.
+func WithMaxRetries(n int) Option {
+	return func(c *client) {
+		c.maxRetries = n
+	}
+}
+
+// WithRateLimit limits the client to n requests per interval, spacing them
+// evenly, so that scripts stay below the API rate limits
+func WithRateLimit(n int, interval time.Duration) Option {
+	return func(c *client) {
+		if n > 0 {
+			c.limiter = &rateLimiter{interval: interval / time.Duration(n)}
+		}
+	}
 }
 
 // NewClient creates a new Readwise Reader client
-func NewClient(token string) (Client, error) {
+func NewClient(token string, opts ...Option) (Client, error) {
 	if token == "" {
 		return nil, &ClientError{
 			Type:    "invalid_token",
@@ -36,13 +84,18 @@ func NewClient(token string) (Client, error) {
 		}
 	}
 
-	return &client{
+	c := &client{
 		baseURL: defaultBaseURL,
 		token:   token,
 		httpClient: &http.Client{
 			Timeout: defaultTimeout,
 		},
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c, nil
 }
 
 // ClientError represents an error from the client
@@ -65,3 +118,8 @@ type APIError struct {
 func (e *APIError) Error() string {
 	return fmt.Sprintf("API error (status %d): %s", e.StatusCode, e.Message)
 }
+
+// Is reports whether the error is ErrNotFound, for API errors with status 404
+func (e *APIError) Is(target error) bool {
+	return target == ErrNotFound && e.StatusCode == http.StatusNotFound
+}
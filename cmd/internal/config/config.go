@@ -0,0 +1,230 @@
+// Package config loads the configuration file shared by the reader CLI and
+// the reader MCP server.
+//
+// The configuration file lives at $XDG_CONFIG_HOME/reader/config.toml
+// (~/.config/reader/config.toml by default) and holds named profiles:
+//
+//	default_profile = "personal"
+//
+//	[profiles.personal]
+//	token = "..."
+//	default_location = "later"
+//
+//	[profiles.team]
+//	token_command = "pass show readwise/team"
+//	output = "compact"
+//	rate_limit = 20
+//	max_retries = 3
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	reader "github.com/tcnksm/go-readwise-reader"
+)
+
+// DefaultProfileName is the profile used when none is selected
+const DefaultProfileName = "default"
+
+// TokenEnv is the environment variable holding the token when the selected
+// profile does not define one
+const TokenEnv = "READWISE_ACCESS_TOKEN"
+
+// ProfileEnv is the environment variable selecting the profile when the
+// -profile flag is not given
+const ProfileEnv = "READER_PROFILE"
+
+// Config is the content of the configuration file
+type Config struct {
+	// DefaultProfile is the profile used when none is selected
+	DefaultProfile string `toml:"default_profile,omitempty"`
+
+	// Profiles are the named profiles
+	Profiles map[string]*Profile `toml:"profiles,omitempty"`
+}
+
+// Profile holds the settings for one Readwise account
+type Profile struct {
+	// Token is the Readwise access token
+	Token string `toml:"token,omitempty"`
+
+	// TokenCommand is a shell command printing the token, e.g. "pass show readwise".
+	// It is used when Token is empty.
+	TokenCommand string `toml:"token_command,omitempty"`
+
+	// DefaultLocation is the location used when a command is not given one
+	DefaultLocation reader.Location `toml:"default_location,omitempty"`
+
+	// Output is the JSON output style: "pretty" (default) or "compact"
+	Output string `toml:"output,omitempty"`
+
+	// RateLimit is the maximum number of API requests per minute (optional)
+	RateLimit int `toml:"rate_limit,omitempty"`
+
+	// MaxRetries is the number of times rate limited requests are retried (optional)
+	MaxRetries int `toml:"max_retries,omitempty"`
+}
+
+// DefaultPath returns the path of the configuration file:
+// $XDG_CONFIG_HOME/reader/config.toml, ~/.config/reader/config.toml by default
+func DefaultPath() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get home directory: %w", err)
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "reader", "config.toml"), nil
+}
+
+// DataDir returns the directory where the CLI and the MCP server keep local
+// state such as audit logs and the undo journal, creating it if needed:
+// $XDG_DATA_HOME/reader, ~/.local/share/reader by default
+func DataDir() (string, error) {
+	dir := os.Getenv("XDG_DATA_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get home directory: %w", err)
+		}
+		dir = filepath.Join(home, ".local", "share")
+	}
+	dir = filepath.Join(dir, "reader")
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("failed to create data directory: %w", err)
+	}
+	return dir, nil
+}
+
+// JournalPath returns the path of the undo journal shared by the CLI and the
+// MCP server
+func JournalPath() (string, error) {
+	dir, err := DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "journal.jsonl"), nil
+}
+
+// Load reads the configuration file at path. A missing file yields an empty
+// configuration.
+func Load(path string) (*Config, error) {
+	cfg := &Config{}
+	if _, err := toml.DecodeFile(path, cfg); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return cfg, nil
+		}
+		return nil, fmt.Errorf("failed to load config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Save writes the configuration to path, readable only by the user since it
+// may contain tokens
+func (c *Config) Save(path string) error {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(c); err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("failed to save config %s: %w", path, err)
+	}
+	return nil
+}
+
+// ProfileName resolves the name of the profile to use: name if not empty,
+// then $READER_PROFILE, then the default profile of the configuration
+func (c *Config) ProfileName(name string) string {
+	if name != "" {
+		return name
+	}
+	if name := os.Getenv(ProfileEnv); name != "" {
+		return name
+	}
+	if c.DefaultProfile != "" {
+		return c.DefaultProfile
+	}
+	return DefaultProfileName
+}
+
+// Profile returns the profile with the given name, resolved as by ProfileName.
+// The default profile may be missing from the file, in which case it is empty
+// and the token is read from $READWISE_ACCESS_TOKEN.
+func (c *Config) Profile(name string) (*Profile, error) {
+	name = c.ProfileName(name)
+	if p, ok := c.Profiles[name]; ok {
+		return p, nil
+	}
+	if name == DefaultProfileName {
+		return &Profile{}, nil
+	}
+	return nil, fmt.Errorf("profile not found: %s", name)
+}
+
+// SetProfile adds or replaces a profile, making it the default profile if
+// there is none yet
+func (c *Config) SetProfile(name string, p *Profile) {
+	if c.Profiles == nil {
+		c.Profiles = make(map[string]*Profile)
+	}
+	c.Profiles[name] = p
+	if c.DefaultProfile == "" {
+		c.DefaultProfile = name
+	}
+}
+
+// ResolveToken returns the token of the profile: Token, else the output of
+// TokenCommand, else $READWISE_ACCESS_TOKEN
+func (p *Profile) ResolveToken(ctx context.Context) (string, error) {
+	if p.Token != "" {
+		return p.Token, nil
+	}
+
+	if p.TokenCommand != "" {
+		var stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, "sh", "-c", p.TokenCommand)
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if err != nil {
+			return "", fmt.Errorf("token command failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		// Like pass, commands may print more lines after the token
+		token, _, _ := strings.Cut(string(out), "\n")
+		if token = strings.TrimSpace(token); token == "" {
+			return "", fmt.Errorf("token command printed no token")
+		}
+		return token, nil
+	}
+
+	if token := os.Getenv(TokenEnv); token != "" {
+		return token, nil
+	}
+	return "", fmt.Errorf("%s not set and no token configured in profile", TokenEnv)
+}
+
+// ClientOptions returns the client options for the rate limit settings of the profile
+func (p *Profile) ClientOptions() []reader.Option {
+	var opts []reader.Option
+	if p.RateLimit > 0 {
+		opts = append(opts, reader.WithRateLimit(p.RateLimit, time.Minute))
+	}
+	if p.MaxRetries > 0 {
+		opts = append(opts, reader.WithMaxRetries(p.MaxRetries))
+	}
+	return opts
+}
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "reader", "config.toml")
+
+	// Missing file
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(cfg.Profiles) != 0 {
+		t.Errorf("Profiles = %v, want empty", cfg.Profiles)
+	}
+
+	cfg.SetProfile("personal", &Profile{Token: "personal-token", DefaultLocation: "later"})
+	cfg.SetProfile("team", &Profile{TokenCommand: "echo team-token", RateLimit: 20})
+	if err := cfg.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("permissions = %o, want 600", perm)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if loaded.DefaultProfile != "personal" {
+		t.Errorf("DefaultProfile = %v, want personal", loaded.DefaultProfile)
+	}
+	if p := loaded.Profiles["team"]; p == nil || p.TokenCommand != "echo team-token" || p.RateLimit != 20 {
+		t.Errorf("Profiles[team] = %+v", p)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte("profiles = ["), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("Expected error, got none")
+	}
+}
+
+func TestConfig_Profile(t *testing.T) {
+	cfg := &Config{
+		DefaultProfile: "personal",
+		Profiles: map[string]*Profile{
+			"personal": {Token: "personal-token"},
+			"team":     {Token: "team-token"},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		profile   string
+		env       string
+		wantToken string
+		wantErr   bool
+	}{
+		{name: "default profile", wantToken: "personal-token"},
+		{name: "explicit profile", profile: "team", wantToken: "team-token"},
+		{name: "profile from environment", env: "team", wantToken: "team-token"},
+		{name: "flag overrides environment", profile: "personal", env: "team", wantToken: "personal-token"},
+		{name: "unknown profile", profile: "other", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(ProfileEnv, tt.env)
+			p, err := cfg.Profile(tt.profile)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Profile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && p.Token != tt.wantToken {
+				t.Errorf("Token = %v, want %v", p.Token, tt.wantToken)
+			}
+		})
+	}
+
+	// The default profile falls back to an empty profile
+	t.Setenv(ProfileEnv, "")
+	empty := &Config{}
+	if p, err := empty.Profile(""); err != nil || *p != (Profile{}) {
+		t.Errorf("Profile() = %+v, %v, want empty profile", p, err)
+	}
+}
+
+func TestProfile_ResolveToken(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile Profile
+		env     string
+		want    string
+		wantErr bool
+	}{
+		{name: "token", profile: Profile{Token: "abc", TokenCommand: "echo xyz"}, want: "abc"},
+		{name: "token command", profile: Profile{TokenCommand: "printf 'xyz\\nmetadata\\n'"}, want: "xyz"},
+		{name: "failing token command", profile: Profile{TokenCommand: "exit 1"}, wantErr: true},
+		{name: "empty token command output", profile: Profile{TokenCommand: "true"}, wantErr: true},
+		{name: "environment", env: "from-env", want: "from-env"},
+		{name: "missing", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(TokenEnv, tt.env)
+			got, err := tt.profile.ResolveToken(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveToken() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultPath(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/xdg")
+	if path, err := DefaultPath(); err != nil || path != filepath.Join("/xdg", "reader", "config.toml") {
+		t.Errorf("DefaultPath() = %v, %v", path, err)
+	}
+
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("HOME", home)
+	if path, err := DefaultPath(); err != nil || path != filepath.Join(home, ".config", "reader", "config.toml") {
+		t.Errorf("DefaultPath() = %v, %v", path, err)
+	}
+}
@@ -0,0 +1,10 @@
+module github.com/tcnksm/go-readwise-reader/cmd/internal/config
+
+go 1.24.5
+
+require (
+	github.com/BurntSushi/toml v1.5.0
+	github.com/tcnksm/go-readwise-reader v0.0.0-00010101000000-000000000000
+)
+
+replace github.com/tcnksm/go-readwise-reader => ../../../
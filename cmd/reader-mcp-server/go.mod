@@ -4,13 +4,30 @@ go 1.24.5
 
 require (
 	github.com/mark3labs/mcp-go v0.34.0
+	github.com/prometheus/client_golang v1.23.2
 	github.com/tcnksm/go-readwise-reader v0.0.0-20250720050601-1ea536251168
+	github.com/tcnksm/go-readwise-reader/cmd/internal/config v0.0.0-00010101000000-000000000000
+	github.com/tcnksm/go-readwise-reader/instrument/prometheus v0.0.0-00010101000000-000000000000
 )
 
 require (
+	github.com/BurntSushi/toml v1.5.0 // indirect
+	github.com/beorn7/perks v1.0.1 // indirect
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
 	github.com/google/uuid v1.6.0 // indirect
+	github.com/munnerz/goautoneg v0.0.0-20191010083416-a7dc8b61c822 // indirect
+	github.com/prometheus/client_model v0.6.2 // indirect
+	github.com/prometheus/common v0.66.1 // indirect
+	github.com/prometheus/procfs v0.16.1 // indirect
 	github.com/spf13/cast v1.7.1 // indirect
 	github.com/yosida95/uritemplate/v3 v3.0.2 // indirect
+	go.yaml.in/yaml/v2 v2.4.2 // indirect
+	golang.org/x/sys v0.35.0 // indirect
+	google.golang.org/protobuf v1.36.8 // indirect
 )
 
 replace github.com/tcnksm/go-readwise-reader => ../../
+
+replace github.com/tcnksm/go-readwise-reader/instrument/prometheus => ../../instrument/prometheus
+
+replace github.com/tcnksm/go-readwise-reader/cmd/internal/config => ../internal/config
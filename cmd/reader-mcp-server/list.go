@@ -38,6 +38,10 @@ func toolList(client reader.Client) (mcp.Tool, server.ToolHandlerFunc) {
 				"unread",
 				mcp.Description("Only return unread documents"),
 			),
+			mcp.WithString(
+				"progress",
+				mcp.Description("Only return documents with this reading progress: unstarted, in_progress, or finished"),
+			),
 		),
 		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			// Extract parameters
@@ -47,18 +51,9 @@ func toolList(client reader.Client) (mcp.Tool, server.ToolHandlerFunc) {
 			}
 
 			// Validate location
-			var loc reader.Location
-			switch location {
-			case "new", "inbox":
-				loc = reader.LocationNew
-			case "later":
-				loc = reader.LocationLater
-			case "archive":
-				loc = reader.LocationArchive
-			case "feed":
-				loc = reader.LocationFeed
-			default:
-				return mcp.NewToolResultError("invalid location: must be one of new, inbox, later, archive, or feed"), nil
+			loc, err := parseLocation(location)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
 			}
 
 			// Parse optional parameters
@@ -82,31 +77,28 @@ func toolList(client reader.Client) (mcp.Tool, server.ToolHandlerFunc) {
 				return mcp.NewToolResultError("limit must be greater than 0"), nil
 			}
 
-			// Call Readwise API
-			resp, err := client.ListDocuments(ctx, opts)
-			if err != nil {
-				return mcp.NewToolResultError(fmt.Sprintf("failed to list documents: %v", err)), nil
-			}
-
-			// Filter unread documents if requested
-			if req.GetBool("unread", false) {
-				results := make([]reader.Document, 0, len(resp.Results))
-				for _, doc := range resp.Results {
-					if doc.FirstOpenedAt != nil {
-						continue
-					}
-					results = append(results, doc)
+			var progress reader.Progress
+			if p := req.GetString("progress", ""); p != "" {
+				if progress, err = reader.ParseProgress(p); err != nil {
+					return mcp.NewToolResultError(err.Error()), nil
 				}
-				resp.Results = results
 			}
 
-			// Limit results if needed
-			if len(resp.Results) > limit {
-				resp.Results = resp.Results[:limit]
+			// Call Readwise API, filtering unread documents and progress
+			// across pages until limit documents are found
+			unread := req.GetBool("unread", false)
+			docs, err := reader.FindDocuments(ctx, client, opts, func(doc reader.Document) bool {
+				if unread && doc.Seen() {
+					return false
+				}
+				return progress == "" || doc.Progress() == progress
+			}, limit)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to list documents: %v", err)), nil
 			}
 
 			// Return JSON response
-			jsonData, err := json.MarshalIndent(resp.Results, "", "  ")
+			jsonData, err := json.MarshalIndent(docs, "", "  ")
 			if err != nil {
 				return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
 			}
@@ -114,3 +106,13 @@ func toolList(client reader.Client) (mcp.Tool, server.ToolHandlerFunc) {
 			return mcp.NewToolResultText(string(jsonData)), nil
 		}
 }
+
+// parseLocation parses a location argument. The tools have always accepted
+// "inbox" for "new", so it is kept as an alias here rather than in
+// reader.ParseLocation.
+func parseLocation(s string) (reader.Location, error) {
+	if s == "inbox" {
+		return reader.LocationNew, nil
+	}
+	return reader.ParseLocation(s)
+}
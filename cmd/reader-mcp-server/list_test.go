@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	reader "github.com/tcnksm/go-readwise-reader"
+)
+
+// listClient is a reader.Client that records the options it lists with
+type listClient struct {
+	reader.Client
+	opts []reader.ListDocumentsOptions
+}
+
+func (c *listClient) ListDocuments(ctx context.Context, opts *reader.ListDocumentsOptions) (*reader.ListDocumentsResponse, error) {
+	c.opts = append(c.opts, *opts)
+	return &reader.ListDocumentsResponse{}, nil
+}
+
+func TestToolList_Location(t *testing.T) {
+	for _, tt := range []struct {
+		location string
+		want     reader.Location
+	}{
+		{"new", reader.LocationNew},
+		{"inbox", reader.LocationNew},
+		{"archive", reader.LocationArchive},
+	} {
+		client := &listClient{}
+		_, handler := toolList(client)
+
+		var req mcp.CallToolRequest
+		req.Params.Arguments = map[string]any{"location": tt.location}
+		result, err := handler(context.Background(), req)
+		if err != nil || result.IsError {
+			t.Fatalf("%s: handler() = %+v, %v", tt.location, result, err)
+		}
+		if len(client.opts) != 1 || client.opts[0].Location != tt.want {
+			t.Errorf("%s: listed with %+v, want location %s", tt.location, client.opts, tt.want)
+		}
+	}
+}
+
+func TestToolList_InvalidLocation(t *testing.T) {
+	// The location is validated before the client is used
+	_, handler := toolList(nil)
+
+	var req mcp.CallToolRequest
+	req.Params.Arguments = map[string]any{"location": "shortlist"}
+	result, err := handler(context.Background(), req)
+	if msg := toolError(t, result, err); !strings.Contains(msg, "invalid location: shortlist") {
+		t.Errorf("error = %q, want an invalid location", msg)
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// logToolCalls returns a middleware that logs every tool invocation with its
+// arguments, duration and outcome. Sensitive arguments such as notes and HTML
+// are redacted by the logger.
+func logToolCalls(logger *slog.Logger) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			args := req.GetArguments()
+			names := make([]string, 0, len(args))
+			for name := range args {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			argAttrs := make([]any, 0, len(names))
+			for _, name := range names {
+				argAttrs = append(argAttrs, slog.Any(name, args[name]))
+			}
+
+			start := time.Now()
+			result, err := next(ctx, req)
+
+			attrs := []any{
+				slog.String("tool", req.Params.Name),
+				slog.Group("arguments", argAttrs...),
+				slog.Duration("duration", time.Since(start)),
+			}
+			switch {
+			case err != nil:
+				logger.ErrorContext(ctx, "tool call failed", append(attrs, slog.Any("error", err))...)
+			case result != nil && result.IsError:
+				logger.WarnContext(ctx, "tool call returned error", attrs...)
+			default:
+				logger.InfoContext(ctx, "tool call", attrs...)
+			}
+
+			return result, err
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	reader "github.com/tcnksm/go-readwise-reader"
+)
+
+func TestLogToolCalls_AddNote(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{ReplaceAttr: reader.RedactAttr}))
+
+	handler := logToolCalls(logger)(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	var req mcp.CallToolRequest
+	req.Params.Name = "add_note"
+	req.Params.Arguments = map[string]any{"id": "doc1", "note": "private summary"}
+	if _, err := handler(context.Background(), req); err != nil {
+		t.Fatalf("handler() error = %v", err)
+	}
+
+	out := buf.String()
+	if strings.Contains(out, "private summary") {
+		t.Errorf("log contains the note: %s", out)
+	}
+	if !strings.Contains(out, `"note":"[REDACTED]"`) || !strings.Contains(out, `"id":"doc1"`) {
+		t.Errorf("log does not contain the redacted arguments: %s", out)
+	}
+}
@@ -1,23 +1,83 @@
 package main
 
 import (
-	"fmt"
-	"log"
+	"context"
+	"flag"
+	"log/slog"
+	"net/http"
 	"os"
 
 	"github.com/mark3labs/mcp-go/server"
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	reader "github.com/tcnksm/go-readwise-reader"
+	"github.com/tcnksm/go-readwise-reader/cmd/internal/config"
+	"github.com/tcnksm/go-readwise-reader/instrument/prometheus"
+	"github.com/tcnksm/go-readwise-reader/journal"
 )
 
 func main() {
-	token := os.Getenv("READWISE_ACCESS_TOKEN")
-	if token == "" {
-		log.Fatal("READWISE_ACCESS_TOKEN not set")
+	metricsAddr := flag.String("metrics-addr", "", "Address to expose Prometheus metrics on at /metrics (e.g., :9090). Disabled if empty")
+	debug := flag.Bool("debug", false, "Log Readwise Reader API requests and responses")
+	configPath := flag.String("config", "", "Path to the configuration file. Default: ~/.config/reader/config.toml")
+	profileName := flag.String("profile", "", "Configuration profile to use. Default: $READER_PROFILE or the default profile")
+	flag.Parse()
+
+	// Log to stderr as stdout is used by the stdio transport
+	level := slog.LevelInfo
+	if *debug {
+		level = slog.LevelDebug
 	}
+	logger := slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: reader.RedactAttr,
+	}))
 
-	readerClient, err := reader.NewClient(token)
+	if *configPath == "" {
+		path, err := config.DefaultPath()
+		if err != nil {
+			fatal(logger, "failed to find config file", slog.Any("error", err))
+		}
+		*configPath = path
+	}
+	cfg, err := config.Load(*configPath)
 	if err != nil {
-		log.Fatalf("failed to create client: %v", err)
+		fatal(logger, "failed to load config", slog.Any("error", err))
+	}
+	profile, err := cfg.Profile(*profileName)
+	if err != nil {
+		fatal(logger, "failed to select profile", slog.Any("error", err))
+	}
+	token, err := profile.ResolveToken(context.Background())
+	if err != nil {
+		fatal(logger, "failed to get token", slog.Any("error", err))
+	}
+
+	opts := append([]reader.Option{reader.WithLogger(logger)}, profile.ClientOptions()...)
+	if *metricsAddr != "" {
+		registry := prom.NewRegistry()
+		instrumentation, err := prometheus.New(registry)
+		if err != nil {
+			fatal(logger, "failed to register metrics", slog.Any("error", err))
+		}
+		opts = append(opts, reader.WithInstrumentation(instrumentation))
+
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+		go func() {
+			logger.Info("serving metrics", slog.String("addr", *metricsAddr))
+			if err := http.ListenAndServe(*metricsAddr, mux); err != nil {
+				fatal(logger, "metrics server error", slog.Any("error", err))
+			}
+		}()
+	}
+
+	readerClient, err := reader.NewClient(token, opts...)
+	if err != nil {
+		fatal(logger, "failed to create client", slog.Any("error", err))
+	}
+	if err := reader.VerifyToken(context.Background(), readerClient); err != nil {
+		fatal(logger, "failed to verify token", slog.Any("error", err))
 	}
 
 	mcpServer := server.NewMCPServer(
@@ -25,13 +85,27 @@ func main() {
 		"0.1.0",
 		server.WithLogging(),
 		server.WithToolCapabilities(false), // TODO:What is this?
+		server.WithToolHandlerMiddleware(logToolCalls(logger)),
 	)
 	mcpServer.AddTool(toolSave(readerClient))
 	mcpServer.AddTool(toolList(readerClient))
-	mcpServer.AddTool(toolMove(readerClient))
+	journalPath, err := config.JournalPath()
+	if err != nil {
+		fatal(logger, "failed to find undo journal", slog.Any("error", err))
+	}
+	undoJournal := journal.Open(journalPath)
+	mcpServer.AddTool(toolMove(readerClient, undoJournal, logger))
+	mcpServer.AddTool(toolMarkRead(readerClient, undoJournal, logger))
+	mcpServer.AddTool(toolAddNote(readerClient, undoJournal, logger))
 
-	log.Println("Starting Stdio server")
+	logger.Info("starting stdio server")
 	if err := server.ServeStdio(mcpServer); err != nil {
-		fmt.Printf("Server error: %v\n", err)
+		logger.Error("server error", slog.Any("error", err))
 	}
 }
+
+// fatal logs msg at error level and exits
+func fatal(logger *slog.Logger, msg string, args ...any) {
+	logger.Error(msg, args...)
+	os.Exit(1)
+}
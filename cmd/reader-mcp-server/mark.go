@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	reader "github.com/tcnksm/go-readwise-reader"
+	"github.com/tcnksm/go-readwise-reader/journal"
+)
+
+// toolMarkRead marks documents as read or unread, recording their previous
+// state in the undo journal shared with the CLI
+func toolMarkRead(client reader.Client, j *journal.Journal, logger *slog.Logger) (mcp.Tool, server.ToolHandlerFunc) {
+	return mcp.NewTool(
+			"readwise_reader_mark_read",
+			mcp.WithDescription("Mark documents as read (seen) or unread in Readwise Reader"),
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:          "Mark documents as read or unread",
+					ReadOnlyHint:   ToBoolPtr(false),
+					IdempotentHint: ToBoolPtr(true),
+				},
+			),
+			mcp.WithArray(
+				"ids",
+				mcp.Description("The IDs of the documents to mark"),
+				mcp.Required(),
+				mcp.WithStringItems(),
+			),
+			mcp.WithBoolean(
+				"read",
+				mcp.Description("Mark the documents as read (true) or unread (false) (default: true)"),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			// Extract parameters
+			ids, err := req.RequireStringSlice("ids")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if len(ids) == 0 {
+				return mcp.NewToolResultError("ids must not be empty"), nil
+			}
+
+			mark, command := reader.MarkSeen, "reader-mcp-server mark read"
+			if !req.GetBool("read", true) {
+				mark, command = reader.MarkUnseen, "reader-mcp-server mark unread"
+			}
+
+			// Mark each document, recording its previous state for undo
+			var responses []*reader.UpdateDocumentResponse
+			var changes []journal.Change
+			var markErr error
+			for _, id := range ids {
+				before, err := journal.Snapshot(ctx, client, id, false)
+				if err != nil {
+					markErr = err
+					break
+				}
+				resp, err := mark(ctx, client, id)
+				if err != nil {
+					markErr = err
+					break
+				}
+				responses = append(responses, resp)
+				changes = append(changes, journal.Change{Operation: journal.OperationUpdate, DocumentID: id, Before: before})
+			}
+			if len(changes) > 0 {
+				if _, err := j.Record(command, changes); err != nil {
+					logger.WarnContext(ctx, "failed to record changes in the undo journal", slog.Any("error", err))
+				}
+			}
+			if markErr != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to mark documents after %d of %d: %v", len(responses), len(ids), markErr)), nil
+			}
+
+			// Return JSON response
+			jsonData, err := json.MarshalIndent(responses, "", "  ")
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
+			}
+
+			return mcp.NewToolResultText(string(jsonData)), nil
+		}
+}
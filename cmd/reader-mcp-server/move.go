@@ -4,13 +4,17 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	reader "github.com/tcnksm/go-readwise-reader"
+	"github.com/tcnksm/go-readwise-reader/journal"
 )
 
-func toolMove(client reader.Client) (mcp.Tool, server.ToolHandlerFunc) {
+// toolMove moves a document, recording its previous location in the undo
+// journal shared with the CLI so that "reader undo" can move it back
+func toolMove(client reader.Client, j *journal.Journal, logger *slog.Logger) (mcp.Tool, server.ToolHandlerFunc) {
 	return mcp.NewTool(
 			"readwise_reader_move",
 			mcp.WithDescription("Move a document to a different location in Readwise Reader"),
@@ -44,18 +48,9 @@ func toolMove(client reader.Client) (mcp.Tool, server.ToolHandlerFunc) {
 			}
 
 			// Validate location
-			var loc reader.Location
-			switch location {
-			case "new", "inbox":
-				loc = reader.LocationNew
-			case "later":
-				loc = reader.LocationLater
-			case "archive":
-				loc = reader.LocationArchive
-			case "feed":
-				loc = reader.LocationFeed
-			default:
-				return mcp.NewToolResultError("invalid location: must be one of new, inbox, later, archive, or feed"), nil
+			loc, err := parseLocation(location)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
 			}
 
 			// Update document location
@@ -63,11 +58,21 @@ func toolMove(client reader.Client) (mcp.Tool, server.ToolHandlerFunc) {
 				Location: loc,
 			}
 
+			before, err := journal.Snapshot(ctx, client, id, false)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to move document: %v", err)), nil
+			}
+
 			resp, err := client.UpdateDocument(ctx, id, updateReq)
 			if err != nil {
 				return mcp.NewToolResultError(fmt.Sprintf("failed to move document: %v", err)), nil
 			}
 
+			changes := []journal.Change{{Operation: journal.OperationUpdate, DocumentID: id, Before: before}}
+			if _, err := j.Record("reader-mcp-server move", changes); err != nil {
+				logger.WarnContext(ctx, "failed to record changes in the undo journal", slog.Any("error", err))
+			}
+
 			// Return JSON response
 			jsonData, err := json.MarshalIndent(resp, "", "  ")
 			if err != nil {
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// toolError returns the text of a tool error result
+func toolError(t *testing.T, result *mcp.CallToolResult, err error) string {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("handler() error = %v", err)
+	}
+	if !result.IsError || len(result.Content) == 0 {
+		t.Fatalf("result = %+v, want a tool error", result)
+	}
+	text, ok := result.Content[0].(mcp.TextContent)
+	if !ok {
+		t.Fatalf("content = %T, want text", result.Content[0])
+	}
+	return text.Text
+}
+
+func TestToolMove_InvalidLocation(t *testing.T) {
+	// The location is validated before the client is used
+	_, handler := toolMove(nil, nil, slog.New(slog.DiscardHandler))
+
+	var req mcp.CallToolRequest
+	req.Params.Arguments = map[string]any{"id": "doc1", "location": "trash"}
+	result, err := handler(context.Background(), req)
+	if msg := toolError(t, result, err); !strings.Contains(msg, "invalid location: trash") {
+		t.Errorf("error = %q, want an invalid location", msg)
+	}
+}
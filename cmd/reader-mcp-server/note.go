@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	reader "github.com/tcnksm/go-readwise-reader"
+	"github.com/tcnksm/go-readwise-reader/journal"
+)
+
+// toolAddNote appends a note to the notes of a document, recording the
+// previous notes in the undo journal shared with the CLI
+func toolAddNote(client reader.Client, j *journal.Journal, logger *slog.Logger) (mcp.Tool, server.ToolHandlerFunc) {
+	return mcp.NewTool(
+			"readwise_reader_add_note",
+			mcp.WithDescription("Append a note, such as a summary, to the notes of a document in Readwise Reader"),
+			mcp.WithToolAnnotation(
+				mcp.ToolAnnotation{
+					Title:        "Add a note to a document",
+					ReadOnlyHint: ToBoolPtr(false),
+				},
+			),
+			mcp.WithString(
+				"id",
+				mcp.Description("The ID of the document"),
+				mcp.Required(),
+			),
+			mcp.WithString(
+				"note",
+				mcp.Description("The note to append as a new paragraph"),
+				mcp.Required(),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			// Extract parameters
+			id, err := req.RequireString("id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			note, err := req.RequireString("note")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if note == "" {
+				return mcp.NewToolResultError("note must not be empty"), nil
+			}
+
+			before, err := journal.Snapshot(ctx, client, id, false)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to add note: %v", err)), nil
+			}
+
+			updateReq := &reader.UpdateDocumentRequest{
+				Notes: reader.AppendNote(before.Notes, note),
+			}
+			resp, err := client.UpdateDocument(ctx, id, updateReq)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to add note: %v", err)), nil
+			}
+
+			changes := []journal.Change{{Operation: journal.OperationUpdate, DocumentID: id, Before: before}}
+			if _, err := j.Record("reader-mcp-server add_note", changes); err != nil {
+				logger.WarnContext(ctx, "failed to record changes in the undo journal", slog.Any("error", err))
+			}
+
+			// Return JSON response
+			jsonData, err := json.MarshalIndent(resp, "", "  ")
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
+			}
+
+			return mcp.NewToolResultText(string(jsonData)), nil
+		}
+}
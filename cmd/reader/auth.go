@@ -0,0 +1,275 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/subcommands"
+	reader "github.com/tcnksm/go-readwise-reader"
+	"github.com/tcnksm/go-readwise-reader/cmd/internal/config"
+)
+
+type authCmd struct{}
+
+func (*authCmd) Name() string { return "auth" }
+func (*authCmd) Synopsis() string {
+	return "Manage access tokens of configuration profiles"
+}
+func (*authCmd) Usage() string {
+	return `auth <subcommand> [flags]:
+  Manage the access tokens stored in the configuration file.
+
+Subcommands:
+  login   Validate a token and store it in the selected profile
+  status  Show the selected profile and whether its token is valid
+`
+}
+func (*authCmd) SetFlags(f *flag.FlagSet) {}
+
+func (c *authCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	return executeSubcommands(ctx, f, "reader auth", &authLoginCmd{}, &authStatusCmd{})
+}
+
+type authLoginCmd struct {
+	baseCommand
+	tokenCommand string
+}
+
+func (*authLoginCmd) Name() string { return "login" }
+func (*authLoginCmd) Synopsis() string {
+	return "Validate a token and store it in the selected profile"
+}
+func (*authLoginCmd) Usage() string {
+	return `login [flags]:
+  Read an access token from stdin, validate it against the API and store it in
+  the profile selected with -profile, creating the profile if needed. The first
+  profile stored becomes the default profile.
+
+  Get your access token from: https://readwise.io/access_token
+
+Flags:
+  -token-command  Store a shell command printing the token (e.g., "pass show readwise") instead of the token
+`
+}
+func (c *authLoginCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&c.tokenCommand, "token-command", "", "Store a shell command printing the token instead of the token")
+}
+
+func (c *authLoginCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	if f.NArg() != 0 {
+		fmt.Fprintf(os.Stderr, "Usage: %s\n", c.Usage())
+		return subcommands.ExitUsageError
+	}
+
+	name := cfg.ProfileName(*profileName)
+	profile := &config.Profile{}
+	if p, ok := cfg.Profiles[name]; ok {
+		profile = p
+	}
+
+	var token string
+	if c.tokenCommand != "" {
+		profile.Token = ""
+		profile.TokenCommand = c.tokenCommand
+		var err error
+		if token, err = profile.ResolveToken(ctx); err != nil {
+			printError(err)
+			return subcommands.ExitFailure
+		}
+	} else {
+		fmt.Fprintf(os.Stderr, "Access token for profile %q: ", name)
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			printError(fmt.Errorf("failed to read token: %w", err))
+			return subcommands.ExitFailure
+		}
+		if token = strings.TrimSpace(line); token == "" {
+			printError(fmt.Errorf("token is empty"))
+			return subcommands.ExitUsageError
+		}
+		profile.Token = token
+		profile.TokenCommand = ""
+	}
+
+	if err := c.initClientWithToken(token); err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+	if err := reader.VerifyToken(ctx, c.client); err != nil {
+		printError(fmt.Errorf("token is invalid: %w", err))
+		return subcommands.ExitFailure
+	}
+
+	cfg.SetProfile(name, profile)
+	if err := cfg.Save(*configPath); err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+
+	fmt.Fprintf(os.Stderr, "Token stored in profile %q of %s\n", name, *configPath)
+	return subcommands.ExitSuccess
+}
+
+// apiRateLimits are the documented Readwise Reader API rate limits per token
+// in requests per minute
+var apiRateLimits = map[string]int{
+	"list":   20,
+	"create": 50,
+	"update": 50,
+	"delete": 20,
+}
+
+type authStatusCmd struct {
+	baseCommand
+}
+
+// authStatus is the output of auth status
+type authStatus struct {
+	Profile     string          `json:"profile"`
+	Config      string          `json:"config"`
+	TokenSource string          `json:"token_source"`
+	Valid       bool            `json:"valid"`
+	Error       string          `json:"error,omitempty"`
+	RateLimit   rateLimitStatus `json:"rate_limit"`
+}
+
+// rateLimitStatus describes how many requests the profile may make
+type rateLimitStatus struct {
+	// ClientPerMinute is the rate limit of the profile, 0 if unlimited
+	ClientPerMinute int `json:"client_requests_per_minute"`
+
+	// MaxRetries is the number of retries of rate limited requests
+	MaxRetries int `json:"max_retries"`
+
+	// Reported is true if the API reported the state of its rate limit in
+	// the response headers of the token check
+	Reported bool `json:"reported"`
+
+	// Limit and Remaining are the requests allowed and left in the current
+	// window, and ResetAt is when it ends, as reported by the API
+	Limit     *int       `json:"limit,omitempty"`
+	Remaining *int       `json:"remaining,omitempty"`
+	ResetAt   *time.Time `json:"reset_at,omitempty"`
+
+	// RetryAfterSeconds is how long the API asks to wait while rate limited
+	RetryAfterSeconds int `json:"retry_after_seconds,omitempty"`
+
+	// DocumentedPerMinute are the documented API rate limits per endpoint.
+	// They are fixed and do not reflect the requests already made.
+	DocumentedPerMinute map[string]int `json:"documented_requests_per_minute"`
+
+	// RateLimited is true if the API is currently rejecting requests
+	RateLimited bool `json:"rate_limited"`
+}
+
+// set sets the rate limit reported by the API
+func (s *rateLimitStatus) set(rl *reader.RateLimit) {
+	if rl == nil {
+		return
+	}
+	s.Reported = true
+	s.Limit = rl.Limit
+	s.Remaining = rl.Remaining
+	if !rl.Reset.IsZero() {
+		s.ResetAt = &rl.Reset
+	}
+	if rl.RetryAfter != nil {
+		s.RetryAfterSeconds = int(rl.RetryAfter.Round(time.Second) / time.Second)
+	}
+}
+
+// rateLimitRecorder is an instrumentation keeping the rate limit reported by
+// the last response
+type rateLimitRecorder struct {
+	last *reader.RateLimit
+}
+
+func (r *rateLimitRecorder) RequestStarted(ctx context.Context, info reader.RequestInfo) context.Context {
+	return ctx
+}
+
+func (r *rateLimitRecorder) RequestFinished(ctx context.Context, info reader.RequestInfo, result reader.RequestResult) {
+	if result.RateLimit != nil {
+		r.last = result.RateLimit
+	}
+}
+
+func (*authStatusCmd) Name() string { return "status" }
+func (*authStatusCmd) Synopsis() string {
+	return "Show the selected profile and whether its token is valid"
+}
+func (*authStatusCmd) Usage() string {
+	return `status:
+  Show the selected profile, where its token comes from, whether the API
+  accepts it, and the rate limits that apply: the client rate limit of the
+  profile, the remaining requests and reset time if the API reports them in
+  its response headers, and the documented API rate limits. Output is
+  pretty-printed JSON.
+  Exits with a non-zero status if the token is invalid.
+`
+}
+func (*authStatusCmd) SetFlags(f *flag.FlagSet) {}
+
+func (c *authStatusCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	profile, err := cfg.Profile(*profileName)
+	if err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+
+	status := authStatus{
+		Profile:     cfg.ProfileName(*profileName),
+		Config:      *configPath,
+		TokenSource: tokenSource(profile),
+		RateLimit: rateLimitStatus{
+			ClientPerMinute:     profile.RateLimit,
+			MaxRetries:          profile.MaxRetries,
+			DocumentedPerMinute: apiRateLimits,
+		},
+	}
+
+	recorder := &rateLimitRecorder{}
+	token, err := profile.ResolveToken(ctx)
+	if err == nil {
+		opts := append(profile.ClientOptions(), reader.WithInstrumentation(recorder))
+		if err = c.initClientWithToken(token, opts...); err == nil {
+			err = reader.VerifyToken(ctx, c.client)
+		}
+	}
+	status.RateLimit.set(recorder.last)
+	if err != nil {
+		status.Error = err.Error()
+		var apiErr *reader.APIError
+		status.RateLimit.RateLimited = errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusTooManyRequests
+	} else {
+		status.Valid = true
+	}
+
+	if err := printJSON(status); err != nil {
+		printError(fmt.Errorf("failed to output JSON: %w", err))
+		return subcommands.ExitFailure
+	}
+	if !status.Valid {
+		return subcommands.ExitFailure
+	}
+	return subcommands.ExitSuccess
+}
+
+// tokenSource describes where the token of a profile comes from
+func tokenSource(p *config.Profile) string {
+	switch {
+	case p.Token != "":
+		return "token"
+	case p.TokenCommand != "":
+		return "token_command"
+	default:
+		return config.TokenEnv
+	}
+}
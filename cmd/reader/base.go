@@ -1,25 +1,61 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
+	"flag"
 	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/google/subcommands"
 	reader "github.com/tcnksm/go-readwise-reader"
+	"github.com/tcnksm/go-readwise-reader/cmd/internal/config"
+	"github.com/tcnksm/go-readwise-reader/journal"
 )
 
+// logger logs to stderr as configured by the -v, -debug and -log-format flags
+var logger = slog.New(slog.DiscardHandler)
+
+// cfg is the configuration file loaded from the -config flag
+var cfg = &config.Config{}
+
 type baseCommand struct {
 	client reader.Client
 }
 
-func (c *baseCommand) initClient(ctx context.Context) error {
-	token, err := getToken()
+func (c *baseCommand) initClient(ctx context.Context, opts ...reader.Option) error {
+	profile, err := cfg.Profile(*profileName)
 	if err != nil {
 		return err
 	}
+	token, err := profile.ResolveToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := c.initClientWithToken(token, append(profile.ClientOptions(), opts...)...); err != nil {
+		return err
+	}
 
-	client, err := reader.NewClient(token)
+	// Fail fast instead of on the first real request
+	if err := reader.VerifyToken(ctx, c.client); err != nil {
+		return fmt.Errorf("failed to verify token of profile %q: %w", cfg.ProfileName(*profileName), err)
+	}
+	return nil
+}
+
+// initClientWithToken creates the client with the given token instead of the
+// token of the selected profile
+func (c *baseCommand) initClientWithToken(token string, opts ...reader.Option) error {
+	opts = append([]reader.Option{reader.WithLogger(logger)}, opts...)
+	client, err := reader.NewClient(token, opts...)
 	if err != nil {
 		return fmt.Errorf("failed to create client: %w", err)
 	}
@@ -28,20 +64,161 @@ func (c *baseCommand) initClient(ctx context.Context) error {
 	return nil
 }
 
-func getToken() (string, error) {
-	token := os.Getenv("READWISE_ACCESS_TOKEN")
-	if token == "" {
-		return "", fmt.Errorf("READWISE_ACCESS_TOKEN not set")
+// defaultLocation returns the default location of the selected profile, or
+// fallback if it has none
+func defaultLocation(fallback reader.Location) reader.Location {
+	if profile, err := cfg.Profile(*profileName); err == nil && profile.DefaultLocation != "" {
+		return profile.DefaultLocation
+	}
+	return fallback
+}
+
+// newLogger creates a logger writing in the given format (text or json).
+// It logs warnings by default, progress with verbose and API requests and
+// responses with debug. Credentials, notes and HTML are redacted.
+func newLogger(w io.Writer, format string, verbose, debug bool) (*slog.Logger, error) {
+	level := slog.LevelWarn
+	if verbose {
+		level = slog.LevelInfo
+	}
+	if debug {
+		level = slog.LevelDebug
+	}
+	opts := &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: reader.RedactAttr,
+	}
+
+	switch format {
+	case "text":
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("invalid log format: %s. Valid values: text, json", format)
+	}
+}
+
+// loggedCommand logs when a command starts and finishes
+type loggedCommand struct {
+	subcommands.Command
+}
+
+func (c loggedCommand) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	start := time.Now()
+	logger.InfoContext(ctx, "running command", slog.String("command", c.Name()), slog.Any("args", f.Args()))
+
+	status := c.Command.Execute(ctx, f, args...)
+
+	logger.InfoContext(ctx, "command finished",
+		slog.String("command", c.Name()),
+		slog.Int("status", int(status)),
+		slog.Duration("duration", time.Since(start)),
+	)
+	return status
+}
+
+// recordChanges records changes made by a command in the undo journal. A
+// failure is only logged since the changes have already been made.
+func recordChanges(command string, changes []journal.Change) {
+	if len(changes) == 0 {
+		return
+	}
+	path, err := config.JournalPath()
+	if err == nil {
+		_, err = journal.Open(path).Record(command, changes)
+	}
+	if err != nil {
+		logger.Warn("failed to record changes in the undo journal", slog.Any("error", err))
 	}
-	return token, nil
 }
 
+// printJSON prints v as JSON, pretty-printed unless the selected profile
+// sets the output format to compact
 func printJSON(v interface{}) error {
 	encoder := json.NewEncoder(os.Stdout)
-	encoder.SetIndent("", "  ")
+	if profile, err := cfg.Profile(*profileName); err != nil || profile.Output != "compact" {
+		encoder.SetIndent("", "  ")
+	}
 	return encoder.Encode(v)
 }
 
 func printError(err error) {
 	fmt.Fprintln(os.Stderr, "Error:", err)
 }
+
+// stdinIsTerminal reports whether stdin is a terminal
+func stdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// confirm asks a yes/no question on stderr and reads the answer from stdin.
+// Anything but y or yes is a no.
+func confirm(prompt string) (bool, error) {
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", prompt)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, fmt.Errorf("failed to read answer: %w", err)
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
+
+// executeSubcommands runs the nested subcommand named by the first remaining
+// argument of f, e.g. "run" in "reader rules run".
+func executeSubcommands(ctx context.Context, f *flag.FlagSet, name string, cmds ...subcommands.Command) subcommands.ExitStatus {
+	commander := subcommands.NewCommander(f, name)
+	for _, cmd := range cmds {
+		commander.Register(cmd, "")
+	}
+	return commander.Execute(ctx)
+}
+
+// appendJSONLines appends each value as a line of JSON to the file at path
+func appendJSONLines[T any](path string, values []T) error {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	for _, v := range values {
+		if err := encoder.Encode(v); err != nil {
+			return err
+		}
+	}
+	return file.Close()
+}
+
+// newHTTPServer returns a server for addr with timeouts, so that slow or
+// idle clients do not hold connections open
+func newHTTPServer(addr string, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       time.Minute,
+		WriteTimeout:      time.Minute,
+		IdleTimeout:       2 * time.Minute,
+	}
+}
+
+// parseDuration parses a Go duration, additionally accepting a whole number of
+// days ("7d") or weeks ("2w")
+func parseDuration(s string) (time.Duration, error) {
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			v, err := strconv.Atoi(n)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration: %s", s)
+			}
+			return time.Duration(v) * unit, nil
+		}
+	}
+	return time.ParseDuration(s)
+}
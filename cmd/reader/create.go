@@ -9,18 +9,20 @@ import (
 
 	"github.com/google/subcommands"
 	reader "github.com/tcnksm/go-readwise-reader"
+	"github.com/tcnksm/go-readwise-reader/journal"
 )
 
 type createCmd struct {
 	baseCommand
 
 	// Flag values
-	location string
+	location reader.Location
 	notes    string
 	summary  string
 	title    string
 	author   string
 	html     string
+	checkDup bool
 }
 
 func (*createCmd) Name() string { return "create" }
@@ -33,21 +35,24 @@ func (*createCmd) Usage() string {
   Returns the created document as pretty-printed JSON.
 
   Flags:
-    -location string     Document location (new, later, archive, feed)
+    -location string     Document location (new, later, archive, feed). Default: the default location of the profile
     -notes string        Top-level note for the document (use "-" to read from stdin)
     -summary string      Brief summary of the document
     -title string        Document title
     -author string       Document author
     -html string         Document content in valid HTML format (use "-" to read from stdin)
+    -check-duplicate     Fail if a document with the same canonical URL is already saved
 `
 }
 func (c *createCmd) SetFlags(f *flag.FlagSet) {
-	f.StringVar(&c.location, "location", "", "Document location (new, later, archive, feed)")
+	c.location = defaultLocation("")
+	f.Var(&c.location, "location", "Document location (new, later, archive, feed)")
 	f.StringVar(&c.notes, "notes", "", "Top-level note for the document (use '-' to read from stdin)")
 	f.StringVar(&c.summary, "summary", "", "Brief summary of the document")
 	f.StringVar(&c.title, "title", "", "Document title")
 	f.StringVar(&c.author, "author", "", "Document author")
 	f.StringVar(&c.html, "html", "", "Document content in valid HTML format")
+	f.BoolVar(&c.checkDup, "check-duplicate", false, "Fail if a document with the same canonical URL is already saved")
 }
 
 func (c *createCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
@@ -72,13 +77,12 @@ func (c *createCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface
 	}
 
 	// Build CreateDocumentRequest from flags
-	req := &reader.CreateDocumentRequest{}
+	req := &reader.CreateDocumentRequest{
+		CheckDuplicate: c.checkDup,
+	}
 
 	// Set fields only if flags were provided
-	if c.location != "" {
-		loc := reader.Location(c.location)
-		req.Location = loc
-	}
+	req.Location = c.location
 	if c.notes != "" {
 		if c.notes == "-" {
 			// Read notes content from stdin
@@ -123,6 +127,7 @@ func (c *createCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface
 		printError(fmt.Errorf("failed to create document: %w", err))
 		return subcommands.ExitFailure
 	}
+	recordChanges("reader create", []journal.Change{{Operation: journal.OperationCreate, DocumentID: response.ID}})
 
 	// Output created document JSON
 	if err := printJSON(response); err != nil {
@@ -0,0 +1,325 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/subcommands"
+	"github.com/tcnksm/go-readwise-reader/cmd/internal/config"
+	"github.com/tcnksm/go-readwise-reader/journal"
+	"github.com/tcnksm/go-readwise-reader/schedule"
+	"gopkg.in/yaml.v3"
+)
+
+type cronCmd struct{}
+
+func (*cronCmd) Name() string { return "cron" }
+func (*cronCmd) Synopsis() string {
+	return "Run recurring maintenance jobs on a schedule"
+}
+func (*cronCmd) Usage() string {
+	return `cron <subcommand> [flags]:
+  Run the jobs of a YAML schedule file on cron schedules: move documents
+  matching a query (e.g. archive old feed documents), apply rules, export
+  documents and poll feed subscriptions. Runs are recorded in
+  $XDG_DATA_HOME/reader/cron-history.jsonl.
+
+Subcommands:
+  serve    Run the jobs on their schedules and serve their status over HTTP
+  run      Run jobs now
+  history  Show the recorded runs
+`
+}
+func (*cronCmd) SetFlags(f *flag.FlagSet) {}
+
+func (c *cronCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	return executeSubcommands(ctx, f, "reader cron", &cronServeCmd{}, &cronRunCmd{}, &cronHistoryCmd{})
+}
+
+// cronFlags are the flags shared by cron serve and cron run
+type cronFlags struct {
+	baseCommand
+	schedule string
+	history  string
+}
+
+func (c *cronFlags) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&c.schedule, "schedule", "", "Path to the YAML schedule file (required)")
+	f.StringVar(&c.history, "history", "", "Path to the run history")
+}
+
+// scheduler loads the schedule file and returns its scheduler, running the
+// jobs with the client of the selected profile
+func (c *cronFlags) scheduler(ctx context.Context) (*schedule.Scheduler, error) {
+	cfg, err := loadSchedule(c.schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.initClient(ctx); err != nil {
+		return nil, err
+	}
+	path, err := config.JournalPath()
+	if err != nil {
+		return nil, err
+	}
+	jobs, err := cfg.Build(c.client, journal.Open(path))
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := cronHistoryPath(c.history)
+	if err != nil {
+		return nil, err
+	}
+	s, err := schedule.New(history, jobs)
+	if err != nil {
+		return nil, err
+	}
+	s.Logger = logger
+	return s, nil
+}
+
+// cronHistoryPath returns path, or the default run history path if path is
+// empty
+func cronHistoryPath(path string) (string, error) {
+	if path != "" {
+		return path, nil
+	}
+	dir, err := config.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "cron-history.jsonl"), nil
+}
+
+// loadSchedule reads and compiles the YAML schedule file at path, and loads
+// the rules files of its rules jobs
+func loadSchedule(path string) (*schedule.Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schedule: %w", err)
+	}
+
+	var cfg schedule.Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse schedule: %w", err)
+	}
+	for i := range cfg.Jobs {
+		job := &cfg.Jobs[i]
+		if job.Type == schedule.JobFeeds && job.State == "" {
+			dir, err := config.DataDir()
+			if err != nil {
+				return nil, err
+			}
+			job.State = filepath.Join(dir, "feeds.json")
+		}
+	}
+	if err := cfg.Compile(); err != nil {
+		return nil, fmt.Errorf("invalid schedule: %w", err)
+	}
+
+	for i := range cfg.Jobs {
+		job := &cfg.Jobs[i]
+		if job.Type != schedule.JobRules {
+			continue
+		}
+		if job.RuleSet, err = loadRuleSet(job.Rules); err != nil {
+			return nil, fmt.Errorf("job %s: %w", job.Name, err)
+		}
+	}
+	return &cfg, nil
+}
+
+type cronServeCmd struct {
+	cronFlags
+	addr string
+}
+
+func (*cronServeCmd) Name() string { return "serve" }
+func (*cronServeCmd) Synopsis() string {
+	return "Run the jobs on their schedules and serve their status over HTTP"
+}
+func (*cronServeCmd) Usage() string {
+	return `serve -schedule <file> [flags]:
+  Run the jobs of the schedule file at the times of their schedules until
+  interrupted. A job that is still running when it is due again is skipped.
+  The status of the jobs is served as JSON at /status and the recorded runs
+  at /runs (?job=<name>&limit=<n>).
+
+  Jobs use the rate limit and retries of the selected profile. Documents moved
+  by move jobs can be moved back with reader undo.
+
+Flags:
+  -schedule  Path to the YAML schedule file (required)
+  -history   Path to the run history. Default: $XDG_DATA_HOME/reader/cron-history.jsonl
+  -addr      Address to serve the status on, or empty to not serve it. Default: localhost:8082
+`
+}
+func (c *cronServeCmd) SetFlags(f *flag.FlagSet) {
+	c.cronFlags.SetFlags(f)
+	f.StringVar(&c.addr, "addr", "localhost:8082", "Address to serve the status on, or empty to not serve it")
+}
+
+func (c *cronServeCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	if c.schedule == "" || f.NArg() != 0 {
+		fmt.Fprintf(os.Stderr, "Usage: %s\n", c.Usage())
+		return subcommands.ExitUsageError
+	}
+
+	s, err := c.scheduler(ctx)
+	if err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+
+	if c.addr != "" {
+		go func() {
+			logger.Info("serving job status", slog.String("addr", c.addr))
+			if err := http.ListenAndServe(c.addr, s.Handler()); err != nil {
+				logger.Error("status server error", slog.Any("error", err))
+			}
+		}()
+	}
+
+	logger.Info("running scheduled jobs", slog.Int("jobs", len(s.Status())))
+	if err := s.Run(ctx); err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+	return subcommands.ExitSuccess
+}
+
+type cronRunCmd struct {
+	cronFlags
+}
+
+func (*cronRunCmd) Name() string { return "run" }
+func (*cronRunCmd) Synopsis() string {
+	return "Run jobs now"
+}
+func (*cronRunCmd) Usage() string {
+	return `run -schedule <file> [flags] [job]...:
+  Run the named jobs of the schedule file now, or all of them if none is
+  named, and record the runs. Outputs the runs as pretty-printed JSON, and
+  fails if any job failed.
+
+Flags:
+  -schedule  Path to the YAML schedule file (required)
+  -history   Path to the run history. Default: $XDG_DATA_HOME/reader/cron-history.jsonl
+`
+}
+
+func (c *cronRunCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	if c.schedule == "" {
+		fmt.Fprintf(os.Stderr, "Usage: %s\n", c.Usage())
+		return subcommands.ExitUsageError
+	}
+
+	s, err := c.scheduler(ctx)
+	if err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+	names := f.Args()
+	if len(names) == 0 {
+		for _, status := range s.Status() {
+			names = append(names, status.Name)
+		}
+	}
+
+	var runs []*schedule.Run
+	status := subcommands.ExitSuccess
+	for _, name := range names {
+		run, err := s.RunJob(ctx, name)
+		if err != nil {
+			printError(err)
+			status = subcommands.ExitFailure
+			if run == nil {
+				continue
+			}
+		}
+		runs = append(runs, run)
+		if run.Status == schedule.RunFailed {
+			status = subcommands.ExitFailure
+		}
+	}
+	if err := printJSON(runs); err != nil {
+		printError(fmt.Errorf("failed to output JSON: %w", err))
+		return subcommands.ExitFailure
+	}
+	return status
+}
+
+type cronHistoryCmd struct {
+	history string
+	job     string
+	limit   int
+}
+
+func (*cronHistoryCmd) Name() string { return "history" }
+func (*cronHistoryCmd) Synopsis() string {
+	return "Show the recorded runs"
+}
+func (*cronHistoryCmd) Usage() string {
+	return `history [flags]:
+  Show the recorded runs of the jobs, most recent first.
+
+Flags:
+  -history  Path to the run history. Default: $XDG_DATA_HOME/reader/cron-history.jsonl
+  -job      Only show the runs of this job
+  -limit    Maximum number of runs to show. Default: 20
+`
+}
+func (c *cronHistoryCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&c.history, "history", "", "Path to the run history")
+	f.StringVar(&c.job, "job", "", "Only show the runs of this job")
+	f.IntVar(&c.limit, "limit", 20, "Maximum number of runs to show")
+}
+
+func (c *cronHistoryCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	if f.NArg() != 0 || c.limit < 0 {
+		fmt.Fprintf(os.Stderr, "Usage: %s\n", c.Usage())
+		return subcommands.ExitUsageError
+	}
+
+	path, err := cronHistoryPath(c.history)
+	if err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+	s, err := schedule.New(path, nil)
+	if err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+	runs, err := s.History(c.job, c.limit)
+	if err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "JOB\tSTARTED\tDURATION\tSTATUS\tERROR")
+	for _, run := range runs {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			run.Job,
+			run.Start.Local().Format("2006-01-02 15:04"),
+			run.End.Sub(run.Start).Round(100*time.Millisecond),
+			run.Status,
+			truncate(run.Error, 60),
+		)
+	}
+	if err := w.Flush(); err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+	return subcommands.ExitSuccess
+}
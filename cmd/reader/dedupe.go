@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/google/subcommands"
+	reader "github.com/tcnksm/go-readwise-reader"
+)
+
+type dedupeCmd struct {
+	baseCommand
+	location   reader.Location
+	similarity float64
+	dryRun     bool
+}
+
+func (*dedupeCmd) Name() string { return "dedupe" }
+func (*dedupeCmd) Synopsis() string {
+	return "Find and merge duplicate documents"
+}
+func (*dedupeCmd) Usage() string {
+	return `dedupe [flags]:
+  Scan all documents and group duplicates by canonical URL, and by title
+  similarity among documents by the same author or from the same site.
+  Prints the merge plan as pretty-printed JSON. For each group the most-read
+  copy is kept, tags and notes of the others are copied onto it, and the rest
+  are deleted. Nothing is changed unless -dry-run=false is given.
+
+Flags:
+  -location     Only scan documents in this location (new, later, archive, feed). Default: all
+  -similarity   Minimum title similarity (0-1) to treat documents as duplicates, 0 disables. Default: 0.9
+  -dry-run      Only print the merge plan. Default: true
+`
+}
+func (c *dedupeCmd) SetFlags(f *flag.FlagSet) {
+	f.Var(&c.location, "location", "Only scan documents in this location (new, later, archive, feed)")
+	f.Float64Var(&c.similarity, "similarity", reader.DefaultTitleSimilarity, "Minimum title similarity (0-1) to treat documents as duplicates, 0 disables")
+	f.BoolVar(&c.dryRun, "dry-run", true, "Only print the merge plan")
+}
+
+func (c *dedupeCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	if c.similarity < 0 || c.similarity > 1 {
+		printError(fmt.Errorf("invalid similarity: %v. Must be between 0 and 1", c.similarity))
+		return subcommands.ExitUsageError
+	}
+
+	// Initialize client
+	if err := c.initClient(ctx); err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+
+	// Fetch all documents
+	documents, err := reader.ListAllDocuments(ctx, c.client, &reader.ListDocumentsOptions{
+		Location: c.location,
+	})
+	if err != nil {
+		printError(fmt.Errorf("failed to list documents: %w", err))
+		return subcommands.ExitFailure
+	}
+
+	groups := reader.FindDuplicates(documents, &reader.FindDuplicatesOptions{
+		TitleSimilarity: c.similarity,
+	})
+
+	// Output merge plan
+	if err := printJSON(groups); err != nil {
+		printError(fmt.Errorf("failed to output JSON: %w", err))
+		return subcommands.ExitFailure
+	}
+
+	if c.dryRun {
+		return subcommands.ExitSuccess
+	}
+
+	// Apply merge plan
+	for _, group := range groups {
+		if err := reader.MergeDuplicates(ctx, c.client, group); err != nil {
+			printError(fmt.Errorf("failed to merge duplicates of %s: %w", group.Keep.ID, err))
+			return subcommands.ExitFailure
+		}
+		fmt.Printf("Merged %d duplicates into document %s\n", len(group.Duplicates), group.Keep.ID)
+	}
+
+	return subcommands.ExitSuccess
+}
@@ -4,35 +4,79 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"log/slog"
 	"os"
+	"time"
 
 	"github.com/google/subcommands"
+	reader "github.com/tcnksm/go-readwise-reader"
+	"github.com/tcnksm/go-readwise-reader/journal"
+	"github.com/tcnksm/go-readwise-reader/trash"
 )
 
 type deleteCmd struct {
 	baseCommand
+	query  string
+	yes    bool
+	dryRun bool
 }
 
 func (*deleteCmd) Name() string { return "delete" }
 func (*deleteCmd) Synopsis() string {
-	return "Delete a document"
+	return "Delete documents"
 }
 func (*deleteCmd) Usage() string {
-	return `delete <document-id>:
-  Delete the document with the specified ID.
-  Returns success message or error.
+	return `delete [flags] <document-id>...
+delete [flags] -q <query>:
+  Delete the documents with the specified IDs, or the documents matching a
+  query. The documents to delete are listed with their titles and deletion
+  must be confirmed, unless -yes is given.
+
+  Before deletion, each document is put in the local trash with its HTML
+  content (see reader trash) and recorded in the undo journal (see reader undo).
+
+Flags:
+  -q        Delete the documents matching a query instead of IDs, e.g.
+            'location=feed and saved_at < -90d'. Fields: id, title, author,
+            site_name, url, category, location, tag, word_count,
+            reading_progress, progress, seen and the *_at times
+  -yes      Do not ask for confirmation
+  -dry-run  Only print the documents that would be deleted as JSON
 `
 }
-func (*deleteCmd) SetFlags(f *flag.FlagSet) {}
+func (c *deleteCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&c.query, "q", "", "Delete the documents matching a query, e.g. 'location=feed and saved_at < -90d'")
+	f.BoolVar(&c.yes, "yes", false, "Do not ask for confirmation")
+	f.BoolVar(&c.dryRun, "dry-run", false, "Only print the documents that would be deleted")
+}
+
+// deleteTarget is a document to delete as printed by -dry-run
+type deleteTarget struct {
+	ID       string          `json:"id"`
+	Title    string          `json:"title"`
+	URL      string          `json:"url"`
+	Location reader.Location `json:"location"`
+}
 
 func (c *deleteCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
-	// Parse document ID from args
+	// Parse document IDs or the query
 	args := f.Args()
-	if len(args) != 1 {
+	if (len(args) == 0) == (c.query == "") {
 		fmt.Fprintf(os.Stderr, "Usage: %s\n", c.Usage())
 		return subcommands.ExitUsageError
 	}
-	documentID := args[0]
+	var query *reader.Query
+	if c.query != "" {
+		var err error
+		if query, err = reader.ParseQuery(c.query, time.Now()); err != nil {
+			printError(fmt.Errorf("invalid query: %w", err))
+			return subcommands.ExitUsageError
+		}
+	}
+	if !c.yes && !c.dryRun && !stdinIsTerminal() {
+		printError(fmt.Errorf("refusing to delete without confirmation: use -yes or -dry-run when not running in a terminal"))
+		return subcommands.ExitUsageError
+	}
 
 	// Initialize client
 	if err := c.initClient(ctx); err != nil {
@@ -40,15 +84,110 @@ func (c *deleteCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface
 		return subcommands.ExitFailure
 	}
 
-	// Call DeleteDocument API
-	err := c.client.DeleteDocument(ctx, documentID)
+	// Find the documents to delete. Documents given by ID are fetched with
+	// their content right away, matching documents just before deletion.
+	var docs []reader.Document
+	if query != nil {
+		all, err := reader.ListAllDocuments(ctx, c.client, query.ListOptions())
+		if err != nil {
+			printError(fmt.Errorf("failed to list documents: %w", err))
+			return subcommands.ExitFailure
+		}
+		for _, doc := range all {
+			if query.Match(doc) {
+				docs = append(docs, doc)
+			}
+		}
+	} else {
+		for _, documentID := range args {
+			doc, err := journal.Snapshot(ctx, c.client, documentID, true)
+			if err != nil {
+				printError(err)
+				return subcommands.ExitFailure
+			}
+			docs = append(docs, *doc)
+		}
+	}
+
+	if c.dryRun {
+		targets := make([]deleteTarget, 0, len(docs))
+		for _, doc := range docs {
+			targets = append(targets, deleteTarget{ID: doc.ID, Title: doc.Title, URL: doc.URL, Location: doc.Location})
+		}
+		if err := printJSON(targets); err != nil {
+			printError(fmt.Errorf("failed to output JSON: %w", err))
+			return subcommands.ExitFailure
+		}
+		return subcommands.ExitSuccess
+	}
+
+	if len(docs) == 0 {
+		fmt.Fprintln(os.Stderr, "No documents to delete")
+		return subcommands.ExitSuccess
+	}
+
+	if !c.yes {
+		for _, doc := range docs {
+			fmt.Fprintf(os.Stderr, "  %s  %s\n", doc.ID, tuiTitle(doc))
+		}
+		ok, err := confirm(fmt.Sprintf("Delete %d document(s)?", len(docs)))
+		if err != nil {
+			printError(err)
+			return subcommands.ExitFailure
+		}
+		if !ok {
+			fmt.Fprintln(os.Stderr, "Cancelled")
+			return subcommands.ExitFailure
+		}
+	}
+
+	trash, err := openTrash()
 	if err != nil {
-		printError(fmt.Errorf("failed to delete document: %w", err))
+		printError(err)
 		return subcommands.ExitFailure
 	}
 
-	// Output success confirmation
-	fmt.Printf("Document %s deleted successfully\n", documentID)
+	// Delete the documents, recording them as one unit in the undo journal
+	var changes []journal.Change
+	status := subcommands.ExitSuccess
+	for _, doc := range docs {
+		if query != nil {
+			snapshot, err := journal.Snapshot(ctx, c.client, doc.ID, true)
+			if err != nil {
+				printError(err)
+				status = subcommands.ExitFailure
+				break
+			}
+			doc = *snapshot
+		}
+
+		if err := trashAndDelete(ctx, c.client, trash, doc); err != nil {
+			printError(err)
+			status = subcommands.ExitFailure
+			break
+		}
+		changes = append(changes, journal.Change{Operation: journal.OperationDelete, DocumentID: doc.ID, Before: &doc})
+
+		// Output success confirmation
+		fmt.Printf("Document %s deleted successfully\n", doc.ID)
+	}
+	recordChanges("reader delete", changes)
+
+	return status
+}
 
-	return subcommands.ExitSuccess
+// trashAndDelete puts a document in the trash and deletes it. The document is
+// taken out of the trash again if it cannot be deleted.
+func trashAndDelete(ctx context.Context, client reader.Client, t *trash.Trash, doc reader.Document) error {
+	if err := t.Put(doc); err != nil {
+		return err
+	}
+	if err := client.DeleteDocument(ctx, doc.ID); err != nil {
+		// The document still exists, so it does not belong in the trash
+		if err := t.Remove(doc.ID); err != nil {
+			logger.Warn("failed to remove document from trash", slog.String("document_id", doc.ID), slog.Any("error", err))
+		}
+		return fmt.Errorf("failed to delete document %s: %w", doc.ID, err)
+	}
+	return nil
 }
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/subcommands"
+	"github.com/tcnksm/go-readwise-reader/cmd/internal/config"
+	"github.com/tcnksm/go-readwise-reader/subscriptions"
+)
+
+type feedsCmd struct{}
+
+func (*feedsCmd) Name() string { return "feeds" }
+func (*feedsCmd) Synopsis() string {
+	return "Import feed subscriptions and save their new entries"
+}
+func (*feedsCmd) Usage() string {
+	return `feeds <subcommand> [flags]:
+  Subscribe to RSS and Atom feeds and save their new entries to the Feed
+  location. The subscriptions and the entries seen in each feed are kept in
+  $XDG_DATA_HOME/reader/feeds.json.
+
+Subcommands:
+  import  Subscribe to the feeds of an OPML file and poll them
+  poll    Save the new entries of the subscribed feeds
+`
+}
+func (*feedsCmd) SetFlags(f *flag.FlagSet) {}
+
+func (c *feedsCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	return executeSubcommands(ctx, f, "reader feeds", &feedsImportCmd{}, &feedsPollCmd{})
+}
+
+// feedsFlags are the flags shared by the feeds subcommands
+type feedsFlags struct {
+	baseCommand
+	state      string
+	maxEntries int
+	dryRun     bool
+	timeout    time.Duration
+}
+
+const feedsFlagsUsage = `Flags:
+  -state        Path to the feed state. Default: $XDG_DATA_HOME/reader/feeds.json
+  -max-entries  Maximum number of new entries saved per feed. Default: 10
+  -dry-run      Only show which entries would be saved
+  -timeout      Timeout for fetching a feed. Default: 30s
+`
+
+func (c *feedsFlags) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&c.state, "state", "", "Path to the feed state")
+	f.IntVar(&c.maxEntries, "max-entries", subscriptions.DefaultMaxEntries, "Maximum number of new entries saved per feed")
+	f.BoolVar(&c.dryRun, "dry-run", false, "Only show which entries would be saved")
+	f.DurationVar(&c.timeout, "timeout", 30*time.Second, "Timeout for fetching a feed")
+}
+
+// poll polls the subscriptions, saves the state and prints the results. It
+// fails if any feed failed, after polling the others.
+func (c *feedsFlags) poll(ctx context.Context, state *subscriptions.State, subs []subscriptions.Subscription) subcommands.ExitStatus {
+	// Initialize client
+	if err := c.initClient(ctx); err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+
+	im := &subscriptions.Importer{
+		Client:     c.client,
+		HTTPClient: &http.Client{Timeout: c.timeout},
+		State:      state,
+		MaxEntries: c.maxEntries,
+		DryRun:     c.dryRun,
+	}
+	results := im.Poll(ctx, subs)
+	if !c.dryRun {
+		if err := state.Save(); err != nil {
+			printError(err)
+			return subcommands.ExitFailure
+		}
+	}
+	if err := printJSON(results); err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+
+	var errs []error
+	for _, r := range results {
+		if r.Error != "" {
+			errs = append(errs, fmt.Errorf("%s: %s", r.Feed, r.Error))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+	return subcommands.ExitSuccess
+}
+
+// loadFeedState loads the feed state at path, or in the data directory if
+// path is empty
+func loadFeedState(path string) (*subscriptions.State, error) {
+	if path == "" {
+		dir, err := config.DataDir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(dir, "feeds.json")
+	}
+	return subscriptions.LoadState(path)
+}
+
+type feedsImportCmd struct {
+	feedsFlags
+}
+
+func (*feedsImportCmd) Name() string { return "import" }
+func (*feedsImportCmd) Synopsis() string {
+	return "Subscribe to the feeds of an OPML file and poll them"
+}
+func (*feedsImportCmd) Usage() string {
+	return `import [flags] <file.opml>:
+  Subscribe to the feeds listed in an OPML file, as exported by most feed
+  readers, and poll them. Feeds already subscribed keep their state. Only the
+  most recent entries of a new feed are saved, so that its whole history is
+  not imported. Outputs the saved entries by feed as pretty-printed JSON.
+
+` + feedsFlagsUsage
+}
+
+func (c *feedsImportCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	if f.NArg() != 1 || c.maxEntries < 0 {
+		fmt.Fprintf(os.Stderr, "Usage: %s\n", c.Usage())
+		return subcommands.ExitUsageError
+	}
+
+	file, err := os.Open(f.Arg(0))
+	if err != nil {
+		printError(fmt.Errorf("failed to open OPML file: %w", err))
+		return subcommands.ExitFailure
+	}
+	defer file.Close()
+	subs, err := subscriptions.ParseOPML(file)
+	if err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+
+	state, err := loadFeedState(c.state)
+	if err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+	return c.poll(ctx, state, subs)
+}
+
+type feedsPollCmd struct {
+	feedsFlags
+}
+
+func (*feedsPollCmd) Name() string { return "poll" }
+func (*feedsPollCmd) Synopsis() string {
+	return "Save the new entries of the subscribed feeds"
+}
+func (*feedsPollCmd) Usage() string {
+	return `poll [flags]:
+  Fetch the subscribed feeds and save their new entries to the Feed location,
+  oldest first. Feeds that did not change since the last poll are not
+  downloaded again. Meant to be run on a schedule, e.g. from cron. Outputs the
+  saved entries by feed as pretty-printed JSON, and fails if any feed failed.
+
+` + feedsFlagsUsage
+}
+
+func (c *feedsPollCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	if f.NArg() != 0 || c.maxEntries < 0 {
+		fmt.Fprintf(os.Stderr, "Usage: %s\n", c.Usage())
+		return subcommands.ExitUsageError
+	}
+
+	state, err := loadFeedState(c.state)
+	if err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+	subs := state.Subscriptions()
+	if len(subs) == 0 {
+		printError(errors.New("no subscribed feeds, run reader feeds import first"))
+		return subcommands.ExitFailure
+	}
+	return c.poll(ctx, state, subs)
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/subcommands"
+	"github.com/tcnksm/go-readwise-reader/cmd/internal/config"
+	"github.com/tcnksm/go-readwise-reader/gc"
+	"github.com/tcnksm/go-readwise-reader/journal"
+	"gopkg.in/yaml.v3"
+)
+
+// gcReportTop is the number of sites and categories listed per policy in
+// the report
+const gcReportTop = 5
+
+type gcCmd struct {
+	baseCommand
+	policies string
+	dryRun   bool
+	yes      bool
+	format   string
+}
+
+func (*gcCmd) Name() string { return "gc" }
+func (*gcCmd) Synopsis() string {
+	return "Archive documents by queue hygiene policies"
+}
+func (*gcCmd) Usage() string {
+	return `gc -policies <file> [flags]:
+  Move the documents selected by the policies of a YAML file, e.g. to archive
+  feed documents never opened or cap the inbox. A report of the documents
+  each policy selects, counted by site and category, is shown first and the
+  moves must be confirmed, unless -yes is given. Outputs the results as
+  pretty-printed JSON. The moves are recorded in the undo journal as one
+  change (see reader undo).
+
+Flags:
+  -policies  Path to the YAML policies file (required)
+  -dry-run   Only print the report
+  -yes       Do not ask for confirmation
+  -format    Format of the report printed by -dry-run: table or json. Default: table
+`
+}
+func (c *gcCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&c.policies, "policies", "", "Path to the YAML policies file (required)")
+	f.BoolVar(&c.dryRun, "dry-run", false, "Only print the report")
+	f.BoolVar(&c.yes, "yes", false, "Do not ask for confirmation")
+	f.StringVar(&c.format, "format", "table", "Format of the report printed by -dry-run: table or json")
+}
+
+func (c *gcCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	if c.policies == "" || f.NArg() != 0 || (c.format != "table" && c.format != "json") {
+		fmt.Fprintf(os.Stderr, "Usage: %s\n", c.Usage())
+		return subcommands.ExitUsageError
+	}
+	if !c.yes && !c.dryRun && !stdinIsTerminal() {
+		printError(fmt.Errorf("refusing to move documents without confirmation: use -yes or -dry-run when not running in a terminal"))
+		return subcommands.ExitUsageError
+	}
+
+	cfg, err := loadPolicies(c.policies)
+	if err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+
+	// Initialize client
+	if err := c.initClient(ctx); err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+
+	plan, err := cfg.Plan(ctx, c.client, time.Now())
+	if err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+	report := plan.Report()
+
+	if c.dryRun {
+		if c.format == "json" {
+			err = printJSON(report)
+		} else {
+			err = writeGCReport(os.Stdout, report)
+		}
+		if err != nil {
+			printError(err)
+			return subcommands.ExitFailure
+		}
+		return subcommands.ExitSuccess
+	}
+
+	if len(plan.Moves) == 0 {
+		fmt.Fprintln(os.Stderr, "No documents to move")
+		return subcommands.ExitSuccess
+	}
+
+	if !c.yes {
+		if err := writeGCReport(os.Stderr, report); err != nil {
+			printError(err)
+			return subcommands.ExitFailure
+		}
+		ok, err := confirm(fmt.Sprintf("Move %d document(s)?", len(plan.Moves)))
+		if err != nil {
+			printError(err)
+			return subcommands.ExitFailure
+		}
+		if !ok {
+			fmt.Fprintln(os.Stderr, "Cancelled")
+			return subcommands.ExitFailure
+		}
+	}
+
+	path, err := config.JournalPath()
+	if err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+	results, applyErr := gc.Apply(ctx, c.client, plan, journal.Open(path))
+	if err := printJSON(results); err != nil {
+		printError(fmt.Errorf("failed to output JSON: %w", err))
+		return subcommands.ExitFailure
+	}
+	if applyErr != nil {
+		printError(applyErr)
+		return subcommands.ExitFailure
+	}
+	return subcommands.ExitSuccess
+}
+
+// loadPolicies reads and compiles the YAML policies file at path
+func loadPolicies(path string) (*gc.Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policies: %w", err)
+	}
+
+	var cfg gc.Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse policies: %w", err)
+	}
+	if err := cfg.Compile(); err != nil {
+		return nil, fmt.Errorf("invalid policies: %w", err)
+	}
+
+	return &cfg, nil
+}
+
+// writeGCReport writes the report with the top sites and categories of
+// each policy
+func writeGCReport(w io.Writer, report *gc.Report) error {
+	for _, p := range report.Policies {
+		fmt.Fprintf(w, "%s: %d document(s) to %s\n", p.Policy, p.Documents, p.To)
+		if p.Documents == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "  Sites:       %s\n", formatGCCounts(p.Sites))
+		fmt.Fprintf(w, "  Categories:  %s\n", formatGCCounts(p.Categories))
+	}
+	_, err := fmt.Fprintf(w, "Total: %d document(s)\n", report.Documents)
+	return err
+}
+
+// formatGCCounts formats the top counts as "name (n), ..."
+func formatGCCounts(counts []gc.Count) string {
+	var parts []string
+	for i, count := range counts {
+		if i == gcReportTop {
+			parts = append(parts, fmt.Sprintf("%d more", len(counts)-i))
+			break
+		}
+		parts = append(parts, fmt.Sprintf("%s (%d)", count.Name, count.Documents))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/google/subcommands"
+	reader "github.com/tcnksm/go-readwise-reader"
+)
+
+type getCmd struct {
+	baseCommand
+	html     bool
+	children bool
+}
+
+func (*getCmd) Name() string { return "get" }
+func (*getCmd) Synopsis() string {
+	return "Get a document by ID"
+}
+func (*getCmd) Usage() string {
+	return `get [flags] <document-id>:
+  Get a document by ID. Output is the pretty-printed JSON document.
+  Exits with status 1 and a "document not found" error if it does not exist.
+
+Flags:
+  -html      Include HTML content in the response
+  -children  Include the highlights and notes of the document as "children".
+             This reads every highlight and note, so it can be slow.
+`
+}
+func (c *getCmd) SetFlags(f *flag.FlagSet) {
+	f.BoolVar(&c.html, "html", false, "Include HTML content in the response")
+	f.BoolVar(&c.children, "children", false, "Include the highlights and notes of the document")
+}
+
+// getOutput is a document with its highlights and notes
+type getOutput struct {
+	*reader.Document
+	Children []reader.Document `json:"children,omitempty"`
+}
+
+func (c *getCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	// Parse document ID from args
+	args := f.Args()
+	if len(args) != 1 {
+		fmt.Fprintf(os.Stderr, "Usage: %s\n", c.Usage())
+		return subcommands.ExitUsageError
+	}
+	documentID := args[0]
+
+	// Initialize client
+	if err := c.initClient(ctx); err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+
+	doc, err := reader.GetDocument(ctx, c.client, documentID, c.html)
+	if err != nil {
+		if !errors.Is(err, reader.ErrNotFound) {
+			err = fmt.Errorf("failed to get document: %w", err)
+		}
+		printError(err)
+		return subcommands.ExitFailure
+	}
+
+	output := getOutput{Document: doc}
+	if c.children {
+		if output.Children, err = reader.Children(ctx, c.client, documentID); err != nil {
+			printError(fmt.Errorf("failed to list children: %w", err))
+			return subcommands.ExitFailure
+		}
+	}
+
+	if err := printJSON(output); err != nil {
+		printError(fmt.Errorf("failed to output JSON: %w", err))
+		return subcommands.ExitFailure
+	}
+	return subcommands.ExitSuccess
+}
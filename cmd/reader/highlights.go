@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/google/subcommands"
+	reader "github.com/tcnksm/go-readwise-reader"
+	"github.com/tcnksm/go-readwise-reader/markdown"
+)
+
+type highlightsCmd struct {
+	baseCommand
+	since  string
+	format string
+	inline bool
+}
+
+func (*highlightsCmd) Name() string { return "highlights" }
+func (*highlightsCmd) Synopsis() string {
+	return "Show highlights of a document or a highlights review"
+}
+func (*highlightsCmd) Usage() string {
+	return `highlights [flags] [document-id]:
+  Show the highlights and notes of a document, or without a document ID, a
+  review of recent highlights grouped by document (by default from the last
+  24 hours, for a daily review).
+
+Flags:
+  -since   Only include highlights updated since duration ago (e.g., 24h, 7d, 2w)
+  -format  Output format (markdown, json). Default: markdown
+  -inline  Export the document content as Markdown with its highlights marked
+           inline where they are found in the text. Requires a document ID
+`
+}
+func (c *highlightsCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&c.since, "since", "", "Only include highlights updated since duration ago (e.g., 24h, 7d, 2w)")
+	f.StringVar(&c.format, "format", "markdown", "Output format (markdown, json)")
+	f.BoolVar(&c.inline, "inline", false, "Export the document content with its highlights marked inline")
+}
+
+func (c *highlightsCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	args := f.Args()
+	if len(args) > 1 || (c.inline && len(args) == 0) {
+		fmt.Fprintf(os.Stderr, "Usage: %s\n", c.Usage())
+		return subcommands.ExitUsageError
+	}
+	if c.format != "markdown" && c.format != "json" {
+		printError(fmt.Errorf("invalid format: %s. Valid values: markdown, json", c.format))
+		return subcommands.ExitUsageError
+	}
+
+	since := c.since
+	if since == "" && len(args) == 0 {
+		since = "24h"
+	}
+	var updatedAfter *time.Time
+	if since != "" {
+		duration, err := parseDuration(since)
+		if err != nil {
+			printError(fmt.Errorf("invalid duration format: %s. Use formats like 24h, 7d, 2w", since))
+			return subcommands.ExitUsageError
+		}
+		t := time.Now().Add(-duration)
+		updatedAfter = &t
+	}
+
+	// Initialize client
+	if err := c.initClient(ctx); err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+
+	highlights, err := reader.ListHighlights(ctx, c.client, updatedAfter)
+	if err != nil {
+		printError(fmt.Errorf("failed to list highlights: %w", err))
+		return subcommands.ExitFailure
+	}
+	byParent := reader.GroupByParent(highlights)
+
+	// Group highlights by document, in the order of their first highlight
+	var parentIDs []string
+	if len(args) == 1 {
+		parentIDs = args
+	} else {
+		seen := make(map[string]bool)
+		for _, h := range highlights {
+			if h.ParentID != "" && !seen[h.ParentID] {
+				seen[h.ParentID] = true
+				parentIDs = append(parentIDs, h.ParentID)
+			}
+		}
+	}
+	groups := make([]markdown.Group, 0, len(parentIDs))
+	for _, id := range parentIDs {
+		doc, err := reader.GetDocument(ctx, c.client, id, c.inline)
+		if err != nil {
+			if len(args) == 1 || !errors.Is(err, reader.ErrNotFound) {
+				printError(fmt.Errorf("failed to get document: %w", err))
+				return subcommands.ExitFailure
+			}
+			// Keep highlights of deleted documents in the review
+			logger.Warn("document of highlights not found", slog.String("document_id", id))
+			doc = &reader.Document{ID: id, Title: id}
+		}
+		groups = append(groups, markdown.Group{Document: *doc, Highlights: byParent[id]})
+	}
+
+	if c.format == "json" {
+		var output interface{} = groups
+		if len(args) == 1 {
+			output = groups[0]
+		}
+		if err := printJSON(output); err != nil {
+			printError(fmt.Errorf("failed to output JSON: %w", err))
+			return subcommands.ExitFailure
+		}
+		return subcommands.ExitSuccess
+	}
+
+	switch {
+	case c.inline:
+		fmt.Print(markdown.Document(groups[0].Document, groups[0].Highlights))
+	case len(args) == 1:
+		fmt.Print(markdown.Review("Highlights", groups))
+	case updatedAfter != nil:
+		fmt.Print(markdown.Review("Highlights since "+updatedAfter.Format("2006-01-02 15:04"), groups))
+	}
+	return subcommands.ExitSuccess
+}
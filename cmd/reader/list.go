@@ -13,14 +13,19 @@ import (
 type listCmd struct {
 	baseCommand
 	id       string
-	location string
-	category string
+	location reader.Location
+	category reader.Category
 	tag      string
 	since    string
 	html     bool
 	unread   bool
+	progress string
 }
 
+// listPageSize is the number of documents the API returns per page, which list
+// also returns when filtering on the client side
+const listPageSize = 100
+
 func (*listCmd) Name() string { return "list" }
 func (*listCmd) Synopsis() string {
 	return "List documents with optional filtering"
@@ -32,22 +37,28 @@ func (*listCmd) Usage() string {
 
 Flags:
   -id         Filter by document ID. Using this parameter it will return just one document, if found.	
-  -location   Filter by location (new, later, archive, feed). Default: new
-  -category   Filter by category (article, email, rss, pdf, epub, tweet, video, highlight)
+  -location   Filter by location (new, later, archive, feed). Default: new, or the default location of the profile
+  -category   Filter by category (article, email, rss, pdf, epub, tweet, video, highlight, note)
   -tag        Filter by tag name
   -since      Filter documents updated since duration ago (e.g., 10s, 30m, 24h)
   -html       Include HTML content in the response
   -unread     Only return unread documents
+  -progress   Filter by reading progress (unstarted, in_progress, finished)
+
+  -unread and -progress are applied on the client side, reading further pages
+  until up to 100 matching documents are found.
 `
 }
 func (c *listCmd) SetFlags(f *flag.FlagSet) {
 	f.StringVar(&c.id, "id", "", "Filter by document ID. Using this parameter it will return just one document, if found.")
-	f.StringVar(&c.location, "location", "new", "Filter by location (new, later, archive, feed)")
-	f.StringVar(&c.category, "category", "", "Filter by category (article, email, rss, pdf, epub, tweet, video, highlight)")
+	c.location = defaultLocation(reader.LocationNew)
+	f.Var(&c.location, "location", "Filter by location (new, later, archive, feed)")
+	f.Var(&c.category, "category", "Filter by category (article, email, rss, pdf, epub, tweet, video, highlight, note)")
 	f.StringVar(&c.tag, "tag", "", "Filter by tag name")
 	f.StringVar(&c.since, "since", "", "Filter documents updated since duration ago (e.g., 10s, 30m, 24h)")
 	f.BoolVar(&c.html, "html", false, "Include HTML content in the response")
 	f.BoolVar(&c.unread, "unread", false, "Only return unread documents")
+	f.StringVar(&c.progress, "progress", "", "Filter by reading progress (unstarted, in_progress, finished)")
 }
 
 func (c *listCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
@@ -57,44 +68,12 @@ func (c *listCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}
 		return subcommands.ExitFailure
 	}
 
-	// Validate location
-	var location reader.Location
-	switch c.location {
-	case "new":
-		location = reader.LocationNew
-	case "later":
-		location = reader.LocationLater
-	case "archive":
-		location = reader.LocationArchive
-	case "feed":
-		location = reader.LocationFeed
-	default:
-		printError(fmt.Errorf("invalid location: %s. Valid values: new, later, archive, feed", c.location))
-		return subcommands.ExitUsageError
-	}
-
-	// Validate category
-	var category reader.Category
-	if c.category != "" {
-		switch c.category {
-		case "article":
-			category = reader.CategoryArticle
-		case "email":
-			category = reader.CategoryEmail
-		case "rss":
-			category = reader.CategoryRSS
-		case "pdf":
-			category = reader.CategoryPDF
-		case "epub":
-			category = reader.CategoryEPUB
-		case "tweet":
-			category = reader.CategoryTweet
-		case "video":
-			category = reader.CategoryVideo
-		case "highlight":
-			category = reader.CategoryHighlight
-		default:
-			printError(fmt.Errorf("invalid category: %s. Valid values: article, email, rss, pdf, epub, tweet, video, highlight", c.category))
+	// Validate progress
+	var progress reader.Progress
+	if c.progress != "" {
+		var err error
+		if progress, err = reader.ParseProgress(c.progress); err != nil {
+			printError(err)
 			return subcommands.ExitUsageError
 		}
 	}
@@ -116,35 +95,40 @@ func (c *listCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}
 	// Set up options for ListDocuments
 	opts := &reader.ListDocumentsOptions{
 		ID:              c.id,
-		Location:        location,
-		Category:        category,
+		Location:        c.location,
+		Category:        c.category,
 		Tag:             c.tag,
 		UpdatedAfter:    updatedAfter,
 		WithHTMLContent: c.html,
 	}
 
-	// Call ListDocuments API
-	response, err := c.client.ListDocuments(ctx, opts)
-	if err != nil {
-		printError(fmt.Errorf("failed to list documents: %w", err))
-		return subcommands.ExitFailure
-	}
-
-	// Filter unread documents if requested
-	if c.unread {
-		results := make([]reader.Document, 0, len(response.Results))
-		for _, doc := range response.Results {
-			if doc.FirstOpenedAt != nil {
-				continue
+	// Call ListDocuments API. The API cannot filter unread documents or
+	// progress, so filter the pages here until a page worth of matches is found.
+	var documents []reader.Document
+	if c.unread || progress != "" {
+		match := func(doc reader.Document) bool {
+			if c.unread && doc.Seen() {
+				return false
 			}
-			results = append(results, doc)
+			return progress == "" || doc.Progress() == progress
+		}
+		var err error
+		if documents, err = reader.FindDocuments(ctx, c.client, opts, match, listPageSize); err != nil {
+			printError(fmt.Errorf("failed to list documents: %w", err))
+			return subcommands.ExitFailure
+		}
+	} else {
+		response, err := c.client.ListDocuments(ctx, opts)
+		if err != nil {
+			printError(fmt.Errorf("failed to list documents: %w", err))
+			return subcommands.ExitFailure
 		}
-		response.Results = results
+		documents = response.Results
 	}
 
 	// Output documents as pretty JSON
 	// TODO: Handle pagination in future implementation
-	if err := printJSON(response.Results); err != nil {
+	if err := printJSON(documents); err != nil {
 		printError(fmt.Errorf("failed to output JSON: %w", err))
 		return subcommands.ExitFailure
 	}
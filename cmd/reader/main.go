@@ -6,6 +6,15 @@ import (
 	"os"
 
 	"github.com/google/subcommands"
+	"github.com/tcnksm/go-readwise-reader/cmd/internal/config"
+)
+
+var (
+	verbose     = flag.Bool("v", false, "Log progress to stderr")
+	debug       = flag.Bool("debug", false, "Log API requests and responses to stderr")
+	logFormat   = flag.String("log-format", "text", "Log output format (text, json)")
+	configPath  = flag.String("config", "", "Path to the configuration file. Default: ~/.config/reader/config.toml")
+	profileName = flag.String("profile", "", "Configuration profile to use. Default: $READER_PROFILE or the default profile")
 )
 
 func main() {
@@ -13,12 +22,57 @@ func main() {
 	subcommands.Register(subcommands.FlagsCommand(), "")
 	subcommands.Register(subcommands.CommandsCommand(), "")
 
-	subcommands.Register(&listCmd{}, "")
-	subcommands.Register(&createCmd{}, "")
-	subcommands.Register(&updateCmd{}, "")
-	subcommands.Register(&deleteCmd{}, "")
+	for _, cmd := range []subcommands.Command{
+		&listCmd{},
+		&getCmd{},
+		&highlightsCmd{},
+		&createCmd{},
+		&updateCmd{},
+		&deleteCmd{},
+		&dedupeCmd{},
+		&rulesCmd{},
+		&webhookCmd{},
+		&statsCmd{},
+		&authCmd{},
+		&tuiCmd{},
+		&undoCmd{},
+		&historyCmd{},
+		&trashCmd{},
+		&markCmd{},
+		&noteCmd{},
+		&watchCmd{},
+		&serveFeedCmd{},
+		&feedsCmd{},
+		&cronCmd{},
+		&gcCmd{},
+	} {
+		subcommands.Register(loggedCommand{cmd}, "")
+	}
+
+	subcommands.ImportantFlag("v")
+	subcommands.ImportantFlag("debug")
+	subcommands.ImportantFlag("log-format")
+	subcommands.ImportantFlag("profile")
 
 	flag.Parse()
+
+	var err error
+	if logger, err = newLogger(os.Stderr, *logFormat, *verbose, *debug); err != nil {
+		printError(err)
+		os.Exit(int(subcommands.ExitUsageError))
+	}
+
+	if *configPath == "" {
+		if *configPath, err = config.DefaultPath(); err != nil {
+			printError(err)
+			os.Exit(int(subcommands.ExitFailure))
+		}
+	}
+	if cfg, err = config.Load(*configPath); err != nil {
+		printError(err)
+		os.Exit(int(subcommands.ExitFailure))
+	}
+
 	ctx := context.Background()
 	os.Exit(int(subcommands.Execute(ctx)))
 }
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/google/subcommands"
+	reader "github.com/tcnksm/go-readwise-reader"
+	"github.com/tcnksm/go-readwise-reader/journal"
+)
+
+type markCmd struct{}
+
+func (*markCmd) Name() string { return "mark" }
+func (*markCmd) Synopsis() string {
+	return "Mark documents as seen or unseen"
+}
+func (*markCmd) Usage() string {
+	return `mark <subcommand> <document-id>...:
+  Mark documents as seen or unseen. Marking a document as unseen clears the
+  times it was first and last opened. The previous state of the documents is
+  recorded in the undo journal (see reader undo).
+
+Subcommands:
+  seen    Mark documents as seen
+  unseen  Mark documents as unseen
+`
+}
+func (*markCmd) SetFlags(f *flag.FlagSet) {}
+
+func (c *markCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	return executeSubcommands(ctx, f, "reader mark", &markSeenCmd{seen: true}, &markSeenCmd{seen: false})
+}
+
+// markSeenCmd marks documents as seen, or as unseen when seen is false
+type markSeenCmd struct {
+	baseCommand
+	seen bool
+}
+
+func (c *markSeenCmd) Name() string {
+	if c.seen {
+		return "seen"
+	}
+	return "unseen"
+}
+func (c *markSeenCmd) Synopsis() string {
+	return fmt.Sprintf("Mark documents as %s", c.Name())
+}
+func (c *markSeenCmd) Usage() string {
+	return fmt.Sprintf(`%s <document-id>...:
+  Mark documents as %s. Returns the updated documents as pretty-printed JSON.
+`, c.Name(), c.Name())
+}
+func (*markSeenCmd) SetFlags(f *flag.FlagSet) {}
+
+func (c *markSeenCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	args := f.Args()
+	if len(args) == 0 {
+		fmt.Fprintf(os.Stderr, "Usage: %s\n", c.Usage())
+		return subcommands.ExitUsageError
+	}
+
+	// Initialize client
+	if err := c.initClient(ctx); err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+
+	mark := reader.MarkUnseen
+	if c.seen {
+		mark = reader.MarkSeen
+	}
+
+	var responses []*reader.UpdateDocumentResponse
+	var changes []journal.Change
+	status := subcommands.ExitSuccess
+	for _, documentID := range args {
+		before, err := journal.Snapshot(ctx, c.client, documentID, false)
+		if err != nil {
+			printError(err)
+			status = subcommands.ExitFailure
+			break
+		}
+
+		response, err := mark(ctx, c.client, documentID)
+		if err != nil {
+			printError(fmt.Errorf("failed to mark document %s as %s: %w", documentID, c.Name(), err))
+			status = subcommands.ExitFailure
+			break
+		}
+		responses = append(responses, response)
+		changes = append(changes, journal.Change{Operation: journal.OperationUpdate, DocumentID: documentID, Before: before})
+	}
+	recordChanges("reader mark "+c.Name(), changes)
+
+	if len(responses) > 0 {
+		if err := printJSON(responses); err != nil {
+			printError(fmt.Errorf("failed to output JSON: %w", err))
+			return subcommands.ExitFailure
+		}
+	}
+	return status
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/google/subcommands"
+	reader "github.com/tcnksm/go-readwise-reader"
+	"github.com/tcnksm/go-readwise-reader/journal"
+)
+
+type noteCmd struct {
+	baseCommand
+	append    string
+	timestamp bool
+}
+
+func (*noteCmd) Name() string { return "note" }
+func (*noteCmd) Synopsis() string {
+	return "Edit or append to the notes of a document"
+}
+func (*noteCmd) Usage() string {
+	return `note [flags] <document-id>:
+  Open the notes of a document in $VISUAL or $EDITOR (default: vi) and save
+  them when the editor exits, or append text to the notes with -append.
+  Flags may also follow the document ID. The previous notes are recorded in
+  the undo journal (see reader undo).
+  Returns the updated document as pretty-printed JSON.
+
+Flags:
+  -append     Append text to the notes as a new paragraph instead of editing
+  -timestamp  Prefix the appended text with the current date and time, to
+              keep the notes as a reading journal
+`
+}
+func (c *noteCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&c.append, "append", "", "Append text to the notes as a new paragraph instead of editing")
+	f.BoolVar(&c.timestamp, "timestamp", false, "Prefix the appended text with the current date and time")
+}
+
+func (c *noteCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	// Parse the document ID, allowing flags after it as in
+	// "reader note <id> -append text"
+	if f.NArg() == 0 {
+		fmt.Fprintf(os.Stderr, "Usage: %s\n", c.Usage())
+		return subcommands.ExitUsageError
+	}
+	documentID := f.Arg(0)
+	if err := f.Parse(f.Args()[1:]); err != nil || f.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "Usage: %s\n", c.Usage())
+		return subcommands.ExitUsageError
+	}
+	if c.timestamp && c.append == "" {
+		printError(fmt.Errorf("-timestamp requires -append"))
+		return subcommands.ExitUsageError
+	}
+
+	// Initialize client
+	if err := c.initClient(ctx); err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+
+	before, err := journal.Snapshot(ctx, c.client, documentID, false)
+	if err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+
+	var notes string
+	if c.append != "" {
+		note := c.append
+		if c.timestamp {
+			note = reader.TimestampNote(time.Now(), note)
+		}
+		notes = reader.AppendNote(before.Notes, note)
+	} else {
+		if notes, err = editNotes(ctx, before.Notes); err != nil {
+			printError(err)
+			return subcommands.ExitFailure
+		}
+		if notes == strings.TrimSpace(before.Notes) {
+			fmt.Fprintln(os.Stderr, "Notes unchanged")
+			return subcommands.ExitSuccess
+		}
+	}
+	req := &reader.UpdateDocumentRequest{Notes: notes}
+	if notes == "" {
+		req.Clear = []reader.UpdateField{reader.UpdateFieldNotes}
+	}
+	response, err := c.client.UpdateDocument(ctx, documentID, req)
+	if err != nil {
+		printError(fmt.Errorf("failed to update notes of document %s: %w", documentID, err))
+		return subcommands.ExitFailure
+	}
+	recordChanges("reader note", []journal.Change{{Operation: journal.OperationUpdate, DocumentID: documentID, Before: before}})
+
+	if err := printJSON(response); err != nil {
+		printError(fmt.Errorf("failed to output JSON: %w", err))
+		return subcommands.ExitFailure
+	}
+	return subcommands.ExitSuccess
+}
+
+// editNotes opens notes in the user's editor and returns the edited notes
+// without surrounding whitespace
+func editNotes(ctx context.Context, notes string) (string, error) {
+	file, err := os.CreateTemp("", "reader-note-*.md")
+	if err != nil {
+		return "", fmt.Errorf("failed to create notes file: %w", err)
+	}
+	defer os.Remove(file.Name())
+	if notes != "" {
+		notes = strings.TrimSpace(notes) + "\n"
+	}
+	if _, err := file.WriteString(notes); err != nil {
+		file.Close()
+		return "", fmt.Errorf("failed to write notes file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return "", fmt.Errorf("failed to write notes file: %w", err)
+	}
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	// Run the editor through the shell since it may include arguments, as in "code -w"
+	cmd := exec.CommandContext(ctx, "sh", "-c", editor+` "$1"`, "sh", file.Name())
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("editor failed: %w", err)
+	}
+
+	edited, err := os.ReadFile(file.Name())
+	if err != nil {
+		return "", fmt.Errorf("failed to read notes file: %w", err)
+	}
+	return strings.TrimSpace(string(edited)), nil
+}
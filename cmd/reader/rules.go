@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/subcommands"
+	reader "github.com/tcnksm/go-readwise-reader"
+	"github.com/tcnksm/go-readwise-reader/cmd/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+type rulesCmd struct{}
+
+func (*rulesCmd) Name() string { return "rules" }
+func (*rulesCmd) Synopsis() string {
+	return "Triage documents with declarative rules"
+}
+func (*rulesCmd) Usage() string {
+	return `rules <subcommand> [flags]:
+  Triage documents with declarative rules defined in a YAML file.
+
+Subcommands:
+  run    Evaluate rules against recently updated documents and apply them
+
+Rules file example:
+  rules:
+    - name: archive short rss
+      when:
+        category: rss
+        site_name: "^Hacker News$"
+        word_count_lt: 300
+      then:
+        location: archive
+    - name: standards
+      when:
+        title_contains: RFC
+      then:
+        add_tags: [standards]
+        location: later
+`
+}
+func (*rulesCmd) SetFlags(f *flag.FlagSet) {}
+
+func (c *rulesCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	return executeSubcommands(ctx, f, "reader rules", &rulesRunCmd{})
+}
+
+type rulesRunCmd struct {
+	baseCommand
+	rules    string
+	since    string
+	interval time.Duration
+	location reader.Location
+	dryRun   bool
+	auditLog string
+}
+
+func (*rulesRunCmd) Name() string { return "run" }
+func (*rulesRunCmd) Synopsis() string {
+	return "Evaluate rules against recently updated documents and apply them"
+}
+func (*rulesRunCmd) Usage() string {
+	return `run -rules <file> [flags]:
+  Evaluate rules against documents updated since the given duration ago and
+  apply the resulting updates. Results are printed as pretty-printed JSON and
+  appended to the audit log.
+
+Flags:
+  -rules      Path to the YAML rules file (required)
+  -since      Evaluate documents updated since duration ago (e.g., 30m, 24h, 7d). Default: 24h
+  -interval   Keep polling for updated documents at this interval (e.g., 5m). Default: run once
+  -location   Only evaluate documents in this location (new, later, archive, feed)
+  -dry-run    Only show which rules would fire on which documents
+  -audit-log  Path to the audit log. Default: $XDG_DATA_HOME/reader/rules-audit.jsonl
+`
+}
+func (c *rulesRunCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&c.rules, "rules", "", "Path to the YAML rules file (required)")
+	f.StringVar(&c.since, "since", "24h", "Evaluate documents updated since duration ago (e.g., 30m, 24h, 7d)")
+	f.DurationVar(&c.interval, "interval", 0, "Keep polling for updated documents at this interval (e.g., 5m)")
+	f.Var(&c.location, "location", "Only evaluate documents in this location (new, later, archive, feed)")
+	f.BoolVar(&c.dryRun, "dry-run", false, "Only show which rules would fire on which documents")
+	f.StringVar(&c.auditLog, "audit-log", "", "Path to the audit log")
+}
+
+func (c *rulesRunCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	if c.rules == "" {
+		fmt.Fprintf(os.Stderr, "Usage: %s\n", c.Usage())
+		return subcommands.ExitUsageError
+	}
+
+	duration, err := parseDuration(c.since)
+	if err != nil {
+		printError(fmt.Errorf("invalid duration format: %s. Use formats like 30m, 24h, 7d", c.since))
+		return subcommands.ExitUsageError
+	}
+
+	ruleSet, err := loadRuleSet(c.rules)
+	if err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+
+	auditLog, err := auditLogPath(c.auditLog)
+	if err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+
+	// Initialize client
+	if err := c.initClient(ctx); err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+
+	watermark := time.Now().Add(-duration)
+	for {
+		// Documents updated while listing are picked up again by the next poll;
+		// rules that have already been applied do not fire twice.
+		nextWatermark := time.Now()
+
+		documents, err := reader.ListAllDocuments(ctx, c.client, &reader.ListDocumentsOptions{
+			Location:     c.location,
+			UpdatedAfter: &watermark,
+		})
+		if err != nil {
+			printError(fmt.Errorf("failed to list documents: %w", err))
+			return subcommands.ExitFailure
+		}
+
+		var matches []reader.RuleMatch
+		for _, doc := range documents {
+			matches = append(matches, ruleSet.Evaluate(doc)...)
+		}
+		results := reader.ApplyRuleMatches(ctx, c.client, matches, c.dryRun)
+		logger.InfoContext(ctx, "evaluated rules",
+			slog.Time("updated_after", watermark),
+			slog.Int("documents", len(documents)),
+			slog.Int("matches", len(matches)),
+		)
+
+		if err := appendJSONLines(auditLog, results); err != nil {
+			printError(fmt.Errorf("failed to write audit log: %w", err))
+			return subcommands.ExitFailure
+		}
+		if err := printJSON(results); err != nil {
+			printError(fmt.Errorf("failed to output JSON: %w", err))
+			return subcommands.ExitFailure
+		}
+
+		if c.interval <= 0 {
+			return subcommands.ExitSuccess
+		}
+		watermark = nextWatermark
+
+		select {
+		case <-ctx.Done():
+			return subcommands.ExitSuccess
+		case <-time.After(c.interval):
+		}
+	}
+}
+
+// loadRuleSet reads and compiles the YAML rules file at path
+func loadRuleSet(path string) (*reader.RuleSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules: %w", err)
+	}
+
+	var ruleSet reader.RuleSet
+	if err := yaml.Unmarshal(data, &ruleSet); err != nil {
+		return nil, fmt.Errorf("failed to parse rules: %w", err)
+	}
+	if err := ruleSet.Compile(); err != nil {
+		return nil, fmt.Errorf("invalid rules: %w", err)
+	}
+
+	return &ruleSet, nil
+}
+
+// auditLogPath returns path, or the default audit log path if path is empty
+func auditLogPath(path string) (string, error) {
+	if path != "" {
+		return path, nil
+	}
+	dir, err := config.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "rules-audit.jsonl"), nil
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/google/subcommands"
+	"github.com/tcnksm/go-readwise-reader/feed"
+)
+
+type serveFeedCmd struct {
+	baseCommand
+	addr    string
+	path    string
+	query   string
+	token   string
+	title   string
+	limit   int
+	content bool
+	ttl     time.Duration
+}
+
+func (*serveFeedCmd) Name() string { return "serve-feed" }
+func (*serveFeedCmd) Synopsis() string {
+	return "Publish documents as an RSS, Atom and JSON Feed"
+}
+func (*serveFeedCmd) Usage() string {
+	return `serve-feed [flags]:
+  Run an HTTP server that publishes the documents matching a query as a feed,
+  e.g. to share finished articles tagged "share". The feed is served as RSS
+  at <path> and <path>.rss, as Atom at <path>.atom and as JSON Feed at
+  <path>.json. Items carry the title, URL, author, summary and notes of the
+  documents, most recently updated first.
+
+  Either -q or -token is required, so that the whole library is not
+  published by mistake. With -token, the feed is only served to requests
+  that carry the token, e.g. <path>.atom?token=<token>. The server listens
+  on localhost unless -addr says otherwise.
+
+Flags:
+  -addr     Address to listen on. Default: 127.0.0.1:8081
+  -path     URL path of the feed. Default: /feed
+  -q        Publish the documents matching a query, e.g.
+            'location=archive and tag=share and progress=finished'
+  -token    Token required in the token query parameter. Default: $READWISE_FEED_TOKEN
+  -title    Title of the feed. Default: Readwise Reader
+  -limit    Maximum number of items. Default: 50
+  -content  Include the HTML content of the documents
+  -ttl      How long to serve the documents before listing them again. Default: 15m
+`
+}
+func (c *serveFeedCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&c.addr, "addr", "127.0.0.1:8081", "Address to listen on")
+	f.StringVar(&c.path, "path", "/feed", "URL path of the feed")
+	f.StringVar(&c.query, "q", "", "Publish the documents matching a query, e.g. 'location=archive and tag=share'")
+	f.StringVar(&c.token, "token", os.Getenv("READWISE_FEED_TOKEN"), "Token required in the token query parameter")
+	f.StringVar(&c.title, "title", "Readwise Reader", "Title of the feed")
+	f.IntVar(&c.limit, "limit", 50, "Maximum number of items")
+	f.BoolVar(&c.content, "content", false, "Include the HTML content of the documents")
+	f.DurationVar(&c.ttl, "ttl", 15*time.Minute, "How long to serve the documents before listing them again")
+}
+
+func (c *serveFeedCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	if f.NArg() != 0 || c.limit < 0 || (c.query == "" && c.token == "") {
+		fmt.Fprintf(os.Stderr, "Usage: %s\n", c.Usage())
+		return subcommands.ExitUsageError
+	}
+
+	// Initialize client
+	if err := c.initClient(ctx); err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+
+	handler, err := feed.NewHandler(c.client, feed.HandlerConfig{
+		Title:       c.title,
+		Query:       c.query,
+		Token:       c.token,
+		Limit:       c.limit,
+		WithContent: c.content,
+		TTL:         c.ttl,
+	})
+	if err != nil {
+		printError(err)
+		return subcommands.ExitUsageError
+	}
+
+	mux := http.NewServeMux()
+	for _, ext := range []string{"", ".rss", ".xml", ".atom", ".json"} {
+		mux.Handle(c.path+ext, handler)
+	}
+
+	logger.Info("serving feed", slog.String("addr", c.addr), slog.String("path", c.path))
+	if err := http.ListenAndServe(c.addr, mux); err != nil {
+		printError(fmt.Errorf("server error: %w", err))
+		return subcommands.ExitFailure
+	}
+
+	return subcommands.ExitSuccess
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/subcommands"
+	reader "github.com/tcnksm/go-readwise-reader"
+	"github.com/tcnksm/go-readwise-reader/stats"
+)
+
+type statsCmd struct {
+	baseCommand
+	since  string
+	format string
+	wpm    int
+	top    int
+}
+
+func (*statsCmd) Name() string { return "stats" }
+func (*statsCmd) Synopsis() string {
+	return "Show reading statistics"
+}
+func (*statsCmd) Usage() string {
+	return `stats [flags]:
+  Compute reading statistics across the full library or documents saved in a
+  time window: documents saved vs finished per week, words read, reading time
+  backlog per location, top sites and authors, time before archiving, and the
+  reading progress distribution.
+
+Flags:
+  -since   Only include documents saved since duration ago (e.g., 24h, 30d, 12w)
+  -format  Output format (table, json). Default: table
+  -wpm     Reading speed in words per minute. Default: 238
+  -top     Number of top sites and authors to show. Default: 10
+`
+}
+func (c *statsCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&c.since, "since", "", "Only include documents saved since duration ago (e.g., 24h, 30d, 12w)")
+	f.StringVar(&c.format, "format", "table", "Output format (table, json)")
+	f.IntVar(&c.wpm, "wpm", stats.DefaultWordsPerMinute, "Reading speed in words per minute")
+	f.IntVar(&c.top, "top", stats.DefaultTop, "Number of top sites and authors to show")
+}
+
+func (c *statsCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	if c.format != "table" && c.format != "json" {
+		printError(fmt.Errorf("invalid format: %s. Valid values: table, json", c.format))
+		return subcommands.ExitUsageError
+	}
+
+	opts := &stats.Options{
+		WordsPerMinute: c.wpm,
+		Top:            c.top,
+	}
+	listOpts := &reader.ListDocumentsOptions{}
+	if c.since != "" {
+		duration, err := parseDuration(c.since)
+		if err != nil {
+			printError(fmt.Errorf("invalid duration format: %s. Use formats like 24h, 30d, 12w", c.since))
+			return subcommands.ExitUsageError
+		}
+		opts.Since = time.Now().Add(-duration)
+
+		// Documents saved in the window have been updated since as well
+		listOpts.UpdatedAfter = &opts.Since
+	}
+
+	// Initialize client
+	if err := c.initClient(ctx); err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+
+	documents, err := reader.ListAllDocuments(ctx, c.client, listOpts)
+	if err != nil {
+		printError(fmt.Errorf("failed to list documents: %w", err))
+		return subcommands.ExitFailure
+	}
+
+	report := stats.Compute(documents, opts)
+
+	if c.format == "json" {
+		if err := printJSON(report); err != nil {
+			printError(fmt.Errorf("failed to output JSON: %w", err))
+			return subcommands.ExitFailure
+		}
+		return subcommands.ExitSuccess
+	}
+
+	if err := printStatsTable(report); err != nil {
+		printError(fmt.Errorf("failed to output table: %w", err))
+		return subcommands.ExitFailure
+	}
+
+	return subcommands.ExitSuccess
+}
+
+func printStatsTable(report *stats.Report) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(w, "Documents: %d\n", report.Documents)
+	fmt.Fprintf(w, "Words read: %d total, %.0f median (%d documents)\n",
+		report.WordsRead.Total, report.WordsRead.Median, report.WordsRead.Documents)
+	fmt.Fprintf(w, "Time before archiving: %.1f days median, %.1f days mean, %.1f days p90 (%d documents)\n",
+		report.TimeToArchive.MedianDays, report.TimeToArchive.MeanDays, report.TimeToArchive.P90Days, report.TimeToArchive.Count)
+
+	fmt.Fprintln(w, "\nWEEK\tSAVED\tFINISHED")
+	for _, week := range report.Weekly {
+		fmt.Fprintf(w, "%s\t%d\t%d\n", week.Start.Format("2006-01-02"), week.Saved, week.Finished)
+	}
+
+	fmt.Fprintln(w, "\nLOCATION\tDOCUMENTS\tWORDS LEFT\tREADING TIME")
+	for _, b := range report.Backlog {
+		readingTime := time.Duration(b.ReadingMinutes * float64(time.Minute)).Round(time.Minute)
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\n", b.Location, b.Documents, b.Words, readingTime)
+	}
+
+	for _, section := range []struct {
+		title   string
+		sources []stats.Source
+	}{
+		{"TOP SITES BY SAVES", report.TopSitesBySaves},
+		{"TOP SITES BY COMPLETION", report.TopSitesByCompletion},
+		{"TOP AUTHORS BY SAVES", report.TopAuthorsBySaves},
+		{"TOP AUTHORS BY COMPLETION", report.TopAuthorsByCompletion},
+	} {
+		fmt.Fprintf(w, "\n%s\tSAVED\tFINISHED\tCOMPLETION\n", section.title)
+		for _, s := range section.sources {
+			fmt.Fprintf(w, "%s\t%d\t%d\t%.0f%%\n", s.Name, s.Saved, s.Finished, s.CompletionRate*100)
+		}
+	}
+
+	fmt.Fprintln(w, "\nPROGRESS\tDOCUMENTS")
+	for _, b := range report.Progress {
+		fmt.Fprintf(w, "%s\t%d\n", b.Label, b.Count)
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/subcommands"
+	"github.com/tcnksm/go-readwise-reader/cmd/internal/config"
+	"github.com/tcnksm/go-readwise-reader/trash"
+)
+
+// openTrash opens the trash in the data directory
+func openTrash() (*trash.Trash, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return nil, err
+	}
+	return trash.Open(filepath.Join(dir, "trash")), nil
+}
+
+type trashCmd struct{}
+
+func (*trashCmd) Name() string { return "trash" }
+func (*trashCmd) Synopsis() string {
+	return "List and restore deleted documents"
+}
+func (*trashCmd) Usage() string {
+	return `trash <subcommand> [flags]:
+  Manage the local trash where reader delete keeps deleted documents with
+  their HTML content, in $XDG_DATA_HOME/reader/trash.
+
+Subcommands:
+  list     List documents in the trash
+  restore  Save trashed documents again
+  empty    Remove documents from the trash for good
+`
+}
+func (*trashCmd) SetFlags(f *flag.FlagSet) {}
+
+func (c *trashCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	return executeSubcommands(ctx, f, "reader trash", &trashListCmd{}, &trashRestoreCmd{}, &trashEmptyCmd{})
+}
+
+type trashListCmd struct{}
+
+func (*trashListCmd) Name() string { return "list" }
+func (*trashListCmd) Synopsis() string {
+	return "List documents in the trash"
+}
+func (*trashListCmd) Usage() string {
+	return `list:
+  List documents in the trash, most recently deleted first.
+`
+}
+func (*trashListCmd) SetFlags(f *flag.FlagSet) {}
+
+func (c *trashListCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	t, err := openTrash()
+	if err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+	items, err := t.List()
+	if err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tDELETED\tLOCATION\tTITLE")
+	for _, item := range items {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+			item.Document.ID,
+			item.DeletedAt.Local().Format("2006-01-02 15:04"),
+			item.Document.Location,
+			truncate(tuiTitle(item.Document), 60),
+		)
+	}
+	if err := w.Flush(); err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+	return subcommands.ExitSuccess
+}
+
+type trashRestoreCmd struct {
+	baseCommand
+}
+
+func (*trashRestoreCmd) Name() string { return "restore" }
+func (*trashRestoreCmd) Synopsis() string {
+	return "Save trashed documents again"
+}
+func (*trashRestoreCmd) Usage() string {
+	return `restore <document-id>...:
+  Save trashed documents again from their URL, HTML content, notes and
+  metadata, and remove them from the trash. Restored documents get new IDs.
+  Outputs the new document IDs by trashed document ID as pretty-printed JSON.
+`
+}
+func (*trashRestoreCmd) SetFlags(f *flag.FlagSet) {}
+
+func (c *trashRestoreCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	args := f.Args()
+	if len(args) == 0 {
+		fmt.Fprintf(os.Stderr, "Usage: %s\n", c.Usage())
+		return subcommands.ExitUsageError
+	}
+
+	t, err := openTrash()
+	if err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+
+	// Initialize client
+	if err := c.initClient(ctx); err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+
+	restored := make(map[string]string)
+	status := subcommands.ExitSuccess
+	for _, documentID := range args {
+		resp, err := t.Restore(ctx, c.client, documentID)
+		if err != nil {
+			printError(err)
+			status = subcommands.ExitFailure
+		}
+		if resp != nil {
+			restored[documentID] = resp.ID
+		}
+	}
+
+	if len(restored) > 0 {
+		if err := printJSON(restored); err != nil {
+			printError(fmt.Errorf("failed to output JSON: %w", err))
+			return subcommands.ExitFailure
+		}
+	}
+	return status
+}
+
+type trashEmptyCmd struct {
+	olderThan string
+}
+
+func (*trashEmptyCmd) Name() string { return "empty" }
+func (*trashEmptyCmd) Synopsis() string {
+	return "Remove documents from the trash for good"
+}
+func (*trashEmptyCmd) Usage() string {
+	return `empty [flags]:
+  Remove documents from the trash for good.
+
+Flags:
+  -older-than  Only remove documents deleted more than this long ago (e.g., 30d, 12w)
+`
+}
+func (c *trashEmptyCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&c.olderThan, "older-than", "", "Only remove documents deleted more than this long ago (e.g., 30d, 12w)")
+}
+
+func (c *trashEmptyCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	before := time.Now()
+	if c.olderThan != "" {
+		duration, err := parseDuration(c.olderThan)
+		if err != nil {
+			printError(fmt.Errorf("invalid duration format: %s. Use formats like 24h, 30d, 12w", c.olderThan))
+			return subcommands.ExitUsageError
+		}
+		before = before.Add(-duration)
+	}
+
+	t, err := openTrash()
+	if err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+	n, err := t.Empty(before)
+	if err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+	fmt.Fprintf(os.Stderr, "Removed %d document(s) from the trash\n", n)
+	return subcommands.ExitSuccess
+}
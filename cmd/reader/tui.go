@@ -0,0 +1,726 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/subcommands"
+	reader "github.com/tcnksm/go-readwise-reader"
+	"github.com/tcnksm/go-readwise-reader/cmd/internal/config"
+	"github.com/tcnksm/go-readwise-reader/journal"
+	"github.com/tcnksm/go-readwise-reader/markdown"
+	"github.com/tcnksm/go-readwise-reader/trash"
+)
+
+type tuiCmd struct {
+	baseCommand
+	location reader.Location
+}
+
+func (*tuiCmd) Name() string { return "tui" }
+func (*tuiCmd) Synopsis() string {
+	return "Triage documents in an interactive terminal UI"
+}
+func (*tuiCmd) Usage() string {
+	return `tui [flags]:
+  Triage documents in an interactive terminal UI. Documents are listed with
+  their title, site, word count and age, and loaded page by page as you scroll.
+  Moves and tag changes are applied immediately and can be undone. Like with
+  reader delete, deleted documents are put in the local trash and recorded in
+  the undo journal, so they can be restored with reader undo.
+
+Keys:
+  j/k, up/down  Move the cursor
+  l             Move to later
+  a             Move to archive
+  f             Move to feed
+  n             Move to new
+  t             Add tags (comma separated)
+  o             Open the URL in a browser
+  p, enter      Preview the document as text
+  d             Delete the document (asks for confirmation)
+  u             Undo the last move or tag change
+  r             Reload
+  q, ctrl+c     Quit
+
+Flags:
+  -location   Location to triage (new, later, archive, feed). Default: new, or the default location of the profile
+`
+}
+func (c *tuiCmd) SetFlags(f *flag.FlagSet) {
+	c.location = defaultLocation(reader.LocationNew)
+	f.Var(&c.location, "location", "Location to triage (new, later, archive, feed)")
+}
+
+func (c *tuiCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	if !stdinIsTerminal() {
+		printError(fmt.Errorf("tui requires a terminal"))
+		return subcommands.ExitUsageError
+	}
+
+	if err := c.initClient(ctx); err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+
+	trash, err := openTrash()
+	if err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+	journalPath, err := config.JournalPath()
+	if err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+
+	m := newTUIModel(ctx, c.client, c.location)
+	m.trash = trash
+	m.journal = journal.Open(journalPath)
+	if _, err := tea.NewProgram(m, tea.WithAltScreen(), tea.WithContext(ctx)).Run(); err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+	return subcommands.ExitSuccess
+}
+
+// tuiMode is what the keyboard input of the TUI currently goes to
+type tuiMode int
+
+const (
+	tuiModeList tuiMode = iota
+	tuiModeTags
+	tuiModeConfirmDelete
+	tuiModePreview
+)
+
+// tuiPrefetch is how close to the end of the loaded documents the cursor gets
+// before the next page is loaded
+const tuiPrefetch = 10
+
+// tuiModel is the state of the triage TUI. It follows the bubbletea
+// model/update loop and does not touch the terminal, so it can be driven
+// headlessly in tests.
+type tuiModel struct {
+	ctx      context.Context
+	client   reader.Client
+	location reader.Location
+	now      func() time.Time
+	openURL  func(url string) error
+
+	// trash and journal keep deleted documents so that deletions can be
+	// undone, as with reader delete
+	trash   *trash.Trash
+	journal *journal.Journal
+
+	docs     []reader.Document
+	cursor   int
+	offset   int
+	next     *string
+	loading  bool
+	loaded   bool
+	mode     tuiMode
+	input    string
+	preview  []string
+	scroll   int
+	status   string
+	undo     []tuiChange
+	changeID int
+	width    int
+	height   int
+}
+
+// tuiChange is a move or tag change applied optimistically, kept to undo it
+type tuiChange struct {
+	id     int
+	before reader.Document
+	index  int
+	update *reader.UpdateDocumentRequest
+	// removed is true if the change moved the document out of the list
+	removed bool
+}
+
+type (
+	tuiPageMsg struct {
+		docs []reader.Document
+		next *string
+		err  error
+	}
+	tuiUpdatedMsg struct {
+		change tuiChange
+		undo   bool
+		err    error
+	}
+	tuiDeletedMsg struct {
+		doc   reader.Document
+		index int
+		err   error
+		// journalErr is the error recording the deletion in the undo journal
+		journalErr error
+	}
+	tuiPreviewMsg struct {
+		text string
+		err  error
+	}
+	tuiStatusMsg string
+)
+
+func newTUIModel(ctx context.Context, client reader.Client, location reader.Location) *tuiModel {
+	return &tuiModel{
+		ctx:      ctx,
+		client:   client,
+		location: location,
+		now:      time.Now,
+		openURL:  openBrowser,
+		loading:  true,
+		width:    100,
+		height:   24,
+	}
+}
+
+func (m *tuiModel) Init() tea.Cmd {
+	return m.loadPage(nil)
+}
+
+// loadPage fetches the page of documents at cursor
+func (m *tuiModel) loadPage(cursor *string) tea.Cmd {
+	m.loading = true
+	opts := &reader.ListDocumentsOptions{Location: m.location}
+	if cursor != nil {
+		opts.PageCursor = *cursor
+	}
+	ctx, client := m.ctx, m.client
+	return func() tea.Msg {
+		resp, err := client.ListDocuments(ctx, opts)
+		if err != nil {
+			return tuiPageMsg{err: err}
+		}
+		return tuiPageMsg{docs: resp.Results, next: resp.NextPageCursor}
+	}
+}
+
+func (m *tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.clampCursor()
+		return m, nil
+
+	case tuiPageMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = "Error: " + msg.err.Error()
+			return m, nil
+		}
+		for _, doc := range msg.docs {
+			// Skip highlights, notes and documents already moved here optimistically
+			if doc.IsHighlight() || m.indexOf(doc.ID) >= 0 {
+				continue
+			}
+			m.docs = append(m.docs, doc)
+		}
+		m.next = msg.next
+		m.loaded = msg.next == nil
+		return m, m.prefetch()
+
+	case tuiUpdatedMsg:
+		if msg.err == nil {
+			return m, nil
+		}
+		if !msg.undo {
+			m.revert(msg.change)
+		}
+		m.status = "Error: " + msg.err.Error()
+		return m, nil
+
+	case tuiDeletedMsg:
+		if msg.err != nil {
+			m.insert(msg.index, msg.doc)
+			m.status = "Error: " + msg.err.Error()
+			return m, nil
+		}
+		m.status = "Deleted " + tuiTitle(msg.doc) + " (reader undo to restore)"
+		if msg.journalErr != nil {
+			m.status = "Deleted " + tuiTitle(msg.doc) + ", but failed to record it in the undo journal: " + msg.journalErr.Error()
+		}
+		return m, m.prefetch()
+
+	case tuiPreviewMsg:
+		if msg.err != nil {
+			m.mode = tuiModeList
+			m.status = "Error: " + msg.err.Error()
+			return m, nil
+		}
+		m.preview = strings.Split(msg.text, "\n")
+		return m, nil
+
+	case tuiStatusMsg:
+		m.status = string(msg)
+		return m, nil
+
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+	}
+	return m, nil
+}
+
+func (m *tuiModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	if key == "ctrl+c" {
+		return m, tea.Quit
+	}
+
+	switch m.mode {
+	case tuiModeTags:
+		switch msg.Type {
+		case tea.KeyEnter:
+			m.mode = tuiModeList
+			return m, m.addTags(m.input)
+		case tea.KeyEsc:
+			m.mode = tuiModeList
+		case tea.KeyBackspace:
+			if len(m.input) > 0 {
+				runes := []rune(m.input)
+				m.input = string(runes[:len(runes)-1])
+			}
+		case tea.KeyRunes, tea.KeySpace:
+			m.input += string(msg.Runes)
+		}
+		return m, nil
+
+	case tuiModeConfirmDelete:
+		m.mode = tuiModeList
+		if key == "y" {
+			return m, m.deleteSelected()
+		}
+		m.status = "Delete cancelled"
+		return m, nil
+
+	case tuiModePreview:
+		switch key {
+		case "q", "esc", "p", "enter":
+			m.mode = tuiModeList
+		case "j", "down":
+			m.scroll = min(m.scroll+1, max(len(m.preview)-m.bodyHeight(), 0))
+		case "k", "up":
+			m.scroll = max(m.scroll-1, 0)
+		case " ", "pgdown":
+			m.scroll = min(m.scroll+m.bodyHeight(), max(len(m.preview)-m.bodyHeight(), 0))
+		case "b", "pgup":
+			m.scroll = max(m.scroll-m.bodyHeight(), 0)
+		}
+		return m, nil
+	}
+
+	m.status = ""
+	switch key {
+	case "q":
+		return m, tea.Quit
+	case "j", "down":
+		m.cursor++
+		m.clampCursor()
+		return m, m.prefetch()
+	case "k", "up":
+		m.cursor--
+		m.clampCursor()
+	case "l":
+		return m, m.move(reader.LocationLater)
+	case "a":
+		return m, m.move(reader.LocationArchive)
+	case "f":
+		return m, m.move(reader.LocationFeed)
+	case "n":
+		return m, m.move(reader.LocationNew)
+	case "t":
+		if _, ok := m.selected(); ok {
+			m.mode = tuiModeTags
+			m.input = ""
+		}
+	case "o":
+		if doc, ok := m.selected(); ok {
+			url := doc.SourceURL
+			if url == "" {
+				url = doc.URL
+			}
+			return m, func() tea.Msg {
+				if err := m.openURL(url); err != nil {
+					return tuiStatusMsg("Error: " + err.Error())
+				}
+				return tuiStatusMsg("Opened " + url)
+			}
+		}
+	case "p", "enter":
+		if doc, ok := m.selected(); ok {
+			m.mode = tuiModePreview
+			m.preview = []string{"Loading..."}
+			m.scroll = 0
+			return m, m.loadPreview(doc)
+		}
+	case "d":
+		if doc, ok := m.selected(); ok {
+			m.mode = tuiModeConfirmDelete
+			m.status = fmt.Sprintf("Delete %q? (y/N)", tuiTitle(doc))
+		}
+	case "u":
+		return m, m.undoLast()
+	case "r":
+		m.docs = nil
+		m.cursor, m.offset = 0, 0
+		m.next = nil
+		m.loaded = false
+		return m, m.loadPage(nil)
+	}
+	return m, nil
+}
+
+// selected returns the document under the cursor
+func (m *tuiModel) selected() (reader.Document, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.docs) {
+		return reader.Document{}, false
+	}
+	return m.docs[m.cursor], true
+}
+
+// prefetch loads the next page when the cursor nears the end of the list
+func (m *tuiModel) prefetch() tea.Cmd {
+	if m.loading || m.loaded || m.next == nil || m.cursor < len(m.docs)-tuiPrefetch {
+		return nil
+	}
+	return m.loadPage(m.next)
+}
+
+// move moves the selected document to location, removing it from the list
+func (m *tuiModel) move(location reader.Location) tea.Cmd {
+	doc, ok := m.selected()
+	if !ok || doc.Location == location {
+		return nil
+	}
+
+	change := m.apply(doc, &reader.UpdateDocumentRequest{Location: location})
+	m.status = fmt.Sprintf("Moved %s to %s (u to undo)", tuiTitle(doc), location)
+	return tea.Batch(m.send(change, false), m.prefetch())
+}
+
+// addTags adds comma separated tags to the selected document
+func (m *tuiModel) addTags(input string) tea.Cmd {
+	doc, ok := m.selected()
+	if !ok {
+		return nil
+	}
+
+	tags := doc.TagNames()
+	added := false
+	for _, tag := range strings.Split(input, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+			added = true
+		}
+	}
+	if !added {
+		return nil
+	}
+	sort.Strings(tags)
+
+	change := m.apply(doc, &reader.UpdateDocumentRequest{Tags: tags})
+	m.status = fmt.Sprintf("Tagged %s with %s (u to undo)", tuiTitle(doc), strings.Join(tags, ", "))
+	return m.send(change, false)
+}
+
+// apply changes the list optimistically and records the change for undo
+func (m *tuiModel) apply(doc reader.Document, update *reader.UpdateDocumentRequest) tuiChange {
+	m.changeID++
+	change := tuiChange{
+		id:     m.changeID,
+		before: doc,
+		index:  m.cursor,
+		update: update,
+	}
+
+	if update.Location != "" && update.Location != m.location {
+		change.removed = true
+		m.remove(m.cursor)
+	} else {
+		m.docs[m.cursor] = tuiApply(doc, update)
+	}
+
+	m.undo = append(m.undo, change)
+	return change
+}
+
+// revert restores the list as it was before a change and forgets the change
+func (m *tuiModel) revert(change tuiChange) {
+	if change.removed {
+		m.insert(change.index, change.before)
+	} else if i := m.indexOf(change.before.ID); i >= 0 {
+		m.docs[i] = change.before
+	}
+	m.undo = slices.DeleteFunc(m.undo, func(c tuiChange) bool {
+		return c.id == change.id
+	})
+}
+
+// undoLast reverts the last change locally and sends the reverse update
+func (m *tuiModel) undoLast() tea.Cmd {
+	if len(m.undo) == 0 {
+		m.status = "Nothing to undo"
+		return nil
+	}
+	change := m.undo[len(m.undo)-1]
+	m.revert(change)
+
+	reverse := &reader.UpdateDocumentRequest{}
+	if change.update.Location != "" {
+		reverse.Location = change.before.Location
+	}
+	if change.update.Tags != nil {
+		reverse.Tags = change.before.TagNames()
+		if len(reverse.Tags) == 0 {
+			reverse.Clear = []reader.UpdateField{reader.UpdateFieldTags}
+		}
+	}
+	m.status = "Undid change to " + tuiTitle(change.before)
+	return m.send(tuiChange{before: change.before, update: reverse}, true)
+}
+
+// send sends the update of a change to the API
+func (m *tuiModel) send(change tuiChange, undo bool) tea.Cmd {
+	ctx, client := m.ctx, m.client
+	return func() tea.Msg {
+		_, err := client.UpdateDocument(ctx, change.before.ID, change.update)
+		return tuiUpdatedMsg{change: change, undo: undo, err: err}
+	}
+}
+
+// deleteSelected removes the selected document from the list and deletes it
+// after putting it in the trash, recording the deletion in the undo journal
+func (m *tuiModel) deleteSelected() tea.Cmd {
+	doc, ok := m.selected()
+	if !ok {
+		return nil
+	}
+	index := m.cursor
+	m.remove(index)
+	m.status = "Deleting " + tuiTitle(doc)
+	ctx, client, trash, j := m.ctx, m.client, m.trash, m.journal
+	return func() tea.Msg {
+		// The document is kept with its HTML content so that it can be saved
+		// again
+		before, err := journal.Snapshot(ctx, client, doc.ID, true)
+		if err != nil {
+			return tuiDeletedMsg{doc: doc, index: index, err: err}
+		}
+		if err := trashAndDelete(ctx, client, trash, *before); err != nil {
+			return tuiDeletedMsg{doc: doc, index: index, err: err}
+		}
+		_, err = j.Record("reader tui", []journal.Change{{Operation: journal.OperationDelete, DocumentID: doc.ID, Before: before}})
+		return tuiDeletedMsg{doc: doc, index: index, journalErr: err}
+	}
+}
+
+// loadPreview fetches the HTML content of a document and converts it to text
+func (m *tuiModel) loadPreview(doc reader.Document) tea.Cmd {
+	ctx, client, width := m.ctx, m.client, m.width
+	return func() tea.Msg {
+		full, err := reader.GetDocument(ctx, client, doc.ID, true)
+		if err != nil {
+			return tuiPreviewMsg{err: err}
+		}
+
+		text := markdown.PlainText(full.HTMLContent)
+		if text == "" {
+			text = full.Summary
+		}
+		return tuiPreviewMsg{text: tuiTitle(*full) + "\n" + full.URL + "\n\n" + wrapText(text, width)}
+	}
+}
+
+func (m *tuiModel) remove(i int) {
+	m.docs = slices.Delete(m.docs, i, i+1)
+	m.clampCursor()
+}
+
+func (m *tuiModel) insert(i int, doc reader.Document) {
+	i = min(max(i, 0), len(m.docs))
+	m.docs = slices.Insert(m.docs, i, doc)
+	m.cursor = i
+	m.clampCursor()
+}
+
+func (m *tuiModel) indexOf(id string) int {
+	return slices.IndexFunc(m.docs, func(d reader.Document) bool {
+		return d.ID == id
+	})
+}
+
+// clampCursor keeps the cursor on a document and within the visible rows
+func (m *tuiModel) clampCursor() {
+	m.cursor = min(max(m.cursor, 0), max(len(m.docs)-1, 0))
+	rows := m.bodyHeight()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+rows {
+		m.offset = m.cursor - rows + 1
+	}
+}
+
+// bodyHeight is the number of rows between the header and the footer
+func (m *tuiModel) bodyHeight() int {
+	return max(m.height-4, 1)
+}
+
+func (m *tuiModel) View() string {
+	var b strings.Builder
+
+	if m.mode == tuiModePreview {
+		end := min(m.scroll+m.bodyHeight()+2, len(m.preview))
+		for _, line := range m.preview[m.scroll:end] {
+			b.WriteString(line + "\n")
+		}
+		b.WriteString("\nj/k scroll  space/b page  q back\n")
+		return b.String()
+	}
+
+	more := ""
+	if !m.loaded {
+		more = "+"
+	}
+	fmt.Fprintf(&b, "%s: %d%s documents\n\n", m.location, len(m.docs), more)
+
+	titleWidth := max(m.width-36, 10)
+	end := min(m.offset+m.bodyHeight(), len(m.docs))
+	for i := m.offset; i < end; i++ {
+		doc := m.docs[i]
+		marker := "  "
+		if i == m.cursor {
+			marker = "> "
+		}
+		fmt.Fprintf(&b, "%s%-*s  %-16s %7s %5s\n",
+			marker,
+			titleWidth, truncate(tuiTitle(doc), titleWidth),
+			truncate(doc.SiteName, 16),
+			formatWords(doc.WordCount),
+			formatAge(doc.SavedAt, m.now()),
+		)
+	}
+	if len(m.docs) == 0 && !m.loading {
+		b.WriteString("  No documents\n")
+	}
+	if m.loading {
+		b.WriteString("  Loading...\n")
+	}
+
+	b.WriteString("\n")
+	switch {
+	case m.mode == tuiModeTags:
+		b.WriteString("Tags: " + m.input + "_")
+	case m.status != "":
+		b.WriteString(m.status)
+	default:
+		b.WriteString("l later  a archive  f feed  t tag  o open  p preview  d delete  u undo  q quit")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// tuiApply returns doc with an update applied
+func tuiApply(doc reader.Document, update *reader.UpdateDocumentRequest) reader.Document {
+	if update.Location != "" {
+		doc.Location = update.Location
+	}
+	if update.Tags != nil {
+		doc.Tags = make(map[string]interface{}, len(update.Tags))
+		for _, tag := range update.Tags {
+			doc.Tags[tag] = map[string]interface{}{"name": tag}
+		}
+	}
+	return doc
+}
+
+func tuiTitle(doc reader.Document) string {
+	if doc.Title != "" {
+		return doc.Title
+	}
+	return doc.URL
+}
+
+func truncate(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+	if width <= 1 {
+		return string(runes[:width])
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+func formatWords(n int) string {
+	if n >= 1000 {
+		return fmt.Sprintf("%.1fk", float64(n)/1000)
+	}
+	return fmt.Sprintf("%d", n)
+}
+
+// formatAge formats the time since t in the largest whole unit
+func formatAge(t *time.Time, now time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	d := now.Sub(*t)
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	case d < 7*24*time.Hour:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	default:
+		return fmt.Sprintf("%dw", int(d.Hours()/24/7))
+	}
+}
+
+// wrapText wraps the lines of text at width
+func wrapText(text string, width int) string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		current := ""
+		for _, word := range strings.Fields(line) {
+			if current != "" && len([]rune(current))+1+len([]rune(word)) > width {
+				lines = append(lines, current)
+				current = ""
+			}
+			if current != "" {
+				current += " "
+			}
+			current += word
+		}
+		lines = append(lines, current)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// openBrowser opens url in the default browser
+func openBrowser(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to open browser: %w", err)
+	}
+	return cmd.Process.Release()
+}
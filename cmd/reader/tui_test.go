@@ -0,0 +1,348 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	reader "github.com/tcnksm/go-readwise-reader"
+	"github.com/tcnksm/go-readwise-reader/journal"
+	"github.com/tcnksm/go-readwise-reader/trash"
+)
+
+// fakeClient serves documents from memory and records the requests it gets
+type fakeClient struct {
+	pages     [][]reader.Document
+	docs      map[string]reader.Document
+	listCalls []*reader.ListDocumentsOptions
+	updates   []fakeUpdate
+	deleted   []string
+	updateErr error
+	deleteErr error
+}
+
+type fakeUpdate struct {
+	id  string
+	req *reader.UpdateDocumentRequest
+}
+
+func newFakeClient(pages ...[]reader.Document) *fakeClient {
+	c := &fakeClient{pages: pages, docs: make(map[string]reader.Document)}
+	for _, page := range pages {
+		for _, doc := range page {
+			c.docs[doc.ID] = doc
+		}
+	}
+	return c
+}
+
+func (c *fakeClient) ListDocuments(ctx context.Context, opts *reader.ListDocumentsOptions) (*reader.ListDocumentsResponse, error) {
+	c.listCalls = append(c.listCalls, opts)
+	if opts.ID != "" {
+		doc, ok := c.docs[opts.ID]
+		if !ok {
+			return &reader.ListDocumentsResponse{}, nil
+		}
+		return &reader.ListDocumentsResponse{Count: 1, Results: []reader.Document{doc}}, nil
+	}
+
+	page := 0
+	if opts.PageCursor != "" {
+		fmt.Sscanf(opts.PageCursor, "page-%d", &page)
+	}
+	resp := &reader.ListDocumentsResponse{Results: c.pages[page]}
+	if page+1 < len(c.pages) {
+		next := fmt.Sprintf("page-%d", page+1)
+		resp.NextPageCursor = &next
+	}
+	return resp, nil
+}
+
+func (c *fakeClient) CreateDocument(ctx context.Context, url string, req *reader.CreateDocumentRequest) (*reader.CreateDocumentResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c *fakeClient) UpdateDocument(ctx context.Context, id string, req *reader.UpdateDocumentRequest) (*reader.UpdateDocumentResponse, error) {
+	c.updates = append(c.updates, fakeUpdate{id: id, req: req})
+	if c.updateErr != nil {
+		return nil, c.updateErr
+	}
+	return &reader.UpdateDocumentResponse{ID: id}, nil
+}
+
+func (c *fakeClient) DeleteDocument(ctx context.Context, id string) error {
+	c.deleted = append(c.deleted, id)
+	return c.deleteErr
+}
+
+// runCmd executes a command and feeds the resulting messages back into the
+// model until no commands are left, like the bubbletea runtime does
+func runCmd(t *testing.T, m *tuiModel, cmd tea.Cmd) {
+	t.Helper()
+	for cmd != nil {
+		msg := cmd()
+		switch msg := msg.(type) {
+		case nil:
+			return
+		case tea.BatchMsg:
+			for _, c := range msg {
+				runCmd(t, m, c)
+			}
+			return
+		case tea.QuitMsg:
+			return
+		default:
+			_, cmd = m.Update(msg)
+		}
+	}
+}
+
+// press sends key presses to the model and runs the resulting commands
+func press(t *testing.T, m *tuiModel, keys ...string) {
+	t.Helper()
+	for _, key := range keys {
+		var msg tea.KeyMsg
+		switch key {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		}
+		_, cmd := m.Update(msg)
+		runCmd(t, m, cmd)
+	}
+}
+
+func newTestModel(t *testing.T, client *fakeClient) *tuiModel {
+	t.Helper()
+	m := newTUIModel(context.Background(), client, reader.LocationNew)
+	m.now = func() time.Time { return time.Date(2025, 7, 20, 0, 0, 0, 0, time.UTC) }
+	m.openURL = func(string) error { return nil }
+	m.trash = trash.Open(filepath.Join(t.TempDir(), "trash"))
+	m.journal = journal.Open(filepath.Join(t.TempDir(), "journal.jsonl"))
+	runCmd(t, m, m.Init())
+	return m
+}
+
+func testDocs(prefix string, n int) []reader.Document {
+	saved := time.Date(2025, 7, 17, 0, 0, 0, 0, time.UTC)
+	docs := make([]reader.Document, n)
+	for i := range docs {
+		docs[i] = reader.Document{
+			ID:        fmt.Sprintf("%s%d", prefix, i),
+			Title:     fmt.Sprintf("Title %s%d", prefix, i),
+			URL:       fmt.Sprintf("https://example.com/%s%d", prefix, i),
+			SiteName:  "example.com",
+			WordCount: 1200,
+			Location:  reader.LocationNew,
+			Category:  reader.CategoryArticle,
+			SavedAt:   &saved,
+		}
+	}
+	return docs
+}
+
+func docIDs(docs []reader.Document) []string {
+	ids := make([]string, len(docs))
+	for i, doc := range docs {
+		ids[i] = doc.ID
+	}
+	return ids
+}
+
+func TestTUI_View(t *testing.T) {
+	m := newTestModel(t, newFakeClient(testDocs("a", 2)))
+
+	view := m.View()
+	for _, want := range []string{"new: 2 documents", "> Title a0", "example.com", "1.2k", "3d"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View() does not contain %q:\n%s", want, view)
+		}
+	}
+}
+
+func TestTUI_SkipsHighlightsAndNotes(t *testing.T) {
+	docs := testDocs("a", 3)
+	docs[1].Category = reader.CategoryHighlight
+	docs[2].Category = reader.CategoryNote
+	m := newTestModel(t, newFakeClient(docs))
+
+	if got := docIDs(m.docs); !slices.Equal(got, []string{"a0"}) {
+		t.Errorf("docs = %v, want only the article", got)
+	}
+}
+
+func TestTUI_MoveAndUndo(t *testing.T) {
+	client := newFakeClient(testDocs("a", 3))
+	m := newTestModel(t, client)
+
+	press(t, m, "j", "a")
+	if got, want := docIDs(m.docs), []string{"a0", "a2"}; !slices.Equal(got, want) {
+		t.Fatalf("docs = %v, want %v", got, want)
+	}
+	if len(client.updates) != 1 || client.updates[0].id != "a1" || client.updates[0].req.Location != reader.LocationArchive {
+		t.Fatalf("updates = %+v, want a1 moved to archive", client.updates)
+	}
+
+	press(t, m, "u")
+	if got, want := docIDs(m.docs), []string{"a0", "a1", "a2"}; !slices.Equal(got, want) {
+		t.Fatalf("docs after undo = %v, want %v", got, want)
+	}
+	if m.cursor != 1 {
+		t.Errorf("cursor = %d, want 1", m.cursor)
+	}
+	if len(client.updates) != 2 || client.updates[1].id != "a1" || client.updates[1].req.Location != reader.LocationNew {
+		t.Fatalf("updates = %+v, want a1 moved back to new", client.updates)
+	}
+
+	press(t, m, "u")
+	if !strings.Contains(m.status, "Nothing to undo") {
+		t.Errorf("status = %q, want nothing to undo", m.status)
+	}
+}
+
+func TestTUI_MoveFailureRollsBack(t *testing.T) {
+	client := newFakeClient(testDocs("a", 2))
+	client.updateErr = errors.New("boom")
+	m := newTestModel(t, client)
+
+	press(t, m, "l")
+	if got, want := docIDs(m.docs), []string{"a0", "a1"}; !slices.Equal(got, want) {
+		t.Errorf("docs = %v, want %v", got, want)
+	}
+	if !strings.Contains(m.status, "boom") {
+		t.Errorf("status = %q, want error", m.status)
+	}
+	if len(m.undo) != 0 {
+		t.Errorf("undo = %v, want empty", m.undo)
+	}
+}
+
+func TestTUI_AddTags(t *testing.T) {
+	client := newFakeClient(testDocs("a", 1))
+	m := newTestModel(t, client)
+
+	press(t, m, "t", "r", "f", "c", ",", " ", "n", "e", "t", "enter")
+	if len(client.updates) != 1 || !slices.Equal(client.updates[0].req.Tags, []string{"net", "rfc"}) {
+		t.Fatalf("updates = %+v, want tags [net rfc]", client.updates)
+	}
+	if got := m.docs[0].TagNames(); !slices.Equal(got, []string{"net", "rfc"}) {
+		t.Errorf("tags = %v, want [net rfc]", got)
+	}
+
+	press(t, m, "u")
+	if got := m.docs[0].TagNames(); len(got) != 0 {
+		t.Errorf("tags after undo = %v, want none", got)
+	}
+	if last := client.updates[len(client.updates)-1]; !slices.Equal(last.req.Clear, []reader.UpdateField{reader.UpdateFieldTags}) {
+		t.Errorf("undo update = %+v, want tags cleared", last.req)
+	}
+}
+
+func TestTUI_Delete(t *testing.T) {
+	client := newFakeClient(testDocs("a", 2))
+	m := newTestModel(t, client)
+
+	press(t, m, "d", "n")
+	if len(client.deleted) != 0 || len(m.docs) != 2 {
+		t.Fatalf("deleted = %v, want none after cancelling", client.deleted)
+	}
+
+	press(t, m, "d", "y")
+	if !slices.Equal(client.deleted, []string{"a0"}) {
+		t.Fatalf("deleted = %v, want [a0]", client.deleted)
+	}
+	if got, want := docIDs(m.docs), []string{"a1"}; !slices.Equal(got, want) {
+		t.Errorf("docs = %v, want %v", got, want)
+	}
+
+	// The deletion can be undone like one made by reader delete
+	if item, err := m.trash.Get("a0"); err != nil || item.Document.ID != "a0" {
+		t.Errorf("trash.Get() = %+v, %v", item, err)
+	}
+	entries, err := m.journal.Entries()
+	if err != nil {
+		t.Fatalf("Entries() error = %v", err)
+	}
+	if len(entries) != 1 || entries[0].Command != "reader tui" || len(entries[0].Changes) != 1 ||
+		entries[0].Changes[0].Operation != journal.OperationDelete || entries[0].Changes[0].DocumentID != "a0" {
+		t.Errorf("entries = %+v, want the deletion of a0", entries)
+	}
+}
+
+func TestTUI_DeleteFailureKeepsTrash(t *testing.T) {
+	client := newFakeClient(testDocs("a", 2))
+	client.deleteErr = errors.New("boom")
+	m := newTestModel(t, client)
+
+	press(t, m, "d", "y")
+	if got, want := docIDs(m.docs), []string{"a0", "a1"}; !slices.Equal(got, want) {
+		t.Errorf("docs = %v, want %v after the failure", got, want)
+	}
+	if items, err := m.trash.List(); err != nil || len(items) != 0 {
+		t.Errorf("trash = %v, %v, want empty", items, err)
+	}
+	if entries, err := m.journal.Entries(); err != nil || len(entries) != 0 {
+		t.Errorf("entries = %v, %v, want none", entries, err)
+	}
+}
+
+func TestTUI_LazyPagination(t *testing.T) {
+	client := newFakeClient(testDocs("a", 20), testDocs("b", 20))
+	m := newTestModel(t, client)
+
+	if len(m.docs) != 20 || len(client.listCalls) != 1 {
+		t.Fatalf("loaded %d documents in %d requests, want 20 in 1", len(m.docs), len(client.listCalls))
+	}
+
+	for range 9 {
+		press(t, m, "j")
+	}
+	if len(client.listCalls) != 1 {
+		t.Fatalf("listCalls = %d, want 1 before nearing the end", len(client.listCalls))
+	}
+
+	press(t, m, "j")
+	if len(m.docs) != 40 || len(client.listCalls) != 2 || client.listCalls[1].PageCursor != "page-1" {
+		t.Fatalf("loaded %d documents in %d requests, want 40 in 2", len(m.docs), len(client.listCalls))
+	}
+	if !m.loaded {
+		t.Error("loaded = false, want true after the last page")
+	}
+}
+
+func TestTUI_Preview(t *testing.T) {
+	docs := testDocs("a", 1)
+	client := newFakeClient(docs)
+	doc := client.docs["a0"]
+	doc.HTMLContent = "<h1>Heading</h1><p>First &amp; second.</p><script>ignored()</script><p>Last</p>"
+	client.docs["a0"] = doc
+	m := newTestModel(t, client)
+
+	press(t, m, "p")
+	if m.mode != tuiModePreview {
+		t.Fatalf("mode = %v, want preview", m.mode)
+	}
+	view := m.View()
+	for _, want := range []string{"Heading", "First & second.", "Last"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("preview does not contain %q:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "ignored") {
+		t.Errorf("preview contains script:\n%s", view)
+	}
+
+	press(t, m, "q")
+	if m.mode != tuiModeList {
+		t.Errorf("mode = %v, want list", m.mode)
+	}
+}
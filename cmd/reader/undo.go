@@ -0,0 +1,257 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/google/subcommands"
+	"github.com/tcnksm/go-readwise-reader/cmd/internal/config"
+	"github.com/tcnksm/go-readwise-reader/history"
+	"github.com/tcnksm/go-readwise-reader/journal"
+)
+
+type undoCmd struct {
+	baseCommand
+}
+
+func (*undoCmd) Name() string { return "undo" }
+func (*undoCmd) Synopsis() string {
+	return "Undo the last changes made by update, mark, note, delete, create or the MCP server"
+}
+func (*undoCmd) Usage() string {
+	return `undo [n]:
+  Undo the last n changes recorded in the undo journal (default 1), newest
+  first. Updated documents get their previous location, title, tags and
+  metadata back, deleted documents are saved again from their URL, HTML
+  content and notes, and created documents are deleted. A command that
+  changed several documents is undone as a unit. See reader history.
+  Outputs the undo entries as pretty-printed JSON.
+`
+}
+func (*undoCmd) SetFlags(f *flag.FlagSet) {}
+
+func (c *undoCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	n := 1
+	switch args := f.Args(); len(args) {
+	case 0:
+	case 1:
+		var err error
+		if n, err = strconv.Atoi(args[0]); err != nil || n < 1 {
+			printError(fmt.Errorf("invalid number of changes: %s", args[0]))
+			return subcommands.ExitUsageError
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "Usage: %s\n", c.Usage())
+		return subcommands.ExitUsageError
+	}
+
+	path, err := config.JournalPath()
+	if err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+
+	// Initialize client
+	if err := c.initClient(ctx); err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+
+	undone, undoErr := journal.Open(path).Undo(ctx, c.client, n)
+	if len(undone) > 0 {
+		if err := printJSON(undone); err != nil {
+			printError(fmt.Errorf("failed to output JSON: %w", err))
+			return subcommands.ExitFailure
+		}
+	}
+	if undoErr != nil {
+		printError(undoErr)
+		return subcommands.ExitFailure
+	}
+	if len(undone) == 0 {
+		fmt.Fprintln(os.Stderr, "Nothing to undo")
+	}
+	return subcommands.ExitSuccess
+}
+
+type historyCmd struct {
+	baseCommand
+	limit   int
+	history string
+	sync    bool
+	format  string
+}
+
+func (*historyCmd) Name() string { return "history" }
+func (*historyCmd) Synopsis() string {
+	return "Show the changes recorded in the undo journal or the timeline of a document"
+}
+func (*historyCmd) Usage() string {
+	return `history [flags] [document-id]:
+  Show the most recent changes recorded in the undo journal, newest first.
+
+  With a document ID, show the timeline of the document instead: its
+  versions observed by reader watch, reader webhook serve and -sync, oldest
+  first, with the location and reading progress of each and what changed
+  from the previous one.
+
+Flags:
+  -n        Number of entries or versions to show. Default: 20
+  -history  Path to the document history directory. Default: $XDG_DATA_HOME/reader/history
+  -sync     Record the documents updated since the last sync before showing the timeline
+  -format   Format of the timeline: table or json. Default: table
+`
+}
+func (c *historyCmd) SetFlags(f *flag.FlagSet) {
+	f.IntVar(&c.limit, "n", 20, "Number of entries or versions to show")
+	f.StringVar(&c.history, "history", "", "Path to the document history directory")
+	f.BoolVar(&c.sync, "sync", false, "Record the documents updated since the last sync before showing the timeline")
+	f.StringVar(&c.format, "format", "table", "Format of the timeline: table or json")
+}
+
+func (c *historyCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	switch {
+	case f.NArg() > 1 || (c.format != "table" && c.format != "json") || (c.sync && f.NArg() == 0):
+		fmt.Fprintf(os.Stderr, "Usage: %s\n", c.Usage())
+		return subcommands.ExitUsageError
+	case f.NArg() == 1:
+		return c.timeline(ctx, f.Arg(0))
+	}
+
+	path, err := config.JournalPath()
+	if err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+	entries, err := journal.Open(path).Entries()
+	if err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTIME\tCOMMAND\tSTATUS\tCHANGES")
+	for i := len(entries) - 1; i >= 0 && i >= len(entries)-c.limit; i-- {
+		entry := entries[i]
+		status := ""
+		switch {
+		case entry.Undoes > 0:
+			status = fmt.Sprintf("undoes %d", entry.Undoes)
+		case entry.UndoneBy > 0:
+			status = fmt.Sprintf("undone by %d", entry.UndoneBy)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n",
+			entry.ID,
+			entry.Time.Local().Format("2006-01-02 15:04:05"),
+			entry.Command,
+			status,
+			describeChanges(entry.Changes),
+		)
+	}
+	if err := w.Flush(); err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+	return subcommands.ExitSuccess
+}
+
+// timeline shows the most recent recorded versions of a document
+func (c *historyCmd) timeline(ctx context.Context, documentID string) subcommands.ExitStatus {
+	store, err := openHistory(c.history)
+	if err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+
+	if c.sync {
+		// Initialize client
+		if err := c.initClient(ctx); err != nil {
+			printError(err)
+			return subcommands.ExitFailure
+		}
+		n, err := store.Sync(ctx, c.client, nil)
+		if err != nil {
+			printError(err)
+			return subcommands.ExitFailure
+		}
+		logger.InfoContext(ctx, "synced document history", slog.Int("versions", n))
+	}
+
+	versions, err := store.Versions(documentID)
+	if err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+	if len(versions) == 0 {
+		printError(fmt.Errorf("no history recorded for document %s", documentID))
+		return subcommands.ExitFailure
+	}
+	versions = versions[max(0, len(versions)-c.limit):]
+
+	if c.format == "json" {
+		if err := printJSON(versions); err != nil {
+			printError(fmt.Errorf("failed to output JSON: %w", err))
+			return subcommands.ExitFailure
+		}
+		return subcommands.ExitSuccess
+	}
+
+	fmt.Println(versions[len(versions)-1].Document.Title)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tSOURCE\tLOCATION\tPROGRESS\tCHANGES")
+	for _, v := range versions {
+		changes := "first seen"
+		if len(v.Changes) > 0 {
+			parts := make([]string, 0, len(v.Changes))
+			for _, change := range v.Changes {
+				parts = append(parts, truncate(change.String(), 60))
+			}
+			changes = strings.Join(parts, ", ")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%.0f%%\t%s\n",
+			v.Time.Local().Format("2006-01-02 15:04:05"),
+			v.Source,
+			v.Document.Location,
+			v.Document.ReadingProgressPercent,
+			changes,
+		)
+	}
+	if err := w.Flush(); err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+	return subcommands.ExitSuccess
+}
+
+// openHistory returns the document history stored in dir, or in the default
+// directory if dir is empty
+func openHistory(dir string) (*history.Store, error) {
+	if dir == "" {
+		dataDir, err := config.DataDir()
+		if err != nil {
+			return nil, err
+		}
+		dir = filepath.Join(dataDir, "history")
+	}
+	return history.Open(dir), nil
+}
+
+// describeChanges summarizes journal changes on one line
+func describeChanges(changes []journal.Change) string {
+	parts := make([]string, 0, len(changes))
+	for _, change := range changes {
+		part := fmt.Sprintf("%s %s", change.Operation, change.DocumentID)
+		if change.Before != nil && change.Before.Title != "" {
+			part += fmt.Sprintf(" (%s)", truncate(change.Before.Title, 40))
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -5,10 +5,12 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/google/subcommands"
 	reader "github.com/tcnksm/go-readwise-reader"
+	"github.com/tcnksm/go-readwise-reader/journal"
 )
 
 type updateCmd struct {
@@ -16,10 +18,19 @@ type updateCmd struct {
 	title         string
 	author        string
 	summary       string
-	location      string
-	category      string
+	location      reader.Location
+	category      reader.Category
 	imageURL      string
 	publishedDate string
+	seen          string
+	notes         string
+
+	clearAuthor        bool
+	clearSummary       bool
+	clearImageURL      bool
+	clearPublishedDate bool
+	clearTags          bool
+	clearNotes         bool
 }
 
 func (*updateCmd) Name() string { return "update" }
@@ -27,19 +38,28 @@ func (*updateCmd) Synopsis() string {
 	return "Update document properties"
 }
 func (*updateCmd) Usage() string {
-	return `update <document-id> [flags]:
-  Update properties of an existing document.
+	return `update [flags] <document-id>...:
+  Update properties of one or more existing documents.
   At least one flag must be provided to specify what to update.
-  Returns the updated document as pretty-printed JSON.
+  Returns the updated document as pretty-printed JSON, or an array when
+  several documents are updated. The previous state of the documents is
+  recorded in the undo journal, and an update of several documents is undone
+  as a unit (see reader undo).
 
 Flags:
   -title          Update document title
   -author         Update document author
   -summary        Update document summary
   -location       Update document location (new, later, archive, feed)
-  -category       Update document category (article, email, rss, pdf, epub, tweet, video, highlight)
+  -category       Update document category (article, email, rss, pdf, epub, tweet, video, highlight, note)
   -image-url      Update document image URL
   -published-date Update document published date (RFC3339 format, e.g., 2023-01-01T00:00:00Z)
+  -seen           Mark document as seen (true) or unseen (false)
+  -notes          Replace document notes (see reader note to edit or append)
+
+  -clear-author, -clear-summary, -clear-image-url, -clear-published-date,
+  -clear-tags, -clear-notes
+                  Clear the field. A field cannot be both set and cleared
 `
 }
 
@@ -47,24 +67,52 @@ func (c *updateCmd) SetFlags(f *flag.FlagSet) {
 	f.StringVar(&c.title, "title", "", "Update document title")
 	f.StringVar(&c.author, "author", "", "Update document author")
 	f.StringVar(&c.summary, "summary", "", "Update document summary")
-	f.StringVar(&c.location, "location", "", "Update document location (new, later, archive, feed)")
-	f.StringVar(&c.category, "category", "", "Update document category (article, email, rss, pdf, epub, tweet, video, highlight)")
+	f.Var(&c.location, "location", "Update document location (new, later, archive, feed)")
+	f.Var(&c.category, "category", "Update document category (article, email, rss, pdf, epub, tweet, video, highlight, note)")
 	f.StringVar(&c.imageURL, "image-url", "", "Update document image URL")
 	f.StringVar(&c.publishedDate, "published-date", "", "Update document published date (RFC3339 format)")
+	f.StringVar(&c.seen, "seen", "", "Mark document as seen (true) or unseen (false)")
+	f.StringVar(&c.notes, "notes", "", "Replace document notes")
+	f.BoolVar(&c.clearAuthor, "clear-author", false, "Clear document author")
+	f.BoolVar(&c.clearSummary, "clear-summary", false, "Clear document summary")
+	f.BoolVar(&c.clearImageURL, "clear-image-url", false, "Clear document image URL")
+	f.BoolVar(&c.clearPublishedDate, "clear-published-date", false, "Clear document published date")
+	f.BoolVar(&c.clearTags, "clear-tags", false, "Remove all document tags")
+	f.BoolVar(&c.clearNotes, "clear-notes", false, "Clear document notes")
+}
+
+// clearFields returns the fields selected by the -clear flags
+func (c *updateCmd) clearFields() []reader.UpdateField {
+	var fields []reader.UpdateField
+	for _, opt := range []struct {
+		clear bool
+		field reader.UpdateField
+	}{
+		{c.clearAuthor, reader.UpdateFieldAuthor},
+		{c.clearSummary, reader.UpdateFieldSummary},
+		{c.clearImageURL, reader.UpdateFieldImageURL},
+		{c.clearPublishedDate, reader.UpdateFieldPublishedDate},
+		{c.clearTags, reader.UpdateFieldTags},
+		{c.clearNotes, reader.UpdateFieldNotes},
+	} {
+		if opt.clear {
+			fields = append(fields, opt.field)
+		}
+	}
+	return fields
 }
 
 func (c *updateCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
-	// Parse document ID from args
+	// Parse document IDs from args
 	args := f.Args()
-	if len(args) != 1 {
+	if len(args) == 0 {
 		fmt.Fprintf(os.Stderr, "Usage: %s\n", c.Usage())
 		return subcommands.ExitUsageError
 	}
-	documentID := args[0]
 
 	// Check if at least one flag is provided
 	if c.title == "" && c.author == "" && c.summary == "" && c.location == "" &&
-		c.category == "" && c.imageURL == "" && c.publishedDate == "" {
+		c.category == "" && c.imageURL == "" && c.publishedDate == "" && c.seen == "" && c.notes == "" && len(c.clearFields()) == 0 {
 		printError(fmt.Errorf("at least one field must be specified to update"))
 		return subcommands.ExitUsageError
 	}
@@ -91,48 +139,12 @@ func (c *updateCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface
 	if c.imageURL != "" {
 		req.ImageURL = c.imageURL
 	}
-
-	// Validate and set location
-	if c.location != "" {
-		switch c.location {
-		case "new":
-			req.Location = reader.LocationNew
-		case "later":
-			req.Location = reader.LocationLater
-		case "archive":
-			req.Location = reader.LocationArchive
-		case "feed":
-			req.Location = reader.LocationFeed
-		default:
-			printError(fmt.Errorf("invalid location: %s. Valid values: new, later, archive, feed", c.location))
-			return subcommands.ExitUsageError
-		}
+	if c.notes != "" {
+		req.Notes = c.notes
 	}
 
-	// Validate and set category
-	if c.category != "" {
-		switch c.category {
-		case "article":
-			req.Category = reader.CategoryArticle
-		case "email":
-			req.Category = reader.CategoryEmail
-		case "rss":
-			req.Category = reader.CategoryRSS
-		case "pdf":
-			req.Category = reader.CategoryPDF
-		case "epub":
-			req.Category = reader.CategoryEPUB
-		case "tweet":
-			req.Category = reader.CategoryTweet
-		case "video":
-			req.Category = reader.CategoryVideo
-		case "highlight":
-			req.Category = reader.CategoryHighlight
-		default:
-			printError(fmt.Errorf("invalid category: %s. Valid values: article, email, rss, pdf, epub, tweet, video, highlight", c.category))
-			return subcommands.ExitUsageError
-		}
-	}
+	req.Location = c.location
+	req.Category = c.category
 
 	// Parse and set published date
 	if c.publishedDate != "" {
@@ -144,18 +156,57 @@ func (c *updateCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface
 		req.PublishedDate = &parsedTime
 	}
 
-	// Call UpdateDocument API
-	response, err := c.client.UpdateDocument(ctx, documentID, req)
-	if err != nil {
-		printError(fmt.Errorf("failed to update document: %w", err))
-		return subcommands.ExitFailure
+	// Parse and set seen
+	if c.seen != "" {
+		seen, err := strconv.ParseBool(c.seen)
+		if err != nil {
+			printError(fmt.Errorf("invalid seen value: %s. Use true or false", c.seen))
+			return subcommands.ExitUsageError
+		}
+		req.Seen = &seen
 	}
 
+	// Set fields to clear
+	req.Clear = c.clearFields()
+	if err := req.Validate(); err != nil {
+		printError(err)
+		return subcommands.ExitUsageError
+	}
+
+	// Update each document, recording its previous state for undo
+	var responses []*reader.UpdateDocumentResponse
+	var changes []journal.Change
+	status := subcommands.ExitSuccess
+	for _, documentID := range args {
+		before, err := journal.Snapshot(ctx, c.client, documentID, false)
+		if err != nil {
+			printError(err)
+			status = subcommands.ExitFailure
+			break
+		}
+
+		response, err := c.client.UpdateDocument(ctx, documentID, req)
+		if err != nil {
+			printError(fmt.Errorf("failed to update document %s: %w", documentID, err))
+			status = subcommands.ExitFailure
+			break
+		}
+		responses = append(responses, response)
+		changes = append(changes, journal.Change{Operation: journal.OperationUpdate, DocumentID: documentID, Before: before})
+	}
+	recordChanges("reader update", changes)
+
 	// Output updated document JSON
-	if err := printJSON(response); err != nil {
-		printError(fmt.Errorf("failed to output JSON: %w", err))
-		return subcommands.ExitFailure
+	var output interface{} = responses
+	if len(args) == 1 && len(responses) == 1 {
+		output = responses[0]
+	}
+	if len(responses) > 0 {
+		if err := printJSON(output); err != nil {
+			printError(fmt.Errorf("failed to output JSON: %w", err))
+			return subcommands.ExitFailure
+		}
 	}
 
-	return subcommands.ExitSuccess
+	return status
 }
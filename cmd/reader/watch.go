@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/google/subcommands"
+	reader "github.com/tcnksm/go-readwise-reader"
+	"github.com/tcnksm/go-readwise-reader/history"
+	"github.com/tcnksm/go-readwise-reader/notify"
+)
+
+type watchCmd struct {
+	baseCommand
+	interval time.Duration
+	location reader.Location
+	notify   string
+	history  string
+}
+
+func (*watchCmd) Name() string { return "watch" }
+func (*watchCmd) Synopsis() string {
+	return "Stream document changes as JSON Lines"
+}
+func (*watchCmd) Usage() string {
+	return `watch [flags]:
+  Poll for changed documents and print each change as a JSON object per line,
+  with the same event types as webhooks (e.g., reader.document.archived,
+  reader.document.tags_updated) plus reader.document.moved,
+  reader.document.progress_updated and reader.document.metadata_updated.
+  Changes made before the command started are not printed, and deleted
+  documents are not detected. The changed documents are recorded in the
+  document history (see reader history).
+
+Flags:
+  -interval  Polling interval (e.g., 30s, 5m). Default: 1m
+  -location  Only watch documents in this location (new, later, archive, feed)
+  -notify    Path to a YAML file of notification sinks to forward events to
+  -history   Path to the document history directory. Default: $XDG_DATA_HOME/reader/history
+`
+}
+func (c *watchCmd) SetFlags(f *flag.FlagSet) {
+	f.DurationVar(&c.interval, "interval", time.Minute, "Polling interval (e.g., 30s, 5m)")
+	f.Var(&c.location, "location", "Only watch documents in this location (new, later, archive, feed)")
+	f.StringVar(&c.notify, "notify", "", "Path to a YAML file of notification sinks to forward events to")
+	f.StringVar(&c.history, "history", "", "Path to the document history directory")
+}
+
+func (c *watchCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	if f.NArg() != 0 || c.interval <= 0 {
+		fmt.Fprintf(os.Stderr, "Usage: %s\n", c.Usage())
+		return subcommands.ExitUsageError
+	}
+
+	var notifier *notify.Config
+	if c.notify != "" {
+		var err error
+		if notifier, err = loadNotifyConfig(c.notify); err != nil {
+			printError(err)
+			return subcommands.ExitFailure
+		}
+	}
+
+	store, err := openHistory(c.history)
+	if err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+
+	// Initialize client
+	if err := c.initClient(ctx); err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+
+	events, errs := reader.Watch(ctx, c.client, &reader.ListDocumentsOptions{Location: c.location}, c.interval)
+	encoder := json.NewEncoder(os.Stdout)
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return subcommands.ExitSuccess
+			}
+			if err := encoder.Encode(event); err != nil {
+				printError(fmt.Errorf("failed to output JSON: %w", err))
+				return subcommands.ExitFailure
+			}
+			if err := recordWatchEvent(store, event); err != nil {
+				logger.WarnContext(ctx, "failed to record document history", slog.String("document_id", event.Document.ID), slog.Any("error", err))
+			}
+			if notifier != nil {
+				if err := notifier.Notify(ctx, event.Payload()); err != nil {
+					logger.WarnContext(ctx, "failed to forward event", slog.String("document_id", event.Document.ID), slog.Any("error", err))
+				}
+			}
+		case err, ok := <-errs:
+			if ok {
+				// Polling is retried at the next interval
+				logger.WarnContext(ctx, "failed to poll documents", slog.Any("error", err))
+			} else {
+				errs = nil
+			}
+		}
+	}
+}
+
+// recordWatchEvent records the document of an event in the history, and the
+// previous version first so that the first change seen by watch has a
+// version to be compared with. The several events of one change record the
+// document once.
+func recordWatchEvent(store *history.Store, event reader.WatchEvent) error {
+	if event.Previous != nil {
+		if _, err := store.Record(history.SourceWatch, *event.Previous); err != nil {
+			return err
+		}
+	}
+	_, err := store.Record(history.SourceWatch, event.Document)
+	return err
+}
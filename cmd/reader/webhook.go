@@ -0,0 +1,499 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/subcommands"
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	reader "github.com/tcnksm/go-readwise-reader"
+	"github.com/tcnksm/go-readwise-reader/cmd/internal/config"
+	"github.com/tcnksm/go-readwise-reader/eventstore"
+	"github.com/tcnksm/go-readwise-reader/instrument/prometheus"
+	"github.com/tcnksm/go-readwise-reader/notify"
+	"gopkg.in/yaml.v3"
+)
+
+type webhookCmd struct{}
+
+func (*webhookCmd) Name() string { return "webhook" }
+func (*webhookCmd) Synopsis() string {
+	return "Receive Readwise Reader webhooks"
+}
+func (*webhookCmd) Usage() string {
+	return `webhook <subcommand> [flags]:
+  Receive Readwise Reader webhooks.
+
+Subcommands:
+  serve   Run an HTTP server that receives webhook events
+  replay  Handle stored webhook events again
+`
+}
+func (*webhookCmd) SetFlags(f *flag.FlagSet) {}
+
+func (c *webhookCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	return executeSubcommands(ctx, f, "reader webhook", &webhookServeCmd{}, &webhookReplayCmd{})
+}
+
+type webhookServeCmd struct {
+	baseCommand
+	addr          string
+	path          string
+	secret        string
+	rules         string
+	dryRun        bool
+	auditLog      string
+	store         string
+	retryInterval time.Duration
+	retention     string
+	notify        string
+	history       string
+	strict        bool
+}
+
+func (*webhookServeCmd) Name() string { return "serve" }
+func (*webhookServeCmd) Synopsis() string {
+	return "Run an HTTP server that receives webhook events"
+}
+func (*webhookServeCmd) Usage() string {
+	return `serve [flags]:
+  Run an HTTP server that receives Readwise Reader webhook events.
+  Each event is printed to stdout as a line of JSON. With -rules, the rules are
+  evaluated against every event and applied live, and with -notify, events are
+  forwarded to chat and notification services. Prometheus metrics are exposed
+  at /metrics.
+
+  Events are stored before they are handled. Events delivered more than once
+  are handled once, and events that fail to be handled are retried with
+  backoff. The rules applied and the sinks notified are stored with a failed
+  event, so that retries, even after a restart, only apply the rules and
+  notify the sinks that failed. Events older than -retention that are not
+  retried are removed from the store. The documents of the events are
+  recorded in the document history (see reader history), and the changes
+  from the previous version, such as the location a document was moved from,
+  are logged.
+
+  Events are only verified with a secret, so -rules requires -secret: without
+  it, anyone who can reach the server could forge events that update
+  documents. The server listens on localhost unless -addr says otherwise.
+
+Flags:
+  -addr            Address to listen on. Default: 127.0.0.1:8080
+  -path            URL path of the webhook endpoint. Default: /webhook
+  -secret          Webhook secret to verify events. Default: $READWISE_WEBHOOK_SECRET
+  -rules           Path to a YAML rules file to apply to events
+  -dry-run         Only show which rules would fire
+  -audit-log       Path to the rules audit log. Default: $XDG_DATA_HOME/reader/rules-audit.jsonl
+  -store           Path to the event store. Default: $XDG_DATA_HOME/reader/webhook-events.jsonl
+  -retry-interval  How often to retry events that failed to be handled. Default: 1m
+  -retention       How long to keep handled events for deduplication and replay. Default: 7d
+  -notify          Path to a YAML file of notification sinks to forward events to
+  -history         Path to the document history directory. Default: $XDG_DATA_HOME/reader/history
+  -strict          Reject events with unknown fields or event types
+`
+}
+func (c *webhookServeCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&c.addr, "addr", "127.0.0.1:8080", "Address to listen on")
+	f.StringVar(&c.path, "path", "/webhook", "URL path of the webhook endpoint")
+	f.StringVar(&c.secret, "secret", os.Getenv("READWISE_WEBHOOK_SECRET"), "Webhook secret to verify events")
+	f.StringVar(&c.rules, "rules", "", "Path to a YAML rules file to apply to events")
+	f.BoolVar(&c.dryRun, "dry-run", false, "Only show which rules would fire")
+	f.StringVar(&c.auditLog, "audit-log", "", "Path to the rules audit log")
+	f.StringVar(&c.store, "store", "", "Path to the event store")
+	f.DurationVar(&c.retryInterval, "retry-interval", time.Minute, "How often to retry events that failed to be handled")
+	f.StringVar(&c.retention, "retention", "7d", "How long to keep handled events for deduplication and replay")
+	f.StringVar(&c.notify, "notify", "", "Path to a YAML file of notification sinks to forward events to")
+	f.StringVar(&c.history, "history", "", "Path to the document history directory")
+	f.BoolVar(&c.strict, "strict", false, "Reject events with unknown fields or event types")
+}
+
+func (c *webhookServeCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	retention, err := parseDuration(c.retention)
+	if err != nil {
+		printError(fmt.Errorf("invalid duration format: %s. Use formats like 24h, 7d, 2w", c.retention))
+		return subcommands.ExitUsageError
+	}
+
+	// Set up metrics
+	registry := prom.NewRegistry()
+	instrumentation, err := prometheus.New(registry)
+	if err != nil {
+		printError(fmt.Errorf("failed to register metrics: %w", err))
+		return subcommands.ExitFailure
+	}
+	events := prom.NewCounterVec(prom.CounterOpts{
+		Name: "reader_webhook_events_total",
+		Help: "Number of received webhook events by event type.",
+	}, []string{"event_type"})
+	registry.MustRegister(events)
+
+	var ruleSet *reader.RuleSet
+	var auditLog string
+	if c.rules != "" {
+		if c.secret == "" {
+			printError(errors.New("-rules requires -secret or $READWISE_WEBHOOK_SECRET, so that forged events cannot update documents"))
+			return subcommands.ExitUsageError
+		}
+		if ruleSet, err = loadRuleSet(c.rules); err != nil {
+			printError(err)
+			return subcommands.ExitFailure
+		}
+		if auditLog, err = auditLogPath(c.auditLog); err != nil {
+			printError(err)
+			return subcommands.ExitFailure
+		}
+
+		// Initialize client
+		if err := c.initClient(ctx, reader.WithInstrumentation(instrumentation)); err != nil {
+			printError(err)
+			return subcommands.ExitFailure
+		}
+	}
+
+	var notifier *notify.Config
+	if c.notify != "" {
+		if notifier, err = loadNotifyConfig(c.notify); err != nil {
+			printError(err)
+			return subcommands.ExitFailure
+		}
+	}
+
+	store, err := openEventStore(c.store)
+	if err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+	versions, err := openHistory(c.history)
+	if err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+	sink := newWebhookSink(c.client, ruleSet, c.dryRun, auditLog, notifier).handle
+
+	var decodeOpts []reader.DecodeOption
+	if c.strict {
+		decodeOpts = append(decodeOpts, reader.DisallowUnknownFields(), reader.DisallowUnknownEventTypes())
+	}
+	handler := reader.NewWebhookHandler(c.secret, func(ctx context.Context, payload *reader.DocumentWebhookPayload) error {
+		events.WithLabelValues(string(payload.EventType)).Inc()
+		logger.InfoContext(ctx, "received webhook event",
+			slog.String("event_type", string(payload.EventType)),
+			slog.String("document_id", payload.ID),
+		)
+
+		event, added, err := store.Add(payload)
+		if err != nil {
+			// Let Readwise retry the event
+			return err
+		}
+		if !added {
+			logger.InfoContext(ctx, "ignored duplicate webhook event", slog.String("key", event.Key))
+			return nil
+		}
+
+		if version, err := versions.RecordPayload(payload); err != nil {
+			logger.WarnContext(ctx, "failed to record document history", slog.String("document_id", payload.ID), slog.Any("error", err))
+		} else if version != nil && len(version.Changes) > 0 {
+			logger.InfoContext(ctx, "document changed",
+				slog.String("document_id", payload.ID),
+				slog.Any("changes", version.Changes),
+			)
+		}
+
+		// The event is stored, so a failure is retried later rather than by
+		// Readwise
+		if err := store.Deliver(ctx, event, sink); err != nil {
+			logger.WarnContext(ctx, "failed to handle webhook event", slog.String("key", event.Key), slog.Any("error", err))
+		}
+		return nil
+	}, decodeOpts...)
+
+	prune := func() {
+		n, err := store.Prune(time.Now().Add(-retention))
+		if n > 0 {
+			logger.InfoContext(ctx, "pruned webhook events", slog.Int("removed", n))
+		}
+		if err != nil {
+			logger.WarnContext(ctx, "failed to prune webhook events", slog.Any("error", err))
+		}
+	}
+	prune()
+
+	go func() {
+		lastPrune := time.Now()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(c.retryInterval):
+			}
+			if time.Since(lastPrune) > time.Hour {
+				prune()
+				lastPrune = time.Now()
+			}
+			n, err := store.Retry(ctx, sink)
+			if n > 0 {
+				logger.InfoContext(ctx, "retried webhook events", slog.Int("delivered", n))
+			}
+			if err != nil {
+				logger.WarnContext(ctx, "failed to retry webhook events", slog.Any("error", err))
+			}
+		}
+	}()
+
+	mux := http.NewServeMux()
+	mux.Handle(c.path, handler)
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+
+	if c.secret == "" {
+		logger.Warn("webhook events are not verified without a secret")
+	}
+	logger.Info("listening for webhooks", slog.String("addr", c.addr), slog.String("path", c.path))
+	if err := newHTTPServer(c.addr, mux).ListenAndServe(); err != nil {
+		printError(fmt.Errorf("server error: %w", err))
+		return subcommands.ExitFailure
+	}
+
+	return subcommands.ExitSuccess
+}
+
+type webhookReplayCmd struct {
+	baseCommand
+	since    string
+	rules    string
+	dryRun   bool
+	auditLog string
+	store    string
+	notify   string
+}
+
+func (*webhookReplayCmd) Name() string { return "replay" }
+func (*webhookReplayCmd) Synopsis() string {
+	return "Handle stored webhook events again"
+}
+func (*webhookReplayCmd) Usage() string {
+	return `replay [flags]:
+  Handle the webhook events stored by serve that were received since the given
+  duration ago again, in the order their documents were updated. Each event is
+  printed to stdout as a line of JSON, with -rules, the rules are applied, and
+  with -notify, events are forwarded to notification sinks. Replay stops at the
+  first event that fails to be handled.
+
+Flags:
+  -since      Replay events received since duration ago (e.g., 30m, 24h, 7d). Default: 24h
+  -rules      Path to a YAML rules file to apply to events
+  -dry-run    Only show which rules would fire
+  -audit-log  Path to the rules audit log. Default: $XDG_DATA_HOME/reader/rules-audit.jsonl
+  -store      Path to the event store. Default: $XDG_DATA_HOME/reader/webhook-events.jsonl
+  -notify     Path to a YAML file of notification sinks to forward events to
+`
+}
+func (c *webhookReplayCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&c.since, "since", "24h", "Replay events received since duration ago (e.g., 30m, 24h, 7d)")
+	f.StringVar(&c.rules, "rules", "", "Path to a YAML rules file to apply to events")
+	f.BoolVar(&c.dryRun, "dry-run", false, "Only show which rules would fire")
+	f.StringVar(&c.auditLog, "audit-log", "", "Path to the rules audit log")
+	f.StringVar(&c.store, "store", "", "Path to the event store")
+	f.StringVar(&c.notify, "notify", "", "Path to a YAML file of notification sinks to forward events to")
+}
+
+func (c *webhookReplayCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	if f.NArg() != 0 {
+		fmt.Fprintf(os.Stderr, "Usage: %s\n", c.Usage())
+		return subcommands.ExitUsageError
+	}
+	duration, err := parseDuration(c.since)
+	if err != nil {
+		printError(fmt.Errorf("invalid duration format: %s. Use formats like 30m, 24h, 7d", c.since))
+		return subcommands.ExitUsageError
+	}
+
+	var ruleSet *reader.RuleSet
+	var auditLog string
+	if c.rules != "" {
+		if ruleSet, err = loadRuleSet(c.rules); err != nil {
+			printError(err)
+			return subcommands.ExitFailure
+		}
+		if auditLog, err = auditLogPath(c.auditLog); err != nil {
+			printError(err)
+			return subcommands.ExitFailure
+		}
+
+		// Initialize client
+		if err := c.initClient(ctx); err != nil {
+			printError(err)
+			return subcommands.ExitFailure
+		}
+	}
+
+	var notifier *notify.Config
+	if c.notify != "" {
+		if notifier, err = loadNotifyConfig(c.notify); err != nil {
+			printError(err)
+			return subcommands.ExitFailure
+		}
+	}
+
+	store, err := openEventStore(c.store)
+	if err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+
+	n, err := store.Replay(ctx, time.Now().Add(-duration), newWebhookSink(c.client, ruleSet, c.dryRun, auditLog, notifier).handle)
+	logger.InfoContext(ctx, "replayed webhook events", slog.Int("delivered", n))
+	if err != nil {
+		printError(err)
+		return subcommands.ExitFailure
+	}
+	return subcommands.ExitSuccess
+}
+
+// openEventStore opens the webhook event store at path, or in the data
+// directory if path is empty
+func openEventStore(path string) (*eventstore.Store, error) {
+	if path == "" {
+		dir, err := config.DataDir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(dir, "webhook-events.jsonl")
+	}
+	return eventstore.Open(path), nil
+}
+
+// webhookSink prints webhook events as JSON lines, applies the rules, if
+// any, to them and forwards them to the notification sinks, if any. Each of
+// these parts is recorded in the eventstore.Progress of the delivery when it
+// succeeds, so that a failed event is retried by the event store, even after
+// a restart, without printing it, applying rules or notifying sinks again.
+type webhookSink struct {
+	client   reader.Client
+	ruleSet  *reader.RuleSet
+	dryRun   bool
+	auditLog string
+	notifier *notify.Config
+
+	// mu guards encoder and the audit log. Events may be delivered
+	// concurrently, and mu is not held while calling the API or the sinks.
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+// newWebhookSink returns a sink that prints events to stdout
+func newWebhookSink(client reader.Client, ruleSet *reader.RuleSet, dryRun bool, auditLog string, notifier *notify.Config) *webhookSink {
+	return &webhookSink{
+		client:   client,
+		ruleSet:  ruleSet,
+		dryRun:   dryRun,
+		auditLog: auditLog,
+		notifier: notifier,
+		encoder:  json.NewEncoder(os.Stdout),
+	}
+}
+
+// handle handles an event. It has the signature of a
+// reader.WebhookHandlerFunc.
+func (s *webhookSink) handle(ctx context.Context, payload *reader.DocumentWebhookPayload) error {
+	progress := eventstore.ProgressFromContext(ctx)
+
+	if !progress.Done("print") {
+		// The secret is not printed
+		printed := *payload
+		printed.Secret = ""
+		s.mu.Lock()
+		err := s.encoder.Encode(&printed)
+		s.mu.Unlock()
+		if err != nil {
+			return err
+		}
+		progress.Complete("print")
+	}
+
+	var errs []error
+	if s.ruleSet != nil {
+		errs = append(errs, s.applyRules(ctx, progress, payload))
+	}
+	if s.notifier != nil {
+		for i := range s.notifier.Sinks {
+			sink := &s.notifier.Sinks[i]
+			part := fmt.Sprintf("notify %d %s", i, sink.Name)
+			if progress.Done(part) || !sink.When.Match(payload) {
+				continue
+			}
+			if err := s.notifier.NotifySink(ctx, sink, payload); err != nil {
+				errs = append(errs, fmt.Errorf("failed to notify %s: %w", sink.Name, err))
+				continue
+			}
+			progress.Complete(part)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// applyRules applies the rule matches of an event that were not applied in
+// an earlier delivery
+func (s *webhookSink) applyRules(ctx context.Context, progress *eventstore.Progress, payload *reader.DocumentWebhookPayload) error {
+	// Rules are evaluated against the payload, so the matches are the same
+	// in every delivery
+	var matches []reader.RuleMatch
+	var parts []string
+	for i, m := range s.ruleSet.EvaluateWebhook(payload) {
+		part := fmt.Sprintf("rule %d %s", i, m.Rule)
+		if !progress.Done(part) {
+			matches = append(matches, m)
+			parts = append(parts, part)
+		}
+	}
+	if len(matches) == 0 {
+		return nil
+	}
+
+	results := reader.ApplyRuleMatches(ctx, s.client, matches, s.dryRun)
+	var errs []error
+	for i, r := range results {
+		if r.Error != "" {
+			errs = append(errs, fmt.Errorf("failed to apply rule %q to document %s: %s", r.Rule, r.DocumentID, r.Error))
+			continue
+		}
+		progress.Complete(parts[i])
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// The rules were applied, so a failure to record them must not apply
+	// them again
+	if err := appendJSONLines(s.auditLog, results); err != nil {
+		logger.WarnContext(ctx, "failed to write audit log", slog.String("document_id", payload.ID), slog.Any("error", err))
+	}
+	return errors.Join(errs...)
+}
+
+// loadNotifyConfig reads and compiles the YAML notification sinks file at path
+func loadNotifyConfig(path string) (*notify.Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read notification sinks: %w", err)
+	}
+
+	var cfg notify.Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse notification sinks: %w", err)
+	}
+	if err := cfg.Compile(); err != nil {
+		return nil, fmt.Errorf("invalid notification sinks: %w", err)
+	}
+
+	return &cfg, nil
+}
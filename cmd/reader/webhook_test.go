@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	reader "github.com/tcnksm/go-readwise-reader"
+	"github.com/tcnksm/go-readwise-reader/eventstore"
+	"github.com/tcnksm/go-readwise-reader/notify"
+)
+
+func TestWebhookSink_RetryFailedRules(t *testing.T) {
+	var notified atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		notified.Add(1)
+	}))
+	t.Cleanup(server.Close)
+	notifier := &notify.Config{Sinks: []notify.Sink{{Name: "chat", Type: notify.SinkSlack, URL: server.URL}}}
+	if err := notifier.Compile(); err != nil {
+		t.Fatal(err)
+	}
+	ruleSet := &reader.RuleSet{Rules: []reader.Rule{{
+		Name: "archive finished",
+		When: reader.RuleCondition{EventType: reader.EventDocumentFinished},
+		Then: reader.RuleAction{Location: reader.LocationArchive},
+	}}}
+	if err := ruleSet.Compile(); err != nil {
+		t.Fatal(err)
+	}
+
+	client := newFakeClient()
+	client.updateErr = errors.New("server error")
+	dir := t.TempDir()
+	auditLog := filepath.Join(dir, "audit.jsonl")
+	var out bytes.Buffer
+	newSink := func() *webhookSink {
+		sink := newWebhookSink(client, ruleSet, false, auditLog, notifier)
+		sink.encoder = json.NewEncoder(&out)
+		return sink
+	}
+
+	store := eventstore.Open(filepath.Join(dir, "events.jsonl"))
+	event, _, err := store.Add(&reader.DocumentWebhookPayload{
+		EventType: reader.EventDocumentFinished,
+		ID:        "doc1",
+		Location:  reader.LocationNew,
+		Secret:    "s3cret",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.Deliver(context.Background(), event, newSink().handle)
+	if err == nil || !strings.Contains(err.Error(), `failed to apply rule "archive finished"`) {
+		t.Fatalf("Deliver() error = %v, want rule failure", err)
+	}
+
+	// After a restart, the event store delivers the event again
+	client.updateErr = nil
+	store = eventstore.Open(filepath.Join(dir, "events.jsonl"))
+	events, err := store.Events(time.Time{})
+	if err != nil || len(events) != 1 {
+		t.Fatalf("Events() = %+v, %v", events, err)
+	}
+	if err := store.Deliver(context.Background(), events[0], newSink().handle); err != nil {
+		t.Fatalf("Deliver() retry error = %v", err)
+	}
+
+	if len(client.updates) != 2 || client.updates[1].req.Location != reader.LocationArchive {
+		t.Errorf("updates = %+v, want the failed rule applied again", client.updates)
+	}
+	if got := notified.Load(); got != 1 {
+		t.Errorf("notified %d times, want 1", got)
+	}
+	if lines := strings.Count(out.String(), "\n"); lines != 1 {
+		t.Errorf("printed %d events, want 1", lines)
+	}
+	if strings.Contains(out.String(), "s3cret") {
+		t.Errorf("printed the secret: %s", out.String())
+	}
+	data, err := os.ReadFile(auditLog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 2 {
+		t.Errorf("audit log has %d lines, want 2", lines)
+	}
+}
@@ -29,12 +29,11 @@ type CreateDocumentRequest struct {
 	// Tags is a list of tags to associate with the document (optional)
 	Tags []string `json:"tags,omitempty"`
 
-	// Location is where the document should be initially stored (optional)
-	// Valid values: "new", "later", "archive", "feed"
+	// Location is where the document should be initially stored (optional).
+	// See AllLocations.
 	Location Location `json:"location,omitempty"`
 
-	// Category is the document type (optional)
-	// Valid values: "article", "email", "rss", "pdf", "epub", "tweet", "video", "highlight"
+	// Category is the document type (optional). See AllCategories.
 	Category Category `json:"category,omitempty"`
 
 	// ImageURL is the URL of an image associated with the document (optional)
@@ -46,6 +45,12 @@ type CreateDocumentRequest struct {
 	// ShouldCleanHTML instructs Readwise to clean the provided HTML and parse metadata (optional)
 	// Only valid when HTML is provided. Defaults to false.
 	ShouldCleanHTML bool `json:"should_clean_html,omitempty"`
+
+	// CheckDuplicate makes CreateDocument look for an already saved document with
+	// the same canonical URL before saving, and return a *DuplicateDocumentError
+	// if one exists (optional). This scans the whole library, so it is slow for
+	// large libraries.
+	CheckDuplicate bool `json:"-"`
 }
 
 // CreateDocumentResponse represents the response from creating a document
@@ -57,6 +62,18 @@ type CreateDocumentResponse struct {
 	URL string `json:"url"`
 }
 
+// Validate checks that the location and category are valid. CreateDocument
+// validates requests before sending them.
+func (r *CreateDocumentRequest) Validate() error {
+	if r.Location != "" && !r.Location.Valid() {
+		return invalidLocation(string(r.Location))
+	}
+	if r.Category != "" && !r.Category.Valid() {
+		return invalidCategory(string(r.Category))
+	}
+	return nil
+}
+
 // CreateDocument creates a new document in Readwise Reader
 func (c *client) CreateDocument(ctx context.Context, url string, req *CreateDocumentRequest) (*CreateDocumentResponse, error) {
 	if url == "" {
@@ -70,6 +87,26 @@ func (c *client) CreateDocument(ctx context.Context, url string, req *CreateDocu
 		req = &CreateDocumentRequest{}
 	}
 
+	if err := req.Validate(); err != nil {
+		return nil, &ClientError{
+			Type:    "invalid_parameter",
+			Message: err.Error(),
+		}
+	}
+
+	if req.CheckDuplicate {
+		existing, err := c.findDocumentByURL(ctx, url)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check duplicate: %w", err)
+		}
+		if existing != nil {
+			return nil, &DuplicateDocumentError{
+				URL:      url,
+				Existing: *existing,
+			}
+		}
+	}
+
 	// Create request body with URL and other fields
 	reqBody := struct {
 		URL string `json:"url"`
@@ -97,7 +134,7 @@ func (c *client) CreateDocument(ctx context.Context, url string, req *CreateDocu
 	httpReq.Header.Set("Accept", "application/json")
 
 	// Execute request
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.do(httpReq, "save", "")
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
@@ -123,3 +160,29 @@ func (c *client) CreateDocument(ctx context.Context, url string, req *CreateDocu
 
 	return &response, nil
 }
+
+// findDocumentByURL returns the saved document whose URL or SourceURL has the
+// same canonical form as rawURL, or nil if there is none
+func (c *client) findDocumentByURL(ctx context.Context, rawURL string) (*Document, error) {
+	key, err := Canonicalize(rawURL)
+	if err != nil {
+		key = rawURL
+	}
+
+	documents, err := ListAllDocuments(ctx, c, nil)
+	if err != nil {
+		return nil, err
+	}
+	for i, doc := range documents {
+		for _, u := range []string{doc.URL, doc.SourceURL} {
+			if u == "" {
+				continue
+			}
+			if k, err := Canonicalize(u); err == nil && k == key {
+				return &documents[i], nil
+			}
+		}
+	}
+
+	return nil, nil
+}
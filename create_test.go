@@ -202,3 +202,79 @@ func TestClient_CreateDocument(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_CreateDocument_CheckDuplicate(t *testing.T) {
+	tests := []struct {
+		name          string
+		url           string
+		wantDuplicate bool
+	}{
+		{
+			name:          "duplicate",
+			url:           "http://www.example.com/article/?utm_campaign=x",
+			wantDuplicate: true,
+		},
+		{
+			name:          "not duplicate",
+			url:           "https://example.com/another",
+			wantDuplicate: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved bool
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Path {
+				case "/list/":
+					json.NewEncoder(w).Encode(ListDocumentsResponse{
+						Count: 1,
+						Results: []Document{
+							{ID: "existing", SourceURL: "https://example.com/article"},
+						},
+					})
+				case "/save/":
+					saved = true
+					var body map[string]interface{}
+					json.NewDecoder(r.Body).Decode(&body)
+					if _, ok := body["CheckDuplicate"]; ok {
+						t.Error("CheckDuplicate must not be sent to the API")
+					}
+					w.WriteHeader(http.StatusCreated)
+					w.Write([]byte(`{"id": "new", "url": "https://example.com/another"}`))
+				}
+			}))
+			defer server.Close()
+
+			c := &client{
+				baseURL:    server.URL,
+				token:      "test-token",
+				httpClient: &http.Client{},
+			}
+
+			_, err := c.CreateDocument(context.Background(), tt.url, &CreateDocumentRequest{CheckDuplicate: true})
+
+			var dupErr *DuplicateDocumentError
+			if tt.wantDuplicate {
+				if !errors.As(err, &dupErr) {
+					t.Fatalf("Expected DuplicateDocumentError, got %v", err)
+				}
+				if dupErr.Existing.ID != "existing" {
+					t.Errorf("Existing.ID = %v, want existing", dupErr.Existing.ID)
+				}
+				if saved {
+					t.Error("Document was saved despite duplicate")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !saved {
+				t.Error("Document was not saved")
+			}
+		})
+	}
+}
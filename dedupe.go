@@ -0,0 +1,265 @@
+package reader
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// DefaultTitleSimilarity is the default minimum title similarity for two
+// documents to be considered duplicates
+const DefaultTitleSimilarity = 0.9
+
+// FindDuplicatesOptions holds options for finding duplicate documents
+type FindDuplicatesOptions struct {
+	// TitleSimilarity is the minimum Jaccard similarity (0-1) of the title words
+	// for two documents by the same author or from the same site to be grouped
+	// together. Zero disables title matching.
+	TitleSimilarity float64
+}
+
+// DuplicateGroup is a set of documents that point at the same content, together
+// with a plan for merging them into a single document
+type DuplicateGroup struct {
+	// Keep is the document that survives the merge (the most-read copy)
+	Keep Document `json:"keep"`
+
+	// Duplicates are the documents to delete after merging
+	Duplicates []Document `json:"duplicates"`
+
+	// Tags is the union of the tags of every document in the group
+	Tags []string `json:"tags"`
+
+	// Notes is the notes of every document in the group joined together
+	Notes string `json:"notes"`
+}
+
+// DuplicateDocumentError is returned by CreateDocument when
+// CreateDocumentRequest.CheckDuplicate is set and the URL is already saved
+type DuplicateDocumentError struct {
+	// URL is the URL that was about to be saved
+	URL string
+
+	// Existing is the document already saved with the same canonical URL
+	Existing Document
+}
+
+func (e *DuplicateDocumentError) Error() string {
+	return fmt.Sprintf("document already exists: %s (id: %s)", e.URL, e.Existing.ID)
+}
+
+// FindDuplicates groups documents whose canonical URL or SourceURL match, or
+// whose titles are similar enough and that have the same author or site name,
+// and returns a merge plan for each group with more than one document. As
+// recurring newsletters and series share their titles, documents published
+// on different days are not grouped by title. Highlights and notes are never
+// grouped. Groups are ordered by the ID of the kept document.
+func FindDuplicates(documents []Document, opts *FindDuplicatesOptions) []DuplicateGroup {
+	if opts == nil {
+		opts = &FindDuplicatesOptions{TitleSimilarity: DefaultTitleSimilarity}
+	}
+
+	parent := make([]int, len(documents))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	union := func(i, j int) {
+		if ri, rj := find(i), find(j); ri != rj {
+			parent[rj] = ri
+		}
+	}
+
+	// Group by canonical URL and SourceURL
+	seen := make(map[string]int)
+	for i, doc := range documents {
+		if doc.ParentID != "" {
+			continue
+		}
+		for _, raw := range []string{doc.URL, doc.SourceURL} {
+			if raw == "" {
+				continue
+			}
+			key, err := Canonicalize(raw)
+			if err != nil {
+				continue
+			}
+			if j, ok := seen[key]; ok {
+				union(j, i)
+			} else {
+				seen[key] = i
+			}
+		}
+	}
+
+	// Group by title similarity
+	if opts.TitleSimilarity > 0 {
+		words := make([]map[string]bool, len(documents))
+		for i, doc := range documents {
+			if doc.ParentID == "" {
+				words[i] = titleWords(doc.Title)
+			}
+		}
+		for i := range documents {
+			if len(words[i]) == 0 {
+				continue
+			}
+			for j := i + 1; j < len(documents); j++ {
+				if len(words[j]) == 0 || find(i) == find(j) || !sameOrigin(documents[i], documents[j]) {
+					continue
+				}
+				if jaccard(words[i], words[j]) >= opts.TitleSimilarity {
+					union(i, j)
+				}
+			}
+		}
+	}
+
+	members := make(map[int][]Document)
+	for i, doc := range documents {
+		root := find(i)
+		members[root] = append(members[root], doc)
+	}
+
+	var groups []DuplicateGroup
+	for _, docs := range members {
+		if len(docs) < 2 {
+			continue
+		}
+		groups = append(groups, newDuplicateGroup(docs))
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Keep.ID < groups[j].Keep.ID
+	})
+
+	return groups
+}
+
+// MergeDuplicates applies the merge plan of a group: it copies the merged tags
+// and notes onto the kept document and deletes the duplicates.
+func MergeDuplicates(ctx context.Context, c Client, group DuplicateGroup) error {
+	req := &UpdateDocumentRequest{}
+	if !slices.Equal(group.Tags, group.Keep.TagNames()) {
+		req.Tags = group.Tags
+	}
+	if group.Notes != group.Keep.Notes {
+		req.Notes = group.Notes
+	}
+	if req.Tags != nil || req.Notes != "" {
+		if _, err := c.UpdateDocument(ctx, group.Keep.ID, req); err != nil {
+			return fmt.Errorf("failed to update document %s: %w", group.Keep.ID, err)
+		}
+	}
+
+	for _, doc := range group.Duplicates {
+		if err := c.DeleteDocument(ctx, doc.ID); err != nil {
+			return fmt.Errorf("failed to delete document %s: %w", doc.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// newDuplicateGroup picks the most-read document of docs to keep and merges
+// the tags and notes of the others into it
+func newDuplicateGroup(docs []Document) DuplicateGroup {
+	sort.SliceStable(docs, func(i, j int) bool {
+		return moreRead(docs[i], docs[j])
+	})
+
+	group := DuplicateGroup{
+		Keep:       docs[0],
+		Duplicates: docs[1:],
+	}
+
+	tags := make(map[string]bool)
+	var notes []string
+	for _, doc := range docs {
+		for _, tag := range doc.TagNames() {
+			tags[tag] = true
+		}
+		note := strings.TrimSpace(doc.Notes)
+		if note != "" && !slices.Contains(notes, note) {
+			notes = append(notes, note)
+		}
+	}
+	group.Tags = make([]string, 0, len(tags))
+	for tag := range tags {
+		group.Tags = append(group.Tags, tag)
+	}
+	sort.Strings(group.Tags)
+	group.Notes = strings.Join(notes, "\n\n")
+
+	return group
+}
+
+// moreRead reports whether a should be kept in preference to b
+func moreRead(a, b Document) bool {
+	if a.ReadingProgressPercent != b.ReadingProgressPercent {
+		return a.ReadingProgressPercent > b.ReadingProgressPercent
+	}
+	if (a.FirstOpenedAt != nil) != (b.FirstOpenedAt != nil) {
+		return a.FirstOpenedAt != nil
+	}
+	if a.SavedAt != nil && b.SavedAt != nil && !a.SavedAt.Equal(*b.SavedAt) {
+		return a.SavedAt.Before(*b.SavedAt)
+	}
+	return a.ID < b.ID
+}
+
+// sameOrigin reports whether two documents have the same author or site name
+// and, if both are known, the same published day
+func sameOrigin(a, b Document) bool {
+	if !equalFold(a.Author, b.Author) && !equalFold(a.SiteName, b.SiteName) {
+		return false
+	}
+	ta, oka := a.PublishedTime()
+	tb, okb := b.PublishedTime()
+	if !oka || !okb {
+		return true
+	}
+	return ta.UTC().Format(time.DateOnly) == tb.UTC().Format(time.DateOnly)
+}
+
+// equalFold reports whether two non-empty strings are equal ignoring case and
+// surrounding spaces
+func equalFold(a, b string) bool {
+	a, b = strings.TrimSpace(a), strings.TrimSpace(b)
+	return a != "" && strings.EqualFold(a, b)
+}
+
+// titleWords returns the set of lowercased words in a title
+func titleWords(title string) map[string]bool {
+	words := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	}) {
+		words[word] = true
+	}
+	return words
+}
+
+// jaccard returns the Jaccard similarity of two word sets
+func jaccard(a, b map[string]bool) float64 {
+	intersection := 0
+	for word := range a {
+		if b[word] {
+			intersection++
+		}
+	}
+	union := len(a) + len(b) - intersection
+	if union == 0 {
+		return 0
+	}
+	return float64(intersection) / float64(union)
+}
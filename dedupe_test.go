@@ -0,0 +1,175 @@
+package reader
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFindDuplicates(t *testing.T) {
+	documents := []Document{
+		{
+			ID:                     "doc1",
+			SourceURL:              "https://example.com/article?utm_source=rss",
+			Title:                  "Understanding Go Interfaces",
+			Tags:                   map[string]interface{}{"go": true},
+			Notes:                  "first note",
+			ReadingProgressPercent: 10,
+		},
+		{
+			ID:                     "doc2",
+			SourceURL:              "https://www.example.com/article/",
+			Title:                  "Understanding Go Interfaces",
+			Author:                 "Jane Doe",
+			Tags:                   map[string]interface{}{"programming": true},
+			ReadingProgressPercent: 80,
+		},
+		{
+			ID:        "doc3",
+			SourceURL: "https://other.example.org/post",
+			Title:     "Understanding Go Interfaces!",
+			Author:    "jane doe",
+			Notes:     "second note",
+		},
+		{
+			ID:        "doc4",
+			SourceURL: "https://example.com/unrelated",
+			Title:     "Something Else Entirely",
+		},
+	}
+
+	t.Run("URL and title", func(t *testing.T) {
+		groups := FindDuplicates(documents, nil)
+		if len(groups) != 1 {
+			t.Fatalf("len(groups) = %d, want 1", len(groups))
+		}
+		g := groups[0]
+		if g.Keep.ID != "doc2" {
+			t.Errorf("Keep.ID = %v, want doc2", g.Keep.ID)
+		}
+		var ids []string
+		for _, doc := range g.Duplicates {
+			ids = append(ids, doc.ID)
+		}
+		if !reflect.DeepEqual(ids, []string{"doc1", "doc3"}) {
+			t.Errorf("Duplicates = %v, want [doc1 doc3]", ids)
+		}
+		if !reflect.DeepEqual(g.Tags, []string{"go", "programming"}) {
+			t.Errorf("Tags = %v, want [go programming]", g.Tags)
+		}
+		if g.Notes != "first note\n\nsecond note" {
+			t.Errorf("Notes = %q", g.Notes)
+		}
+	})
+
+	t.Run("URL only", func(t *testing.T) {
+		groups := FindDuplicates(documents, &FindDuplicatesOptions{})
+		if len(groups) != 1 {
+			t.Fatalf("len(groups) = %d, want 1", len(groups))
+		}
+		if len(groups[0].Duplicates) != 1 || groups[0].Duplicates[0].ID != "doc1" {
+			t.Errorf("Duplicates = %v, want [doc1]", groups[0].Duplicates)
+		}
+	})
+
+	t.Run("no duplicates", func(t *testing.T) {
+		if groups := FindDuplicates(documents[3:], nil); len(groups) != 0 {
+			t.Errorf("len(groups) = %d, want 0", len(groups))
+		}
+	})
+}
+
+func TestFindDuplicates_SameTitle(t *testing.T) {
+	documents := []Document{
+		// Issues of a newsletter
+		{ID: "doc1", URL: "https://example.com/digest/1", Title: "Weekly digest", Author: "Example", PublishedDate: "2024-01-01"},
+		{ID: "doc2", URL: "https://example.com/digest/2", Title: "Weekly digest", Author: "Example", PublishedDate: "2024-01-08"},
+		// Unrelated posts
+		{ID: "doc3", URL: "https://a.example.com/intro", Title: "Introduction", Author: "Alice"},
+		{ID: "doc4", URL: "https://b.example.com/intro", Title: "Introduction", Author: "Bob"},
+		{ID: "doc5", URL: "https://c.example.com/intro", Title: "Introduction"},
+		// Highlights of the same document
+		{ID: "doc6", URL: "https://read.readwise.io/read/doc1", Title: "Weekly digest", Author: "Example", ParentID: "doc1"},
+		{ID: "doc7", URL: "https://read.readwise.io/read/doc1", Title: "Weekly digest", Author: "Example", ParentID: "doc1"},
+	}
+
+	if groups := FindDuplicates(documents, nil); len(groups) != 0 {
+		t.Errorf("FindDuplicates() = %+v, want no groups", groups)
+	}
+}
+
+func TestFindDuplicates_KeepPreference(t *testing.T) {
+	earlier := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	later := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	documents := []Document{
+		{ID: "doc1", SourceURL: "https://example.com/a", SavedAt: &later},
+		{ID: "doc2", SourceURL: "https://example.com/a", SavedAt: &earlier},
+		{ID: "doc3", SourceURL: "https://example.com/a", SavedAt: &later, FirstOpenedAt: &later},
+	}
+
+	groups := FindDuplicates(documents, &FindDuplicatesOptions{})
+	if len(groups) != 1 {
+		t.Fatalf("len(groups) = %d, want 1", len(groups))
+	}
+	if groups[0].Keep.ID != "doc3" {
+		t.Errorf("Keep.ID = %v, want doc3", groups[0].Keep.ID)
+	}
+	if groups[0].Duplicates[0].ID != "doc2" {
+		t.Errorf("Duplicates[0].ID = %v, want doc2", groups[0].Duplicates[0].ID)
+	}
+}
+
+func TestMergeDuplicates(t *testing.T) {
+	var updated map[string]interface{}
+	var deleted []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodPatch:
+			if r.URL.Path != "/update/doc2/" {
+				t.Errorf("unexpected update path %s", r.URL.Path)
+			}
+			json.NewDecoder(r.Body).Decode(&updated)
+			json.NewEncoder(w).Encode(UpdateDocumentResponse{ID: "doc2"})
+		case http.MethodDelete:
+			deleted = append(deleted, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected method %s", r.Method)
+		}
+	}))
+	defer server.Close()
+
+	c := &client{
+		baseURL:    server.URL,
+		token:      "test-token",
+		httpClient: &http.Client{},
+	}
+
+	group := DuplicateGroup{
+		Keep:       Document{ID: "doc2", Tags: map[string]interface{}{"go": true}},
+		Duplicates: []Document{{ID: "doc1"}, {ID: "doc3"}},
+		Tags:       []string{"go", "programming"},
+		Notes:      "merged note",
+	}
+
+	if err := MergeDuplicates(context.Background(), c, group); err != nil {
+		t.Fatalf("MergeDuplicates() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(updated["tags"], []interface{}{"go", "programming"}) {
+		t.Errorf("updated tags = %v", updated["tags"])
+	}
+	if updated["notes"] != "merged note" {
+		t.Errorf("updated notes = %v", updated["notes"])
+	}
+	if !reflect.DeepEqual(deleted, []string{"/delete/doc1/", "/delete/doc3/"}) {
+		t.Errorf("deleted = %v", deleted)
+	}
+}
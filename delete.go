@@ -27,7 +27,7 @@ func (c *client) DeleteDocument(ctx context.Context, documentID string) error {
 	req.Header.Set("Authorization", "Token "+c.token)
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req, "delete", documentID)
 	if err != nil {
 		return fmt.Errorf("failed to delete document: %w", err)
 	}
@@ -0,0 +1,106 @@
+package reader
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DocumentField is a document field compared by Diff
+type DocumentField string
+
+// Fields compared by Diff
+const (
+	FieldLocation DocumentField = "location"
+	FieldProgress DocumentField = "progress"
+	FieldTitle    DocumentField = "title"
+	FieldTags     DocumentField = "tags"
+	FieldNotes    DocumentField = "notes"
+)
+
+// FieldChange is a change to one field of a document
+type FieldChange struct {
+	// Field is the changed field
+	Field DocumentField `json:"field"`
+
+	// From and To are the values before and after the change. Reading
+	// progress is formatted as a percentage, e.g. "45%", and tags as their
+	// sorted names separated by commas.
+	From string `json:"from"`
+	To   string `json:"to"`
+
+	// Added and Removed are the added and removed tag names of a tags
+	// change
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+// String describes the change, e.g. "location: new -> later" or
+// "tags: +ai -todo"
+func (c FieldChange) String() string {
+	if c.Field == FieldTags {
+		var parts []string
+		for _, name := range c.Added {
+			parts = append(parts, "+"+name)
+		}
+		for _, name := range c.Removed {
+			parts = append(parts, "-"+name)
+		}
+		return fmt.Sprintf("%s: %s", c.Field, strings.Join(parts, " "))
+	}
+	return fmt.Sprintf("%s: %s -> %s", c.Field, quoteEmpty(c.From), quoteEmpty(c.To))
+}
+
+func quoteEmpty(s string) string {
+	if s == "" {
+		return `""`
+	}
+	return s
+}
+
+// Diff returns the changes between two versions of a document to its
+// location, reading progress, title, tags and notes, in that order. It
+// returns nil if none of them changed.
+func Diff(old, new Document) []FieldChange {
+	var changes []FieldChange
+	if old.Location != new.Location {
+		changes = append(changes, FieldChange{Field: FieldLocation, From: string(old.Location), To: string(new.Location)})
+	}
+	if old.ReadingProgressPercent != new.ReadingProgressPercent {
+		changes = append(changes, FieldChange{
+			Field: FieldProgress,
+			From:  formatProgress(old.ReadingProgressPercent),
+			To:    formatProgress(new.ReadingProgressPercent),
+		})
+	}
+	if old.Title != new.Title {
+		changes = append(changes, FieldChange{Field: FieldTitle, From: old.Title, To: new.Title})
+	}
+	oldTags, newTags := old.TagNames(), new.TagNames()
+	if !slices.Equal(oldTags, newTags) {
+		change := FieldChange{Field: FieldTags, From: strings.Join(oldTags, ","), To: strings.Join(newTags, ",")}
+		for _, name := range newTags {
+			if !slices.Contains(oldTags, name) {
+				change.Added = append(change.Added, name)
+			}
+		}
+		for _, name := range oldTags {
+			if !slices.Contains(newTags, name) {
+				change.Removed = append(change.Removed, name)
+			}
+		}
+		changes = append(changes, change)
+	}
+	if old.Notes != new.Notes {
+		changes = append(changes, FieldChange{Field: FieldNotes, From: old.Notes, To: new.Notes})
+	}
+	return changes
+}
+
+// formatProgress formats a reading progress as a percentage with at most
+// one decimal
+func formatProgress(p float64) string {
+	return strconv.FormatFloat(math.Round(p*10)/10, 'f', -1, 64) + "%"
+}
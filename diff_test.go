@@ -0,0 +1,61 @@
+package reader
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	old := Document{
+		ID:                     "doc1",
+		Title:                  "Old title",
+		Location:               LocationNew,
+		ReadingProgressPercent: 12.34,
+		Tags:                   map[string]interface{}{"ai": nil, "todo": nil},
+		Notes:                  "note",
+		Author:                 "Jane",
+	}
+
+	if changes := Diff(old, old); changes != nil {
+		t.Errorf("Diff() of the same document = %v, want nil", changes)
+	}
+
+	// Fields other than location, progress, title, tags and notes are ignored
+	renamed := old
+	renamed.Author = "John"
+	if changes := Diff(old, renamed); changes != nil {
+		t.Errorf("Diff() = %v, want nil", changes)
+	}
+
+	updated := old
+	updated.Title = "New title"
+	updated.Location = LocationLater
+	updated.ReadingProgressPercent = 100
+	updated.Tags = map[string]interface{}{"ai": nil, "go": nil}
+	updated.Notes = ""
+
+	want := []FieldChange{
+		{Field: FieldLocation, From: "new", To: "later"},
+		{Field: FieldProgress, From: "12.3%", To: "100%"},
+		{Field: FieldTitle, From: "Old title", To: "New title"},
+		{Field: FieldTags, From: "ai,todo", To: "ai,go", Added: []string{"go"}, Removed: []string{"todo"}},
+		{Field: FieldNotes, From: "note", To: ""},
+	}
+	changes := Diff(old, updated)
+	if !reflect.DeepEqual(changes, want) {
+		t.Fatalf("Diff() = %+v, want %+v", changes, want)
+	}
+
+	wantStrings := []string{
+		"location: new -> later",
+		"progress: 12.3% -> 100%",
+		"title: Old title -> New title",
+		"tags: +go -todo",
+		`notes: note -> ""`,
+	}
+	for i, change := range changes {
+		if got := change.String(); got != wantStrings[i] {
+			t.Errorf("String() = %q, want %q", got, wantStrings[i])
+		}
+	}
+}
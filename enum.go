@@ -0,0 +1,175 @@
+package reader
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Locations, categories and event types implement encoding.TextUnmarshaler,
+// so that decoding JSON or YAML fails for values that are not valid, and
+// flag.Value, so that they can be used as command-line flags. Documents and
+// webhook payloads keep the values Readwise sends even if they are unknown.
+
+// AllLocations returns the valid locations
+func AllLocations() []Location {
+	return []Location{LocationNew, LocationLater, LocationArchive, LocationFeed}
+}
+
+// ParseLocation parses a location name. The inbox is "new"; there is no
+// alias for it.
+func ParseLocation(s string) (Location, error) {
+	if l := Location(s); l.Valid() {
+		return l, nil
+	}
+	return "", invalidLocation(s)
+}
+
+func invalidLocation(s string) error {
+	return fmt.Errorf("invalid location: %s. Valid values: %s", s, joinValues(AllLocations()))
+}
+
+// Valid reports whether l is a valid location
+func (l Location) Valid() bool {
+	return slices.Contains(AllLocations(), l)
+}
+
+// String returns the location name
+func (l Location) String() string {
+	return string(l)
+}
+
+// Set sets the location parsed by ParseLocation, see flag.Value
+func (l *Location) Set(s string) error {
+	parsed, err := ParseLocation(s)
+	if err != nil {
+		return err
+	}
+	*l = parsed
+	return nil
+}
+
+// UnmarshalText decodes a location with ParseLocation. An empty location is
+// decoded as not set.
+func (l *Location) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*l = ""
+		return nil
+	}
+	return l.Set(string(text))
+}
+
+// AllCategories returns the valid categories
+func AllCategories() []Category {
+	return []Category{
+		CategoryArticle,
+		CategoryEmail,
+		CategoryRSS,
+		CategoryPDF,
+		CategoryEPUB,
+		CategoryTweet,
+		CategoryVideo,
+		CategoryHighlight,
+		CategoryNote,
+	}
+}
+
+// ParseCategory parses a category name
+func ParseCategory(s string) (Category, error) {
+	if c := Category(s); c.Valid() {
+		return c, nil
+	}
+	return "", invalidCategory(s)
+}
+
+func invalidCategory(s string) error {
+	return fmt.Errorf("invalid category: %s. Valid values: %s", s, joinValues(AllCategories()))
+}
+
+// Valid reports whether c is a valid category
+func (c Category) Valid() bool {
+	return slices.Contains(AllCategories(), c)
+}
+
+// String returns the category name
+func (c Category) String() string {
+	return string(c)
+}
+
+// Set sets the category parsed by ParseCategory, see flag.Value
+func (c *Category) Set(s string) error {
+	parsed, err := ParseCategory(s)
+	if err != nil {
+		return err
+	}
+	*c = parsed
+	return nil
+}
+
+// UnmarshalText decodes a category with ParseCategory. An empty category is
+// decoded as not set.
+func (c *Category) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*c = ""
+		return nil
+	}
+	return c.Set(string(text))
+}
+
+// AllWebhookEventTypes returns the valid event types: the event types of the
+// webhooks Readwise Reader sends, followed by those only reported by Watch
+func AllWebhookEventTypes() []WebhookEventType {
+	return append(slices.Clone(webhookEventTypes),
+		EventDocumentMoved,
+		EventDocumentProgressUpdated,
+		EventDocumentMetadataUpdated,
+	)
+}
+
+// ParseWebhookEventType parses an event type such as
+// "reader.document.archived"
+func ParseWebhookEventType(s string) (WebhookEventType, error) {
+	if t := WebhookEventType(s); t.Valid() {
+		return t, nil
+	}
+	return "", fmt.Errorf("invalid event type: %s. Valid values: %s", s, joinValues(AllWebhookEventTypes()))
+}
+
+// Valid reports whether t is a valid event type
+func (t WebhookEventType) Valid() bool {
+	return slices.Contains(AllWebhookEventTypes(), t)
+}
+
+// String returns the event type name
+func (t WebhookEventType) String() string {
+	return string(t)
+}
+
+// Set sets the event type parsed by ParseWebhookEventType, see flag.Value
+func (t *WebhookEventType) Set(s string) error {
+	parsed, err := ParseWebhookEventType(s)
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
+// UnmarshalText decodes an event type with ParseWebhookEventType. An empty
+// event type is decoded as not set.
+func (t *WebhookEventType) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*t = ""
+		return nil
+	}
+	return t.Set(string(text))
+}
+
+// joinValues joins valid values for error messages
+func joinValues[T ~string](values []T) string {
+	names := make([]string, len(values))
+	for i, v := range values {
+		names[i] = string(v)
+	}
+	return strings.Join(names, ", ")
+}
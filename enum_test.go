@@ -0,0 +1,153 @@
+package reader
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseLocation(t *testing.T) {
+	for _, l := range AllLocations() {
+		got, err := ParseLocation(string(l))
+		if err != nil || got != l {
+			t.Errorf("ParseLocation(%q) = %q, %v", l, got, err)
+		}
+	}
+	for _, s := range []string{"", "shortlist", "New", "inbox"} {
+		if _, err := ParseLocation(s); err == nil {
+			t.Errorf("ParseLocation(%q) error = nil, want error", s)
+		}
+	}
+	_, err := ParseLocation("trash")
+	if want := "invalid location: trash. Valid values: new, later, archive, feed"; err == nil || err.Error() != want {
+		t.Errorf("ParseLocation() error = %v, want %q", err, want)
+	}
+	_, err = ParseLocation("inbox")
+	if want := "invalid location: inbox. Valid values: new, later, archive, feed"; err == nil || err.Error() != want {
+		t.Errorf("ParseLocation(inbox) error = %v, want %q", err, want)
+	}
+}
+
+func TestParseCategory(t *testing.T) {
+	for _, c := range AllCategories() {
+		if got, err := ParseCategory(string(c)); err != nil || got != c {
+			t.Errorf("ParseCategory(%q) = %q, %v", c, got, err)
+		}
+	}
+	if got, err := ParseCategory("note"); err != nil || got != CategoryNote {
+		t.Errorf("ParseCategory(note) = %q, %v", got, err)
+	}
+	if _, err := ParseCategory("podcast"); err == nil || !strings.Contains(err.Error(), "highlight, note") {
+		t.Errorf("ParseCategory() error = %v, want the valid values", err)
+	}
+}
+
+func TestParseWebhookEventType(t *testing.T) {
+	for _, s := range []string{"reader.document.archived", "reader.any_document.created", "reader.document.moved"} {
+		if got, err := ParseWebhookEventType(s); err != nil || string(got) != s {
+			t.Errorf("ParseWebhookEventType(%q) = %q, %v", s, got, err)
+		}
+	}
+	if _, err := ParseWebhookEventType("reader.document.exploded"); err == nil {
+		t.Error("ParseWebhookEventType() error = nil, want error")
+	}
+	if n := len(AllWebhookEventTypes()); n != 12 {
+		t.Errorf("AllWebhookEventTypes() = %d event types, want 12", n)
+	}
+}
+
+func TestEnum_Flag(t *testing.T) {
+	var location Location
+	var category Category
+	var event WebhookEventType
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Var(&location, "location", "")
+	fs.Var(&category, "category", "")
+	fs.Var(&event, "event", "")
+
+	if err := fs.Parse([]string{"-location", "new", "-category", "note", "-event", "reader.document.finished"}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if location != LocationNew || category != CategoryNote || event != EventDocumentFinished {
+		t.Errorf("flags = %q, %q, %q", location, category, event)
+	}
+
+	for _, args := range [][]string{{"-location", "inbox"}, {"-location", "trash"}, {"-category", "podcast"}, {"-event", "x"}} {
+		if err := fs.Parse(args); err == nil {
+			t.Errorf("Parse(%v) error = nil, want error", args)
+		}
+	}
+}
+
+func TestEnum_UnmarshalJSON(t *testing.T) {
+	var rule Rule
+	data := `{"name": "r", "when": {"event_type": "reader.document.archived", "category": "rss"}, "then": {"location": "new"}}`
+	if err := json.Unmarshal([]byte(data), &rule); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if rule.When.EventType != EventDocumentArchived || rule.When.Category != CategoryRSS || rule.Then.Location != LocationNew {
+		t.Errorf("rule = %+v", rule)
+	}
+
+	for _, data := range []string{
+		`{"then": {"location": "inbox"}}`,
+		`{"then": {"location": "trash"}}`,
+		`{"when": {"category": "podcast"}}`,
+		`{"when": {"event_type": "reader.document.exploded"}}`,
+	} {
+		if err := json.Unmarshal([]byte(data), &rule); err == nil {
+			t.Errorf("Unmarshal(%s) error = nil, want error", data)
+		}
+	}
+
+	// Documents and webhooks keep the values Readwise sends
+	var doc Document
+	if err := json.Unmarshal([]byte(`{"id": "doc1", "location": "shortlist", "category": "podcast"}`), &doc); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if doc.Location != "shortlist" || doc.Category != "podcast" {
+		t.Errorf("document = %+v, want the unknown location and category", doc)
+	}
+	payload, err := DecodeDocumentWebhookPayload(strings.NewReader(`{"event_type": "reader.document.new", "id": "doc1", "location": "shortlist"}`))
+	if err != nil {
+		t.Fatalf("DecodeDocumentWebhookPayload() error = %v", err)
+	}
+	if payload.EventType != "reader.document.new" || payload.Location != "shortlist" {
+		t.Errorf("payload = %+v, want the unknown event type and location", payload)
+	}
+}
+
+func TestEnum_ValidateBeforeRequest(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	client := &client{baseURL: server.URL, token: "test-token", httpClient: &http.Client{}}
+	ctx := context.Background()
+
+	var clientErr *ClientError
+	_, err := client.UpdateDocument(ctx, "doc1", &UpdateDocumentRequest{Location: "inbox"})
+	if !errors.As(err, &clientErr) || !strings.Contains(err.Error(), "invalid location: inbox") {
+		t.Errorf("UpdateDocument() error = %v, want an invalid location", err)
+	}
+	_, err = client.UpdateDocument(ctx, "doc1", &UpdateDocumentRequest{Category: "podcast"})
+	if !errors.As(err, &clientErr) || !strings.Contains(err.Error(), "invalid category: podcast") {
+		t.Errorf("UpdateDocument() error = %v, want an invalid category", err)
+	}
+	_, err = client.CreateDocument(ctx, "https://example.com", &CreateDocumentRequest{Location: "trash", CheckDuplicate: true})
+	if !errors.As(err, &clientErr) || !strings.Contains(err.Error(), "invalid location: trash") {
+		t.Errorf("CreateDocument() error = %v, want an invalid location", err)
+	}
+	if requests != 0 {
+		t.Errorf("requests = %d, want none", requests)
+	}
+}
@@ -0,0 +1,463 @@
+// Package eventstore keeps a durable, append-only log of received Readwise
+// Reader webhook events so that none are lost when they cannot be handled.
+//
+// Readwise may deliver a webhook more than once. Add stores each event once,
+// identified by its document ID, event type and update time, and reports
+// duplicates. Deliver passes a stored event to a handler and records the
+// outcome. Events whose delivery failed stay in a retry queue and are
+// delivered again by Retry with exponential backoff. Replay re-delivers the
+// events received in a time window, for example after fixing a handler.
+//
+// A handler doing several things with an event, such as applying rules and
+// sending notifications, records the parts that succeeded in the Progress
+// passed in its context. They are stored with a failed delivery, so that
+// retries, even after a restart, only repeat the parts that failed. Prune
+// removes old events that are no longer retried and compacts the log.
+package eventstore
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	reader "github.com/tcnksm/go-readwise-reader"
+)
+
+const (
+	// MaxAttempts is the number of failed deliveries after which an event
+	// is no longer retried. It can still be replayed.
+	MaxAttempts = 10
+
+	minBackoff = time.Minute
+	maxBackoff = time.Hour
+)
+
+// Event is a stored webhook event and the state of its delivery
+type Event struct {
+	// Key identifies the event for deduplication
+	Key string `json:"key"`
+
+	// ReceivedAt is when the event was first received
+	ReceivedAt time.Time `json:"received_at"`
+
+	// Payload is the event, without its secret
+	Payload reader.DocumentWebhookPayload `json:"payload"`
+
+	// Delivered reports whether the last delivery succeeded
+	Delivered bool `json:"delivered"`
+
+	// Attempts is the number of failed deliveries since the last successful
+	// one
+	Attempts int `json:"attempts,omitempty"`
+
+	// LastError is the error of the last failed delivery
+	LastError string `json:"last_error,omitempty"`
+
+	// NextAttempt is when Retry delivers the event again. It is nil when the
+	// event was delivered or is no longer retried.
+	NextAttempt *time.Time `json:"next_attempt,omitempty"`
+
+	// Done are the parts of the handling that succeeded in failed
+	// deliveries, see Progress. They are cleared when a delivery succeeds.
+	Done []string `json:"done,omitempty"`
+}
+
+// record is a line of the log: a received event when Payload is set, an
+// event with its delivery state written by Prune when State is set,
+// otherwise the outcome of a delivery
+type record struct {
+	Time      time.Time                      `json:"time"`
+	Key       string                         `json:"key"`
+	Payload   *reader.DocumentWebhookPayload `json:"payload,omitempty"`
+	State     *Event                         `json:"state,omitempty"`
+	Delivered bool                           `json:"delivered,omitempty"`
+	Error     string                         `json:"error,omitempty"`
+	Done      []string                       `json:"done,omitempty"`
+}
+
+// Progress is the parts of the handling of an event that succeeded, such as
+// each rule applied to its document and each sink notified of it. Deliver
+// passes the progress of an event to the handler in the context, see
+// ProgressFromContext, and stores it when the delivery fails.
+type Progress struct {
+	mu   sync.Mutex
+	done []string
+}
+
+type progressKey struct{}
+
+// ProgressFromContext returns the progress of the event being delivered, or
+// nil if ctx does not come from Deliver. A nil Progress has no part done and
+// ignores completed parts.
+func ProgressFromContext(ctx context.Context) *Progress {
+	p, _ := ctx.Value(progressKey{}).(*Progress)
+	return p
+}
+
+// Done reports whether a part of the handling succeeded in an earlier
+// delivery, or was completed in this one
+func (p *Progress) Done(part string) bool {
+	if p == nil {
+		return false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return slices.Contains(p.done, part)
+}
+
+// Complete records that a part of the handling succeeded
+func (p *Progress) Complete(part string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !slices.Contains(p.done, part) {
+		p.done = append(p.done, part)
+	}
+}
+
+func (p *Progress) parts() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return slices.Clone(p.done)
+}
+
+// Store is an event store kept as JSON lines in a file. It is safe for
+// concurrent use, but only one process should add events to a file.
+type Store struct {
+	path string
+
+	mu     sync.Mutex
+	events map[string]*Event
+	order  []string
+}
+
+// Open returns the store kept at path. The file is created on the first
+// Add.
+func Open(path string) *Store {
+	return &Store{path: path}
+}
+
+// Key returns the deduplication key of a webhook event
+func Key(payload *reader.DocumentWebhookPayload) string {
+	updatedAt := ""
+	if payload.UpdatedAt != nil {
+		updatedAt = payload.UpdatedAt.UTC().Format(time.RFC3339Nano)
+	}
+	return payload.ID + "/" + string(payload.EventType) + "/" + updatedAt
+}
+
+// Backoff returns how long to wait before delivering an event again after
+// the given number of failed deliveries: one minute, doubled after every
+// failure up to an hour
+func Backoff(attempts int) time.Duration {
+	backoff := minBackoff
+	for i := 1; i < attempts && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxBackoff)
+}
+
+// Add stores an event and returns it. Events already stored are not stored
+// again; the stored event is returned with added set to false.
+func (s *Store) Add(payload *reader.DocumentWebhookPayload) (event Event, added bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(); err != nil {
+		return Event{}, false, err
+	}
+	key := Key(payload)
+	if e, ok := s.events[key]; ok {
+		return *e, false, nil
+	}
+
+	// The secret is not kept on disk
+	stored := *payload
+	stored.Secret = ""
+	rec := record{Time: time.Now(), Key: key, Payload: &stored}
+	if err := s.append(rec); err != nil {
+		return Event{}, false, err
+	}
+	s.apply(rec)
+	return *s.events[key], true, nil
+}
+
+// Deliver passes a stored event to fn, with the parts of its handling done
+// in earlier deliveries in the Progress of the context, and records the
+// outcome. A failed delivery is queued for Retry with the parts done so far,
+// and its error is returned.
+func (s *Store) Deliver(ctx context.Context, event Event, fn reader.WebhookHandlerFunc) error {
+	payload := event.Payload
+	progress := &Progress{done: slices.Clone(event.Done)}
+	deliverErr := fn(context.WithValue(ctx, progressKey{}, progress), &payload)
+
+	rec := record{Time: time.Now(), Key: event.Key, Delivered: deliverErr == nil}
+	if deliverErr != nil {
+		rec.Error = deliverErr.Error()
+		rec.Done = progress.parts()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.load(); err != nil {
+		return errors.Join(deliverErr, err)
+	}
+	if err := s.append(rec); err != nil {
+		return errors.Join(deliverErr, err)
+	}
+	s.apply(rec)
+	return deliverErr
+}
+
+// Events returns the events received at or after since, ordered by the
+// update time of their documents so that events delivered out of order are
+// returned in the order they happened
+func (s *Store) Events(since time.Time) ([]Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	var events []Event
+	for _, key := range s.order {
+		e := s.events[key]
+		if !e.ReceivedAt.Before(since) {
+			events = append(events, *e)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return updatedAt(events[i]).Before(updatedAt(events[j]))
+	})
+	return events, nil
+}
+
+// Pending returns the events due for another delivery at now
+func (s *Store) Pending(now time.Time) ([]Event, error) {
+	events, err := s.Events(time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	var pending []Event
+	for _, e := range events {
+		if e.NextAttempt != nil && !e.NextAttempt.After(now) {
+			pending = append(pending, e)
+		}
+	}
+	return pending, nil
+}
+
+// Retry delivers the events due for another delivery to fn and returns how
+// many were delivered. It stops when ctx is done.
+func (s *Store) Retry(ctx context.Context, fn reader.WebhookHandlerFunc) (int, error) {
+	pending, err := s.Pending(time.Now())
+	if err != nil {
+		return 0, err
+	}
+	return s.deliverAll(ctx, pending, fn, false)
+}
+
+// Replay delivers the events received at or after since to fn again,
+// whether or not they were delivered before, and returns how many were
+// delivered. Unlike Retry, it stops at the first failed delivery so that
+// later events are not handled before earlier ones.
+func (s *Store) Replay(ctx context.Context, since time.Time, fn reader.WebhookHandlerFunc) (int, error) {
+	events, err := s.Events(since)
+	if err != nil {
+		return 0, err
+	}
+	return s.deliverAll(ctx, events, fn, true)
+}
+
+func (s *Store) deliverAll(ctx context.Context, events []Event, fn reader.WebhookHandlerFunc, stopOnError bool) (int, error) {
+	delivered := 0
+	var errs []error
+	for _, e := range events {
+		if err := ctx.Err(); err != nil {
+			return delivered, err
+		}
+		if err := s.Deliver(ctx, e, fn); err != nil {
+			err = fmt.Errorf("failed to deliver event %s: %w", e.Key, err)
+			if stopOnError {
+				return delivered, err
+			}
+			errs = append(errs, err)
+			continue
+		}
+		delivered++
+	}
+	return delivered, errors.Join(errs...)
+}
+
+// Prune removes the events received before the given time that are not
+// queued for a retry, that is delivered events and events no longer retried,
+// and rewrites the log with the others. It returns the number of removed
+// events. Removed events are no longer deduplicated or replayed.
+func (s *Store) Prune(before time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(); err != nil {
+		return 0, err
+	}
+	var order []string
+	for _, key := range s.order {
+		e := s.events[key]
+		if e.ReceivedAt.Before(before) && e.NextAttempt == nil {
+			continue
+		}
+		order = append(order, key)
+	}
+	removed := len(s.order) - len(order)
+	if removed == 0 {
+		return 0, nil
+	}
+
+	tmp := s.path + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return 0, fmt.Errorf("failed to compact event store: %w", err)
+	}
+	defer os.Remove(tmp)
+	defer file.Close()
+
+	w := bufio.NewWriter(file)
+	encoder := json.NewEncoder(w)
+	for _, key := range order {
+		e := s.events[key]
+		if err := encoder.Encode(record{Time: e.ReceivedAt, Key: key, State: e}); err != nil {
+			return 0, fmt.Errorf("failed to compact event store: %w", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return 0, fmt.Errorf("failed to compact event store: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return 0, fmt.Errorf("failed to compact event store: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return 0, fmt.Errorf("failed to compact event store: %w", err)
+	}
+
+	events := make(map[string]*Event, len(order))
+	for _, key := range order {
+		events[key] = s.events[key]
+	}
+	s.events, s.order = events, order
+	return removed, nil
+}
+
+// load reads the log on first use
+func (s *Store) load() error {
+	if s.events != nil {
+		return nil
+	}
+	s.events = make(map[string]*Event)
+
+	file, err := os.Open(s.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		s.events = nil
+		return fmt.Errorf("failed to open event store: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	// Payloads may hold the document content, so lines can be long
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		var rec record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			s.events, s.order = nil, nil
+			return fmt.Errorf("failed to read event store line %d: %w", line, err)
+		}
+		s.apply(rec)
+	}
+	if err := scanner.Err(); err != nil {
+		s.events, s.order = nil, nil
+		return fmt.Errorf("failed to read event store: %w", err)
+	}
+	return nil
+}
+
+// apply updates the events with a record
+func (s *Store) apply(rec record) {
+	if rec.State != nil {
+		if _, ok := s.events[rec.Key]; !ok {
+			s.order = append(s.order, rec.Key)
+		}
+		e := *rec.State
+		s.events[rec.Key] = &e
+		return
+	}
+	if rec.Payload != nil {
+		if _, ok := s.events[rec.Key]; !ok {
+			s.events[rec.Key] = &Event{Key: rec.Key, ReceivedAt: rec.Time, Payload: *rec.Payload}
+			s.order = append(s.order, rec.Key)
+		}
+		return
+	}
+
+	e, ok := s.events[rec.Key]
+	if !ok {
+		return
+	}
+	if rec.Delivered {
+		e.Delivered = true
+		e.Attempts = 0
+		e.LastError = ""
+		e.NextAttempt = nil
+		e.Done = nil
+		return
+	}
+	e.Delivered = false
+	e.Attempts++
+	e.LastError = rec.Error
+	e.Done = rec.Done
+	e.NextAttempt = nil
+	if e.Attempts < MaxAttempts {
+		next := rec.Time.Add(Backoff(e.Attempts))
+		e.NextAttempt = &next
+	}
+}
+
+func (s *Store) append(rec record) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create event store directory: %w", err)
+	}
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open event store: %w", err)
+	}
+	defer file.Close()
+
+	if err := json.NewEncoder(file).Encode(rec); err != nil {
+		return fmt.Errorf("failed to write event store: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write event store: %w", err)
+	}
+	return nil
+}
+
+// updatedAt returns the update time of the document of an event, or when
+// the event was received if the payload has none
+func updatedAt(e Event) time.Time {
+	if e.Payload.UpdatedAt != nil {
+		return *e.Payload.UpdatedAt
+	}
+	return e.ReceivedAt
+}
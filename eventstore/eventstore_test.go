@@ -0,0 +1,295 @@
+package eventstore
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	reader "github.com/tcnksm/go-readwise-reader"
+)
+
+func payload(id string, eventType reader.WebhookEventType, updatedAt time.Time) *reader.DocumentWebhookPayload {
+	return &reader.DocumentWebhookPayload{
+		EventType: eventType,
+		Secret:    "secret",
+		ID:        id,
+		UpdatedAt: &updatedAt,
+	}
+}
+
+func TestStore_Add(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	s := Open(path)
+	updated := time.Date(2025, 7, 15, 0, 0, 0, 0, time.UTC)
+
+	event, added, err := s.Add(payload("doc1", reader.EventDocumentArchived, updated))
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if !added || event.Key != "doc1/reader.document.archived/2025-07-15T00:00:00Z" {
+		t.Errorf("Add() = %+v, %v", event, added)
+	}
+
+	// Redeliveries of the same event are duplicates, other events are not
+	if _, added, _ := s.Add(payload("doc1", reader.EventDocumentArchived, updated)); added {
+		t.Error("Add() added a duplicate event")
+	}
+	if _, added, _ := s.Add(payload("doc1", reader.EventDocumentArchived, updated.Add(time.Second))); !added {
+		t.Error("Add() did not add an event with a later update time")
+	}
+	if _, added, _ := s.Add(payload("doc1", reader.EventDocumentTagsUpdated, updated)); !added {
+		t.Error("Add() did not add an event of another type")
+	}
+
+	// Duplicates are detected across reopening the store
+	s = Open(path)
+	if _, added, _ := s.Add(payload("doc1", reader.EventDocumentArchived, updated)); added {
+		t.Error("Add() added a duplicate event after reopening")
+	}
+	events, err := s.Events(time.Time{})
+	if err != nil {
+		t.Fatalf("Events() error = %v", err)
+	}
+	if len(events) != 3 {
+		t.Errorf("len(Events()) = %d, want 3", len(events))
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), `"secret":"secret"`) {
+		t.Errorf("store contains the webhook secret: %s", data)
+	}
+}
+
+func TestStore_Events_Order(t *testing.T) {
+	s := Open(filepath.Join(t.TempDir(), "events.jsonl"))
+	base := time.Date(2025, 7, 15, 0, 0, 0, 0, time.UTC)
+
+	// Delivered out of order
+	s.Add(payload("doc1", reader.EventDocumentArchived, base.Add(time.Hour)))
+	s.Add(payload("doc1", reader.EventDocumentMovedToLater, base))
+
+	events, err := s.Events(time.Time{})
+	if err != nil {
+		t.Fatalf("Events() error = %v", err)
+	}
+	if len(events) != 2 || events[0].Payload.EventType != reader.EventDocumentMovedToLater {
+		t.Errorf("Events() = %+v, want moved_to_later first", events)
+	}
+
+	if events, _ := s.Events(time.Now().Add(time.Hour)); len(events) != 0 {
+		t.Errorf("Events(future) = %+v, want none", events)
+	}
+}
+
+func TestStore_DeliverRetry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	s := Open(path)
+	event, _, err := s.Add(payload("doc1", reader.EventDocumentArchived, time.Now()))
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	sinkErr := errors.New("sink is down")
+	failing := func(ctx context.Context, p *reader.DocumentWebhookPayload) error { return sinkErr }
+	if err := s.Deliver(context.Background(), event, failing); !errors.Is(err, sinkErr) {
+		t.Fatalf("Deliver() error = %v, want %v", err, sinkErr)
+	}
+
+	// The failed event is queued with backoff
+	s = Open(path)
+	events, _ := s.Events(time.Time{})
+	if e := events[0]; e.Delivered || e.Attempts != 1 || e.LastError != "sink is down" || e.NextAttempt == nil {
+		t.Fatalf("event after failed delivery = %+v", e)
+	}
+	if pending, _ := s.Pending(time.Now()); len(pending) != 0 {
+		t.Errorf("Pending(now) = %+v, want none before the backoff", pending)
+	}
+	if pending, _ := s.Pending(time.Now().Add(Backoff(1))); len(pending) != 1 {
+		t.Errorf("Pending(after backoff) = %+v, want the failed event", pending)
+	}
+
+	var got []string
+	ok := func(ctx context.Context, p *reader.DocumentWebhookPayload) error {
+		got = append(got, p.ID)
+		return nil
+	}
+	if err := s.Deliver(context.Background(), events[0], ok); err != nil {
+		t.Fatalf("Deliver() error = %v", err)
+	}
+	events, _ = s.Events(time.Time{})
+	if e := events[0]; !e.Delivered || e.Attempts != 0 || e.NextAttempt != nil {
+		t.Errorf("event after delivery = %+v", e)
+	}
+	if n, err := s.Retry(context.Background(), ok); n != 0 || err != nil {
+		t.Errorf("Retry() = %d, %v, want nothing to retry", n, err)
+	}
+	if len(got) != 1 || got[0] != "doc1" {
+		t.Errorf("delivered = %v, want [doc1]", got)
+	}
+}
+
+func TestStore_Deliver_MaxAttempts(t *testing.T) {
+	s := Open(filepath.Join(t.TempDir(), "events.jsonl"))
+	event, _, _ := s.Add(payload("doc1", reader.EventDocumentArchived, time.Now()))
+
+	failing := func(ctx context.Context, p *reader.DocumentWebhookPayload) error { return errors.New("down") }
+	for range MaxAttempts {
+		s.Deliver(context.Background(), event, failing)
+	}
+	events, _ := s.Events(time.Time{})
+	if e := events[0]; e.Attempts != MaxAttempts || e.NextAttempt != nil {
+		t.Errorf("event after %d failures = %+v, want no next attempt", MaxAttempts, e)
+	}
+}
+
+func TestStore_Replay(t *testing.T) {
+	s := Open(filepath.Join(t.TempDir(), "events.jsonl"))
+	base := time.Date(2025, 7, 15, 0, 0, 0, 0, time.UTC)
+	s.Add(payload("doc2", reader.EventDocumentArchived, base.Add(time.Hour)))
+	s.Add(payload("doc1", reader.EventDocumentArchived, base))
+
+	var got []string
+	fn := func(ctx context.Context, p *reader.DocumentWebhookPayload) error {
+		got = append(got, p.ID)
+		if p.Secret != "" {
+			t.Errorf("replayed payload has secret %q", p.Secret)
+		}
+		return nil
+	}
+	n, err := s.Replay(context.Background(), time.Now().Add(-time.Hour), fn)
+	if err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+	if n != 2 || strings.Join(got, ",") != "doc1,doc2" {
+		t.Errorf("Replay() = %d, delivered %v, want doc1,doc2", n, got)
+	}
+
+	// Replay stops at the first failure
+	got = nil
+	failing := func(ctx context.Context, p *reader.DocumentWebhookPayload) error {
+		got = append(got, p.ID)
+		return errors.New("down")
+	}
+	if n, err := s.Replay(context.Background(), time.Time{}, failing); n != 0 || err == nil || len(got) != 1 {
+		t.Errorf("Replay() = %d, %v, delivered %v, want a failure after the first event", n, err, got)
+	}
+}
+
+func TestStore_Progress(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	event, _, err := Open(path).Add(payload("doc1", reader.EventDocumentArchived, time.Now()))
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	var handled []string
+	fn := func(fail bool) reader.WebhookHandlerFunc {
+		return func(ctx context.Context, p *reader.DocumentWebhookPayload) error {
+			progress := ProgressFromContext(ctx)
+			for _, part := range []string{"rule", "notify"} {
+				if progress.Done(part) {
+					continue
+				}
+				if part == "notify" && fail {
+					return errors.New("sink is down")
+				}
+				handled = append(handled, part)
+				progress.Complete(part)
+			}
+			return nil
+		}
+	}
+	if err := Open(path).Deliver(context.Background(), event, fn(true)); err == nil {
+		t.Fatal("Deliver() error = nil, want the sink error")
+	}
+
+	// After a restart, only the failed part is handled again
+	s := Open(path)
+	events, _ := s.Events(time.Time{})
+	if done := events[0].Done; len(done) != 1 || done[0] != "rule" {
+		t.Fatalf("Done = %v, want [rule]", done)
+	}
+	if err := s.Deliver(context.Background(), events[0], fn(false)); err != nil {
+		t.Fatalf("Deliver() error = %v", err)
+	}
+	if strings.Join(handled, ",") != "rule,notify" {
+		t.Errorf("handled = %v, want rule then notify once each", handled)
+	}
+	events, _ = Open(path).Events(time.Time{})
+	if e := events[0]; !e.Delivered || e.Done != nil {
+		t.Errorf("event after delivery = %+v, want delivered with no parts", e)
+	}
+
+	// Handlers work without a store
+	if ProgressFromContext(context.Background()).Done("rule") {
+		t.Error("Done() = true without a progress")
+	}
+}
+
+func TestStore_Prune(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	s := Open(path)
+	ok := func(ctx context.Context, p *reader.DocumentWebhookPayload) error { return nil }
+	failing := func(ctx context.Context, p *reader.DocumentWebhookPayload) error { return errors.New("down") }
+	now := time.Now()
+
+	delivered, _, _ := s.Add(payload("delivered", reader.EventDocumentArchived, now))
+	s.Deliver(context.Background(), delivered, ok)
+	pending, _, _ := s.Add(payload("pending", reader.EventDocumentArchived, now))
+	s.Deliver(context.Background(), pending, failing)
+
+	// Nothing was received before the cutoff
+	if n, err := s.Prune(now.Add(-time.Hour)); n != 0 || err != nil {
+		t.Fatalf("Prune(hour ago) = %d, %v, want nothing removed", n, err)
+	}
+
+	n, err := s.Prune(time.Now().Add(time.Minute))
+	if n != 1 || err != nil {
+		t.Fatalf("Prune() = %d, %v, want the delivered event removed", n, err)
+	}
+
+	// The compacted log keeps the pending event and its delivery state
+	s = Open(path)
+	events, err := s.Events(time.Time{})
+	if err != nil {
+		t.Fatalf("Events() error = %v", err)
+	}
+	if len(events) != 1 || events[0].Payload.ID != "pending" || events[0].Attempts != 1 || events[0].NextAttempt == nil {
+		t.Fatalf("events after Prune() = %+v, want the pending event", events)
+	}
+	if err := s.Deliver(context.Background(), events[0], ok); err != nil {
+		t.Fatalf("Deliver() error = %v", err)
+	}
+	if events, _ := Open(path).Events(time.Time{}); len(events) != 1 || !events[0].Delivered {
+		t.Errorf("events after delivering the pending event = %+v", events)
+	}
+	if _, err := os.Stat(path + ".tmp"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("temporary file left behind: %v", err)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{4, 8 * time.Minute},
+		{7, time.Hour},
+		{100, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := Backoff(tt.attempts); got != tt.want {
+			t.Errorf("Backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,389 @@
+// Package feed publishes Readwise Reader documents as RSS 2.0, Atom and
+// JSON Feed feeds, for example to share an archive of finished articles.
+//
+// New builds a Feed from documents, which can then be encoded in any of the
+// formats. NewHandler serves the documents matching a reader.Query as a feed
+// over HTTP, with ETag and Last-Modified support.
+package feed
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"time"
+
+	reader "github.com/tcnksm/go-readwise-reader"
+)
+
+// Format is a feed format
+type Format string
+
+// Format constants
+const (
+	// FormatRSS is RSS 2.0
+	FormatRSS Format = "rss"
+
+	// FormatAtom is Atom
+	FormatAtom Format = "atom"
+
+	// FormatJSON is JSON Feed 1.1
+	FormatJSON Format = "json"
+)
+
+// ContentType returns the media type of the format
+func (f Format) ContentType() string {
+	switch f {
+	case FormatAtom:
+		return "application/atom+xml; charset=utf-8"
+	case FormatJSON:
+		return "application/feed+json; charset=utf-8"
+	default:
+		return "application/rss+xml; charset=utf-8"
+	}
+}
+
+// Feed is a list of items to publish
+type Feed struct {
+	// Title is the title of the feed
+	Title string
+
+	// Link is the URL the feed is published at
+	Link string
+
+	// Updated is when an item was last updated
+	Updated time.Time
+
+	// Items are the items, most recently updated first
+	Items []Item
+}
+
+// Item is a document in a feed
+type Item struct {
+	// ID is the Reader URL of the document, which stays stable
+	ID string
+
+	// Title is the document title
+	Title string
+
+	// URL is the original URL of the document
+	URL string
+
+	// Author is the document author
+	Author string
+
+	// Summary is the document summary
+	Summary string
+
+	// Notes is the note of the document
+	Notes string
+
+	// Content is the HTML content, if the documents were listed with it
+	Content string
+
+	// Tags are the tag names of the document
+	Tags []string
+
+	// Published is when the document was saved
+	Published time.Time
+
+	// Updated is when the document was last updated
+	Updated time.Time
+}
+
+// New returns a feed of documents, most recently updated first
+func New(title, link string, docs []reader.Document) *Feed {
+	f := &Feed{Title: title, Link: link}
+	for _, doc := range docs {
+		item := Item{
+			ID:        doc.URL,
+			Title:     doc.Title,
+			URL:       doc.SourceURL,
+			Author:    doc.Author,
+			Summary:   doc.Summary,
+			Notes:     doc.Notes,
+			Content:   doc.HTMLContent,
+			Tags:      doc.TagNames(),
+			Published: timeOrZero(doc.SavedAt),
+			Updated:   timeOrZero(doc.UpdatedAt),
+		}
+		if item.ID == "" {
+			item.ID = "urn:readwise-reader:" + doc.ID
+		}
+		if item.URL == "" {
+			item.URL = doc.URL
+		}
+		if item.Title == "" {
+			item.Title = item.URL
+		}
+		if item.Published.IsZero() {
+			item.Published = timeOrZero(doc.CreatedAt)
+		}
+		if item.Updated.After(f.Updated) {
+			f.Updated = item.Updated
+		}
+		f.Items = append(f.Items, item)
+	}
+	sort.SliceStable(f.Items, func(i, j int) bool {
+		return f.Items[i].Updated.After(f.Items[j].Updated)
+	})
+	return f
+}
+
+// Encode encodes the feed in a format
+func (f *Feed) Encode(format Format) ([]byte, error) {
+	switch format {
+	case FormatRSS:
+		return f.RSS()
+	case FormatAtom:
+		return f.Atom()
+	case FormatJSON:
+		return f.JSON()
+	default:
+		return nil, fmt.Errorf("invalid format: %s. Valid values: rss, atom, json", format)
+	}
+}
+
+// html returns the notes and the content of an item as HTML
+func (item *Item) html() string {
+	var b strings.Builder
+	if item.Notes != "" {
+		b.WriteString("<blockquote>")
+		for _, p := range strings.Split(strings.TrimSpace(item.Notes), "\n\n") {
+			b.WriteString("<p>" + strings.ReplaceAll(html.EscapeString(p), "\n", "<br>") + "</p>")
+		}
+		b.WriteString("</blockquote>")
+	}
+	b.WriteString(item.Content)
+	return b.String()
+}
+
+type rss struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        rssGUID  `xml:"guid"`
+	Author      string   `xml:"author,omitempty"`
+	Description string   `xml:"description,omitempty"`
+	Categories  []string `xml:"category"`
+	PubDate     string   `xml:"pubDate,omitempty"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// RSS encodes the feed as RSS 2.0. The description of an item holds its
+// summary, notes and content as HTML.
+func (f *Feed) RSS() ([]byte, error) {
+	doc := rss{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:         f.Title,
+			Link:          f.Link,
+			Description:   f.Title,
+			LastBuildDate: rssTime(f.Updated),
+		},
+	}
+	for _, item := range f.Items {
+		description := item.html()
+		if item.Summary != "" {
+			description = "<p>" + html.EscapeString(item.Summary) + "</p>" + description
+		}
+		doc.Channel.Items = append(doc.Channel.Items, rssItem{
+			Title:       item.Title,
+			Link:        item.URL,
+			GUID:        rssGUID{Value: item.ID},
+			Author:      item.Author,
+			Description: description,
+			Categories:  item.Tags,
+			PubDate:     rssTime(item.Published),
+		})
+	}
+	return encodeXML(doc)
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published,omitempty"`
+	Links      []atomLink     `xml:"link"`
+	Author     *atomAuthor    `xml:"author"`
+	Summary    string         `xml:"summary,omitempty"`
+	Content    *atomContent   `xml:"content"`
+	Categories []atomCategory `xml:"category"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomContent struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// Atom encodes the feed as Atom. The content of an entry holds its notes and
+// content as HTML.
+func (f *Feed) Atom() ([]byte, error) {
+	doc := atomFeed{
+		ID:      f.Link,
+		Title:   f.Title,
+		Updated: atomTime(f.Updated),
+		Links:   []atomLink{{Rel: "self", Href: f.Link}},
+	}
+	for _, item := range f.Items {
+		entry := atomEntry{
+			ID:        item.ID,
+			Title:     item.Title,
+			Updated:   atomTime(item.Updated),
+			Published: atomTime(item.Published),
+			Links:     []atomLink{{Rel: "alternate", Href: item.URL}},
+			Summary:   item.Summary,
+		}
+		if item.Author != "" {
+			entry.Author = &atomAuthor{Name: item.Author}
+		}
+		if content := item.html(); content != "" {
+			entry.Content = &atomContent{Type: "html", Value: content}
+		}
+		for _, tag := range item.Tags {
+			entry.Categories = append(entry.Categories, atomCategory{Term: tag})
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+	return encodeXML(doc)
+}
+
+type jsonFeed struct {
+	Version string         `json:"version"`
+	Title   string         `json:"title"`
+	FeedURL string         `json:"feed_url,omitempty"`
+	Items   []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            string           `json:"id"`
+	URL           string           `json:"url,omitempty"`
+	Title         string           `json:"title,omitempty"`
+	ContentHTML   string           `json:"content_html,omitempty"`
+	ContentText   string           `json:"content_text,omitempty"`
+	Summary       string           `json:"summary,omitempty"`
+	DatePublished string           `json:"date_published,omitempty"`
+	DateModified  string           `json:"date_modified,omitempty"`
+	Authors       []jsonFeedAuthor `json:"authors,omitempty"`
+	Tags          []string         `json:"tags,omitempty"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+}
+
+// JSON encodes the feed as JSON Feed 1.1. The content of an item holds its
+// notes and content as HTML, or its summary or title as text if it has
+// neither.
+func (f *Feed) JSON() ([]byte, error) {
+	doc := jsonFeed{
+		Version: "https://jsonfeed.org/version/1.1",
+		Title:   f.Title,
+		FeedURL: f.Link,
+		Items:   []jsonFeedItem{},
+	}
+	for _, item := range f.Items {
+		entry := jsonFeedItem{
+			ID:            item.ID,
+			URL:           item.URL,
+			Title:         item.Title,
+			ContentHTML:   item.html(),
+			Summary:       item.Summary,
+			DatePublished: atomTime(item.Published),
+			DateModified:  atomTime(item.Updated),
+			Tags:          item.Tags,
+		}
+		// Items must have content
+		if entry.ContentHTML == "" {
+			entry.ContentText = item.Summary
+			if entry.ContentText == "" {
+				entry.ContentText = item.Title
+			}
+		}
+		if item.Author != "" {
+			entry.Authors = []jsonFeedAuthor{{Name: item.Author}}
+		}
+		doc.Items = append(doc.Items, entry)
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode feed: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+func encodeXML(v interface{}) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	enc := xml.NewEncoder(&b)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return nil, fmt.Errorf("failed to encode feed: %w", err)
+	}
+	b.WriteString("\n")
+	return b.Bytes(), nil
+}
+
+func rssTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC1123Z)
+}
+
+func atomTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func timeOrZero(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
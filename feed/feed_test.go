@@ -0,0 +1,138 @@
+package feed
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	reader "github.com/tcnksm/go-readwise-reader"
+)
+
+func testDocuments() []reader.Document {
+	saved := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	older := time.Date(2025, 7, 10, 0, 0, 0, 0, time.UTC)
+	newer := time.Date(2025, 7, 12, 0, 0, 0, 0, time.UTC)
+	return []reader.Document{
+		{
+			ID:        "doc1",
+			URL:       "https://read.readwise.io/read/doc1",
+			SourceURL: "https://example.com/older",
+			Title:     "Older",
+			SavedAt:   &saved,
+			UpdatedAt: &older,
+		},
+		{
+			ID:          "doc2",
+			URL:         "https://read.readwise.io/read/doc2",
+			SourceURL:   "https://example.com/newer",
+			Title:       "Newer",
+			Author:      "Jane Doe",
+			Summary:     "A summary",
+			Notes:       "Worth <sharing>",
+			HTMLContent: "<p>Content</p>",
+			Tags:        map[string]interface{}{"share": map[string]interface{}{"name": "share"}},
+			SavedAt:     &saved,
+			UpdatedAt:   &newer,
+		},
+	}
+}
+
+func TestNew(t *testing.T) {
+	f := New("Shared", "https://example.com/feed", testDocuments())
+	if len(f.Items) != 2 || f.Items[0].Title != "Newer" || f.Items[1].Title != "Older" {
+		t.Fatalf("New() items = %+v, want newest first", f.Items)
+	}
+	if want := time.Date(2025, 7, 12, 0, 0, 0, 0, time.UTC); !f.Updated.Equal(want) {
+		t.Errorf("Updated = %v, want %v", f.Updated, want)
+	}
+	item := f.Items[0]
+	if item.ID != "https://read.readwise.io/read/doc2" || item.URL != "https://example.com/newer" {
+		t.Errorf("item = %+v", item)
+	}
+	if got, want := item.html(), "<blockquote><p>Worth &lt;sharing&gt;</p></blockquote><p>Content</p>"; got != want {
+		t.Errorf("html() = %q, want %q", got, want)
+	}
+}
+
+func TestFeed_RSS(t *testing.T) {
+	data, err := New("Shared", "https://example.com/feed", testDocuments()).RSS()
+	if err != nil {
+		t.Fatalf("RSS() error = %v", err)
+	}
+	var got rss
+	if err := xml.Unmarshal(data, &got); err != nil {
+		t.Fatalf("invalid RSS: %v\n%s", err, data)
+	}
+	if got.Version != "2.0" || got.Channel.Title != "Shared" || len(got.Channel.Items) != 2 {
+		t.Fatalf("RSS() = %s", data)
+	}
+	item := got.Channel.Items[0]
+	if item.Link != "https://example.com/newer" || item.GUID.Value != "https://read.readwise.io/read/doc2" || item.GUID.IsPermaLink {
+		t.Errorf("item = %+v", item)
+	}
+	if item.PubDate != "Tue, 01 Jul 2025 00:00:00 +0000" || item.Author != "Jane Doe" || len(item.Categories) != 1 {
+		t.Errorf("item = %+v", item)
+	}
+	if want := "<p>A summary</p><blockquote><p>Worth &lt;sharing&gt;</p></blockquote><p>Content</p>"; item.Description != want {
+		t.Errorf("description = %q, want %q", item.Description, want)
+	}
+}
+
+func TestFeed_Atom(t *testing.T) {
+	data, err := New("Shared", "https://example.com/feed.atom", testDocuments()).Atom()
+	if err != nil {
+		t.Fatalf("Atom() error = %v", err)
+	}
+	if !strings.Contains(string(data), `<feed xmlns="http://www.w3.org/2005/Atom">`) {
+		t.Errorf("Atom() has no Atom namespace:\n%s", data)
+	}
+	var got atomFeed
+	if err := xml.Unmarshal(data, &got); err != nil {
+		t.Fatalf("invalid Atom: %v\n%s", err, data)
+	}
+	if got.ID != "https://example.com/feed.atom" || got.Updated != "2025-07-12T00:00:00Z" || len(got.Entries) != 2 {
+		t.Fatalf("Atom() = %s", data)
+	}
+	entry := got.Entries[0]
+	if entry.Author == nil || entry.Author.Name != "Jane Doe" || entry.Summary != "A summary" || entry.Content == nil || entry.Content.Type != "html" {
+		t.Errorf("entry = %+v", entry)
+	}
+	if older := got.Entries[1]; older.Author != nil || older.Content != nil {
+		t.Errorf("entry without author and content = %+v", older)
+	}
+}
+
+func TestFeed_JSON(t *testing.T) {
+	data, err := New("Shared", "https://example.com/feed.json", testDocuments()).JSON()
+	if err != nil {
+		t.Fatalf("JSON() error = %v", err)
+	}
+	var got jsonFeed
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("invalid JSON Feed: %v\n%s", err, data)
+	}
+	if got.Version != "https://jsonfeed.org/version/1.1" || len(got.Items) != 2 {
+		t.Fatalf("JSON() = %s", data)
+	}
+	if item := got.Items[0]; item.DateModified != "2025-07-12T00:00:00Z" || len(item.Authors) != 1 || item.Tags[0] != "share" || item.ContentHTML == "" {
+		t.Errorf("item = %+v", item)
+	}
+	// Items without notes or content still have content
+	if item := got.Items[1]; item.ContentHTML != "" || item.ContentText != "Older" {
+		t.Errorf("item = %+v", item)
+	}
+}
+
+func TestFeed_Encode(t *testing.T) {
+	f := New("Empty", "https://example.com/feed", nil)
+	for _, format := range []Format{FormatRSS, FormatAtom, FormatJSON} {
+		if _, err := f.Encode(format); err != nil {
+			t.Errorf("Encode(%s) error = %v", format, err)
+		}
+	}
+	if _, err := f.Encode("csv"); err == nil {
+		t.Error("Encode(csv) error = nil, want invalid format")
+	}
+}
@@ -0,0 +1,192 @@
+package feed
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	reader "github.com/tcnksm/go-readwise-reader"
+)
+
+// HandlerConfig configures the documents a handler publishes
+type HandlerConfig struct {
+	// Title is the title of the feed
+	Title string
+
+	// Query selects the documents, e.g. "location=archive and tag=share".
+	// It is parsed again on every refresh, so relative times such as
+	// "saved_at > -30d" move with time. All documents are published if it
+	// is empty, which requires a Token.
+	Query string
+
+	// Token, if set, must be given in the token query parameter of every
+	// request, e.g. /feed.atom?token=...
+	Token string
+
+	// Limit is the maximum number of items, the most recently updated.
+	// 0 means no limit.
+	Limit int
+
+	// WithContent includes the HTML content of the documents
+	WithContent bool
+
+	// TTL is how long the documents are served before they are listed again
+	TTL time.Duration
+}
+
+// handler serves a feed of the documents matching a query
+type handler struct {
+	client reader.Client
+	config HandlerConfig
+
+	mu       sync.Mutex
+	docs     []reader.Document
+	listedAt time.Time
+}
+
+// NewHandler returns an http.Handler that serves the documents matching the
+// query in cfg as a feed. The format is chosen by the extension of the
+// request path (.rss or .xml, .atom, .json) or the format query parameter,
+// and defaults to RSS. Responses carry an ETag and a Last-Modified header,
+// and conditional requests are answered with 304 Not Modified. Either a
+// query or a token is required, so that the whole library is not published
+// by mistake.
+func NewHandler(c reader.Client, cfg HandlerConfig) (http.Handler, error) {
+	if cfg.Query == "" && cfg.Token == "" {
+		return nil, errors.New("a query or a token is required to publish the whole library")
+	}
+	h := &handler{client: c, config: cfg}
+	if _, _, err := h.query(time.Now()); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// query returns the list options and the filter of the documents to publish
+func (h *handler) query(now time.Time) (*reader.ListDocumentsOptions, func(reader.Document) bool, error) {
+	if h.config.Query == "" {
+		return &reader.ListDocumentsOptions{}, nil, nil
+	}
+	query, err := reader.ParseQuery(h.config.Query, now)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid query: %w", err)
+	}
+	return query.ListOptions(), query.Match, nil
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.config.Token != "" && subtle.ConstantTimeCompare([]byte(r.URL.Query().Get("token")), []byte(h.config.Token)) != 1 {
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+		return
+	}
+	format, ok := requestFormat(r)
+	if !ok {
+		http.Error(w, "unknown feed format", http.StatusNotFound)
+		return
+	}
+
+	docs, err := h.documents(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	f := New(h.config.Title, requestURL(r), docs)
+	data, err := f.Encode(format)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	sum := sha256.Sum256(data)
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:16])+`"`)
+	http.ServeContent(w, r, "", f.Updated, bytes.NewReader(data))
+}
+
+// documents returns the documents to publish, listing them again once the
+// TTL has passed
+func (h *handler) documents(ctx context.Context) ([]reader.Document, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.docs != nil && time.Since(h.listedAt) < h.config.TTL {
+		return h.docs, nil
+	}
+
+	now := time.Now()
+	opts, match, err := h.query(now)
+	if err != nil {
+		return nil, err
+	}
+	opts.WithHTMLContent = h.config.WithContent
+	docs, err := reader.FindDocuments(ctx, h.client, opts, match, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list documents: %w", err)
+	}
+
+	h.docs, h.listedAt = latest(docs, h.config.Limit), now
+	return h.docs, nil
+}
+
+// latest returns the limit most recently updated documents
+func latest(docs []reader.Document, limit int) []reader.Document {
+	docs = slices.Clone(docs)
+	sort.SliceStable(docs, func(i, j int) bool {
+		return timeOrZero(docs[i].UpdatedAt).After(timeOrZero(docs[j].UpdatedAt))
+	})
+	if limit > 0 && len(docs) > limit {
+		docs = docs[:limit]
+	}
+	if docs == nil {
+		docs = []reader.Document{}
+	}
+	return docs
+}
+
+// requestFormat returns the format requested by the path extension or the
+// format query parameter
+func requestFormat(r *http.Request) (Format, bool) {
+	if format := r.URL.Query().Get("format"); format != "" {
+		switch Format(format) {
+		case FormatRSS, FormatAtom, FormatJSON:
+			return Format(format), true
+		}
+		return "", false
+	}
+	switch path.Ext(r.URL.Path) {
+	case "", ".rss", ".xml":
+		return FormatRSS, true
+	case ".atom":
+		return FormatAtom, true
+	case ".json":
+		return FormatJSON, true
+	}
+	return "", false
+}
+
+// requestURL returns the URL the feed was requested at
+func requestURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto == "http" || proto == "https" {
+		scheme = proto
+	}
+	return scheme + "://" + r.Host + r.URL.RequestURI()
+}
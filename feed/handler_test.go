@@ -0,0 +1,160 @@
+package feed
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	reader "github.com/tcnksm/go-readwise-reader"
+)
+
+// fakeClient is a reader.Client that lists fixed documents
+type fakeClient struct {
+	reader.Client
+	docs  []reader.Document
+	opts  []reader.ListDocumentsOptions
+	calls int
+}
+
+func (c *fakeClient) ListDocuments(ctx context.Context, opts *reader.ListDocumentsOptions) (*reader.ListDocumentsResponse, error) {
+	c.calls++
+	c.opts = append(c.opts, *opts)
+	return &reader.ListDocumentsResponse{Count: len(c.docs), Results: c.docs}, nil
+}
+
+func TestHandler(t *testing.T) {
+	docs := testDocuments()
+	docs[0].Location = reader.LocationArchive
+	docs[1].Location = reader.LocationArchive
+	c := &fakeClient{docs: append(docs, reader.Document{ID: "doc3", Location: reader.LocationNew})}
+
+	h, err := NewHandler(c, HandlerConfig{
+		Title:       "Shared",
+		Query:       "location=archive",
+		Limit:       1,
+		WithContent: true,
+		TTL:         time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("NewHandler() error = %v", err)
+	}
+
+	tests := []struct {
+		target      string
+		contentType string
+		contains    string
+	}{
+		{"/feed", "application/rss+xml; charset=utf-8", "<rss version=\"2.0\">"},
+		{"/feed.xml", "application/rss+xml; charset=utf-8", "<link>http://example.com/feed.xml</link>"},
+		{"/feed.atom", "application/atom+xml; charset=utf-8", "<feed xmlns=\"http://www.w3.org/2005/Atom\">"},
+		{"/feed.json", "application/feed+json; charset=utf-8", `"version": "https://jsonfeed.org/version/1.1"`},
+		{"/feed?format=json", "application/feed+json; charset=utf-8", `"feed_url": "http://example.com/feed?format=json"`},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %s = %d, want 200", tt.target, rec.Code)
+		}
+		if got := rec.Header().Get("Content-Type"); got != tt.contentType {
+			t.Errorf("GET %s Content-Type = %q, want %q", tt.target, got, tt.contentType)
+		}
+		body := rec.Body.String()
+		if !strings.Contains(body, tt.contains) {
+			t.Errorf("GET %s does not contain %q:\n%s", tt.target, tt.contains, body)
+		}
+		// Only the most recently updated document in the archive is published
+		if !strings.Contains(body, "Newer") || strings.Contains(body, "Older") || strings.Contains(body, "doc3") {
+			t.Errorf("GET %s items:\n%s", tt.target, body)
+		}
+	}
+
+	// Documents are listed once within the TTL, with the filters the API supports
+	if c.calls != 1 {
+		t.Errorf("ListDocuments called %d times, want 1", c.calls)
+	}
+	if opts := c.opts[0]; opts.Location != reader.LocationArchive || !opts.WithHTMLContent {
+		t.Errorf("ListDocuments options = %+v", opts)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/feed.csv", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("GET /feed.csv = %d, want 404", rec.Code)
+	}
+}
+
+func TestHandler_Conditional(t *testing.T) {
+	h, err := NewHandler(&fakeClient{docs: testDocuments()}, HandlerConfig{Title: "Shared", Token: "t0ken", TTL: time.Hour})
+	if err != nil {
+		t.Fatalf("NewHandler() error = %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/feed.atom?token=t0ken", nil))
+	etag := rec.Header().Get("ETag")
+	lastModified := rec.Header().Get("Last-Modified")
+	if etag == "" || lastModified != "Sat, 12 Jul 2025 00:00:00 GMT" {
+		t.Fatalf("ETag = %q, Last-Modified = %q", etag, lastModified)
+	}
+
+	tests := []struct {
+		name   string
+		header string
+		value  string
+		want   int
+	}{
+		{"matching etag", "If-None-Match", etag, http.StatusNotModified},
+		{"other etag", "If-None-Match", `"other"`, http.StatusOK},
+		{"not modified since", "If-Modified-Since", lastModified, http.StatusNotModified},
+		{"modified since", "If-Modified-Since", "Fri, 11 Jul 2025 00:00:00 GMT", http.StatusOK},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/feed.atom?token=t0ken", nil)
+		req.Header.Set(tt.header, tt.value)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.name, rec.Code, tt.want)
+		}
+	}
+}
+
+func TestNewHandler_InvalidQuery(t *testing.T) {
+	if _, err := NewHandler(&fakeClient{}, HandlerConfig{Query: "nope=1"}); err == nil {
+		t.Error("NewHandler() error = nil, want invalid query")
+	}
+}
+
+func TestNewHandler_QueryOrToken(t *testing.T) {
+	if _, err := NewHandler(&fakeClient{}, HandlerConfig{}); err == nil {
+		t.Error("NewHandler() error = nil, want a query or a token to be required")
+	}
+}
+
+func TestHandler_Token(t *testing.T) {
+	h, err := NewHandler(&fakeClient{docs: testDocuments()}, HandlerConfig{Title: "Shared", Token: "t0ken", TTL: time.Hour})
+	if err != nil {
+		t.Fatalf("NewHandler() error = %v", err)
+	}
+
+	tests := []struct {
+		target string
+		want   int
+	}{
+		{"/feed", http.StatusUnauthorized},
+		{"/feed?token=wrong", http.StatusUnauthorized},
+		{"/feed?token=t0ken", http.StatusOK},
+		{"/feed.json?token=t0ken", http.StatusOK},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+		if rec.Code != tt.want {
+			t.Errorf("GET %s = %d, want %d", tt.target, rec.Code, tt.want)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+// Package gc keeps the locations of a Readwise Reader library from growing
+// without bound by applying queue hygiene policies, such as archiving feed
+// documents that were never opened or capping the inbox.
+//
+// A policy selects the documents matching a query (see reader.ParseQuery),
+// optionally only the oldest ones beyond a number of documents to keep, and
+// moves them to a location, the archive by default. Plan finds the documents
+// each policy selects without changing anything, and its Report counts them
+// by site and category so that a plan can be reviewed before Apply moves
+// the documents with UpdateDocument.
+package gc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	reader "github.com/tcnksm/go-readwise-reader"
+	"github.com/tcnksm/go-readwise-reader/journal"
+)
+
+// Config is a list of policies, typically loaded from a YAML file
+type Config struct {
+	// Policies are applied in order. A document selected by a policy is not
+	// selected by the following ones.
+	Policies []Policy `json:"policies" yaml:"policies"`
+}
+
+// Policy selects documents to move
+type Policy struct {
+	// Name identifies the policy in reports
+	Name string `json:"name" yaml:"name"`
+
+	// Query selects the documents the policy applies to, e.g.
+	// "location=feed and saved_at < -14d and seen=false". Relative times
+	// are relative to the time the plan is made.
+	Query string `json:"query" yaml:"query"`
+
+	// Keep keeps the Keep most recently saved documents matching the query
+	// and selects the older ones, e.g. to cap the inbox at Keep documents.
+	// With 0 every matching document is selected.
+	Keep int `json:"keep,omitempty" yaml:"keep,omitempty"`
+
+	// To is the location selected documents are moved to. Default: archive.
+	To reader.Location `json:"to,omitempty" yaml:"to,omitempty"`
+}
+
+// Compile validates the policies. It must be called before Plan.
+func (cfg *Config) Compile() error {
+	names := make(map[string]bool)
+	for i := range cfg.Policies {
+		p := &cfg.Policies[i]
+		if p.Name == "" {
+			return fmt.Errorf("policy %d: name is required", i+1)
+		}
+		if names[p.Name] {
+			return fmt.Errorf("policy %s: duplicate name", p.Name)
+		}
+		names[p.Name] = true
+
+		if p.Query == "" {
+			return fmt.Errorf("policy %s: query is required", p.Name)
+		}
+		if _, err := reader.ParseQuery(p.Query, time.Now()); err != nil {
+			return fmt.Errorf("policy %s: %w", p.Name, err)
+		}
+		if p.Keep < 0 {
+			return fmt.Errorf("policy %s: invalid keep: %d", p.Name, p.Keep)
+		}
+		if p.To == "" {
+			p.To = reader.LocationArchive
+		} else if !p.To.Valid() {
+			return fmt.Errorf("policy %s: invalid location: %s", p.Name, p.To)
+		}
+	}
+	return nil
+}
+
+// Move is a document selected by a policy
+type Move struct {
+	// Policy is the name of the policy that selected the document
+	Policy string `json:"policy"`
+
+	// Document is the selected document
+	Document reader.Document `json:"document"`
+
+	// To is the location the document is moved to
+	To reader.Location `json:"to"`
+}
+
+// Plan is the documents selected by policies
+type Plan struct {
+	// Moves are the selected documents in policy order
+	Moves []Move `json:"moves"`
+
+	policies []Policy
+}
+
+// Plan finds the documents each policy selects at now, without moving them
+func (cfg *Config) Plan(ctx context.Context, c reader.Client, now time.Time) (*Plan, error) {
+	plan := &Plan{Moves: []Move{}, policies: cfg.Policies}
+	selected := make(map[string]bool)
+	for _, p := range cfg.Policies {
+		q, err := reader.ParseQuery(p.Query, now)
+		if err != nil {
+			return nil, fmt.Errorf("policy %s: %w", p.Name, err)
+		}
+		docs, err := reader.FindDocuments(ctx, c, q.ListOptions(), func(doc reader.Document) bool {
+			return !doc.IsHighlight() && q.Match(doc)
+		}, 0)
+		if err != nil {
+			return nil, fmt.Errorf("policy %s: failed to list documents: %w", p.Name, err)
+		}
+
+		if p.Keep > 0 {
+			// Keep the newest documents, including those already in the
+			// target location or selected by an earlier policy
+			sort.SliceStable(docs, func(i, j int) bool {
+				return savedAt(docs[i]).After(savedAt(docs[j]))
+			})
+			docs = docs[min(p.Keep, len(docs)):]
+		}
+		for _, doc := range docs {
+			if selected[doc.ID] || doc.Location == p.To {
+				continue
+			}
+			selected[doc.ID] = true
+			plan.Moves = append(plan.Moves, Move{Policy: p.Name, Document: doc, To: p.To})
+		}
+	}
+	return plan, nil
+}
+
+// savedAt returns when a document was saved, or created if unknown
+func savedAt(doc reader.Document) time.Time {
+	if doc.SavedAt != nil {
+		return *doc.SavedAt
+	}
+	if doc.CreatedAt != nil {
+		return *doc.CreatedAt
+	}
+	return time.Time{}
+}
+
+// Report counts the documents a plan moves
+type Report struct {
+	// Documents is the number of documents moved by all policies
+	Documents int `json:"documents"`
+
+	// Policies are the counts per policy, in policy order
+	Policies []PolicyReport `json:"policies"`
+}
+
+// PolicyReport counts the documents a policy moves
+type PolicyReport struct {
+	Policy string          `json:"policy"`
+	To     reader.Location `json:"to"`
+
+	// Documents is the number of documents the policy moves
+	Documents int `json:"documents"`
+
+	// Sites and Categories are the number of documents by site name and
+	// category, most documents first
+	Sites      []Count `json:"sites"`
+	Categories []Count `json:"categories"`
+}
+
+// Count is a number of documents with a given site name or category
+type Count struct {
+	Name      string `json:"name"`
+	Documents int    `json:"documents"`
+}
+
+// Report counts the documents of the plan by policy, site and category.
+// Policies that select no documents are reported with zero documents.
+func (plan *Plan) Report() *Report {
+	report := &Report{Documents: len(plan.Moves), Policies: []PolicyReport{}}
+	for _, p := range plan.policies {
+		sites := make(map[string]int)
+		categories := make(map[string]int)
+		pr := PolicyReport{Policy: p.Name, To: p.To}
+		for _, m := range plan.Moves {
+			if m.Policy != p.Name {
+				continue
+			}
+			pr.Documents++
+			sites[nameOrUnknown(m.Document.SiteName)]++
+			categories[nameOrUnknown(string(m.Document.Category))]++
+		}
+		pr.Sites = sortCounts(sites)
+		pr.Categories = sortCounts(categories)
+		report.Policies = append(report.Policies, pr)
+	}
+	return report
+}
+
+func nameOrUnknown(name string) string {
+	if name == "" {
+		return "(unknown)"
+	}
+	return name
+}
+
+// sortCounts returns the counts, most documents first, then by name
+func sortCounts(counts map[string]int) []Count {
+	sorted := make([]Count, 0, len(counts))
+	for name, n := range counts {
+		sorted = append(sorted, Count{Name: name, Documents: n})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Documents != sorted[j].Documents {
+			return sorted[i].Documents > sorted[j].Documents
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// Result is the outcome of a move
+type Result struct {
+	Policy     string          `json:"policy"`
+	DocumentID string          `json:"document_id"`
+	Title      string          `json:"title"`
+	To         reader.Location `json:"to"`
+
+	// Error is the error returned by the API, if any
+	Error string `json:"error,omitempty"`
+}
+
+// Apply moves the documents of the plan in order with UpdateDocument and
+// returns the results. A failed move is recorded in its result and does not
+// stop the others. The moved documents are recorded in j, if not nil, as
+// one entry so that reader undo moves them all back.
+func Apply(ctx context.Context, c reader.Client, plan *Plan, j *journal.Journal) ([]Result, error) {
+	results := make([]Result, 0, len(plan.Moves))
+	var changes []journal.Change
+	for _, m := range plan.Moves {
+		result := Result{Policy: m.Policy, DocumentID: m.Document.ID, Title: m.Document.Title, To: m.To}
+		if _, err := c.UpdateDocument(ctx, m.Document.ID, &reader.UpdateDocumentRequest{Location: m.To}); err != nil {
+			result.Error = err.Error()
+		} else {
+			before := m.Document
+			changes = append(changes, journal.Change{Operation: journal.OperationUpdate, DocumentID: before.ID, Before: &before})
+		}
+		results = append(results, result)
+	}
+
+	if j != nil && len(changes) > 0 {
+		if _, err := j.Record("reader gc", changes); err != nil {
+			return results, fmt.Errorf("failed to record changes: %w", err)
+		}
+	}
+	for _, r := range results {
+		if r.Error != "" {
+			return results, errors.New("failed to move some documents")
+		}
+	}
+	return results, nil
+}
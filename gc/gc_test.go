@@ -0,0 +1,169 @@
+package gc
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	reader "github.com/tcnksm/go-readwise-reader"
+	"github.com/tcnksm/go-readwise-reader/journal"
+)
+
+// fakeClient is a reader.Client that lists fixed documents and records
+// updates
+type fakeClient struct {
+	reader.Client
+	docs    []reader.Document
+	updates []string
+	failID  string
+}
+
+func (c *fakeClient) ListDocuments(ctx context.Context, opts *reader.ListDocumentsOptions) (*reader.ListDocumentsResponse, error) {
+	var docs []reader.Document
+	for _, doc := range c.docs {
+		if opts.Location == "" || doc.Location == opts.Location {
+			docs = append(docs, doc)
+		}
+	}
+	return &reader.ListDocumentsResponse{Count: len(docs), Results: docs}, nil
+}
+
+func (c *fakeClient) UpdateDocument(ctx context.Context, documentID string, req *reader.UpdateDocumentRequest) (*reader.UpdateDocumentResponse, error) {
+	if documentID == c.failID {
+		return nil, errors.New("rate limited")
+	}
+	c.updates = append(c.updates, documentID+"->"+string(req.Location))
+	return &reader.UpdateDocumentResponse{ID: documentID}, nil
+}
+
+var now = time.Date(2025, 7, 20, 12, 0, 0, 0, time.UTC)
+
+func daysAgo(n int) *time.Time {
+	t := now.AddDate(0, 0, -n)
+	return &t
+}
+
+func testConfig(t *testing.T) *Config {
+	t.Helper()
+	cfg := &Config{Policies: []Policy{
+		{Name: "unread-feed", Query: "location=feed and saved_at < -14d and seen=false"},
+		{Name: "stale-later", Query: "location=later and last_moved_at < -180d and tag!=keep"},
+		{Name: "inbox-cap", Query: "location=new", Keep: 2, To: reader.LocationLater},
+	}}
+	if err := cfg.Compile(); err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+	return cfg
+}
+
+func testDocuments() []reader.Document {
+	return []reader.Document{
+		{ID: "feed-old", Location: reader.LocationFeed, SiteName: "Example", Category: reader.CategoryRSS, SavedAt: daysAgo(20)},
+		{ID: "feed-old2", Location: reader.LocationFeed, SiteName: "Example", Category: reader.CategoryArticle, SavedAt: daysAgo(30)},
+		{ID: "feed-opened", Location: reader.LocationFeed, SavedAt: daysAgo(20), FirstOpenedAt: daysAgo(19)},
+		{ID: "feed-recent", Location: reader.LocationFeed, SavedAt: daysAgo(3)},
+		{ID: "later-stale", Location: reader.LocationLater, SiteName: "Blog", Category: reader.CategoryArticle, LastMovedAt: daysAgo(200)},
+		{ID: "later-keep", Location: reader.LocationLater, LastMovedAt: daysAgo(200), Tags: map[string]interface{}{"keep": nil}},
+		{ID: "later-fresh", Location: reader.LocationLater, LastMovedAt: daysAgo(10)},
+		{ID: "new-1", Location: reader.LocationNew, SavedAt: daysAgo(1)},
+		{ID: "new-2", Location: reader.LocationNew, SavedAt: daysAgo(5)},
+		{ID: "new-3", Location: reader.LocationNew, SavedAt: daysAgo(3)},
+		{ID: "new-4", Location: reader.LocationNew, CreatedAt: daysAgo(9)},
+		{ID: "new-highlight", Location: reader.LocationNew, Category: reader.CategoryHighlight, SavedAt: daysAgo(50)},
+	}
+}
+
+func TestConfig_Plan(t *testing.T) {
+	cfg := testConfig(t)
+	plan, err := cfg.Plan(context.Background(), &fakeClient{docs: testDocuments()}, now)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+
+	var got []string
+	for _, m := range plan.Moves {
+		got = append(got, m.Policy+":"+m.Document.ID+"->"+string(m.To))
+	}
+	want := []string{
+		"unread-feed:feed-old->archive",
+		"unread-feed:feed-old2->archive",
+		"stale-later:later-stale->archive",
+		"inbox-cap:new-2->later",
+		"inbox-cap:new-4->later",
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Plan() = %v, want %v", got, want)
+	}
+
+	report := plan.Report()
+	if report.Documents != 5 || len(report.Policies) != 3 {
+		t.Fatalf("Report() = %+v", report)
+	}
+	feed := report.Policies[0]
+	if feed.Documents != 2 || len(feed.Sites) != 1 || feed.Sites[0] != (Count{Name: "Example", Documents: 2}) {
+		t.Errorf("feed report = %+v", feed)
+	}
+	if len(feed.Categories) != 2 || feed.Categories[0].Name != "article" || feed.Categories[1].Name != "rss" {
+		t.Errorf("feed categories = %+v, want article and rss", feed.Categories)
+	}
+	if inbox := report.Policies[2]; inbox.Sites[0] != (Count{Name: "(unknown)", Documents: 2}) {
+		t.Errorf("inbox report = %+v", inbox)
+	}
+}
+
+func TestApply(t *testing.T) {
+	cfg := testConfig(t)
+	c := &fakeClient{docs: testDocuments(), failID: "feed-old2"}
+	plan, err := cfg.Plan(context.Background(), c, now)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	j := journal.Open(filepath.Join(t.TempDir(), "journal.jsonl"))
+
+	results, err := Apply(context.Background(), c, plan, j)
+	if err == nil {
+		t.Error("Apply() error = nil, want error for the failed move")
+	}
+	if len(results) != 5 || results[1].Error == "" || results[0].Error != "" {
+		t.Errorf("Apply() = %+v", results)
+	}
+	if len(c.updates) != 4 || c.updates[0] != "feed-old->archive" || c.updates[3] != "new-4->later" {
+		t.Errorf("updates = %v", c.updates)
+	}
+
+	// The moves are undone as one entry
+	entries, err := j.Entries()
+	if err != nil {
+		t.Fatalf("Entries() error = %v", err)
+	}
+	if len(entries) != 1 || entries[0].Command != "reader gc" || len(entries[0].Changes) != 4 {
+		t.Errorf("journal = %+v", entries)
+	}
+}
+
+func TestConfig_Compile(t *testing.T) {
+	tests := []struct {
+		policy Policy
+		want   string
+	}{
+		{Policy{Query: "location=feed"}, "name is required"},
+		{Policy{Name: "a"}, "query is required"},
+		{Policy{Name: "a", Query: "size>1"}, "policy a:"},
+		{Policy{Name: "a", Query: "location=new", Keep: -1}, "invalid keep"},
+		{Policy{Name: "a", Query: "location=new", To: "trash"}, "invalid location"},
+	}
+	for _, tt := range tests {
+		cfg := &Config{Policies: []Policy{tt.policy}}
+		if err := cfg.Compile(); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Compile(%+v) error = %v, want %q", tt.policy, err, tt.want)
+		}
+	}
+
+	cfg := &Config{Policies: []Policy{{Name: "a", Query: "location=new"}}}
+	if err := cfg.Compile(); err != nil || cfg.Policies[0].To != reader.LocationArchive {
+		t.Errorf("Compile() error = %v, To = %q, want archive", err, cfg.Policies[0].To)
+	}
+}
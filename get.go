@@ -0,0 +1,48 @@
+package reader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrNotFound is returned when a document does not exist. API errors with
+// status 404 match it with errors.Is.
+var ErrNotFound = errors.New("document not found")
+
+// GetDocument retrieves a single document by ID, including its HTML content
+// if withHTMLContent is true. It returns an error wrapping ErrNotFound if the
+// document does not exist.
+func GetDocument(ctx context.Context, c Client, documentID string, withHTMLContent bool) (*Document, error) {
+	if documentID == "" {
+		return nil, &ClientError{
+			Type:    "invalid_parameter",
+			Message: "document ID cannot be empty",
+		}
+	}
+
+	resp, err := c.ListDocuments(ctx, &ListDocumentsOptions{
+		ID:              documentID,
+		WithHTMLContent: withHTMLContent,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, doc := range resp.Results {
+		if doc.ID == documentID {
+			return &doc, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrNotFound, documentID)
+}
+
+// Children retrieves the highlights and notes whose parent is the given
+// document. The API cannot filter by parent, so this reads every highlight
+// and note and can take many requests for a large library.
+func Children(ctx context.Context, c Client, documentID string) ([]Document, error) {
+	highlights, err := ListHighlights(ctx, c, nil)
+	if err != nil {
+		return nil, err
+	}
+	return GroupByParent(highlights)[documentID], nil
+}
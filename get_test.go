@@ -0,0 +1,102 @@
+package reader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetDocument(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("withHtmlContent"); got != "true" {
+			t.Errorf("withHtmlContent = %q, want true", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("id") {
+		case "doc123":
+			fmt.Fprint(w, `{"count": 1, "results": [{
+				"id": "doc123",
+				"title": "Article",
+				"html_content": "<p>Hello</p>",
+				"image_url": "https://example.com/cover.png",
+				"published_date": "2024-05-15",
+				"reading_time": "5 mins",
+				"parent_id": null
+			}]}`)
+		default:
+			fmt.Fprint(w, `{"count": 0, "results": []}`)
+		}
+	}))
+	defer server.Close()
+
+	c := &client{
+		baseURL:    server.URL,
+		token:      "test-token",
+		httpClient: &http.Client{},
+	}
+	ctx := context.Background()
+
+	doc, err := GetDocument(ctx, c, "doc123", true)
+	if err != nil {
+		t.Fatalf("GetDocument() error = %v", err)
+	}
+	if doc.ID != "doc123" || doc.HTMLContent != "<p>Hello</p>" {
+		t.Errorf("GetDocument() = %+v, want doc123 with HTML content", doc)
+	}
+	if doc.ImageURL != "https://example.com/cover.png" || doc.PublishedDate != "2024-05-15" || doc.ReadingTime != "5 mins" || doc.ParentID != "" {
+		t.Errorf("GetDocument() = %+v, want image URL, published date and reading time", doc)
+	}
+
+	if _, err := GetDocument(ctx, c, "missing", true); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetDocument(missing) error = %v, want ErrNotFound", err)
+	}
+
+	var clientErr *ClientError
+	if _, err := GetDocument(ctx, c, "", true); !errors.As(err, &clientErr) {
+		t.Errorf("GetDocument(\"\") error = %v, want ClientError", err)
+	}
+}
+
+func TestAPIError_IsNotFound(t *testing.T) {
+	if !errors.Is(fmt.Errorf("failed: %w", &APIError{StatusCode: http.StatusNotFound}), ErrNotFound) {
+		t.Error("404 API error does not match ErrNotFound")
+	}
+	if errors.Is(&APIError{StatusCode: http.StatusInternalServerError}, ErrNotFound) {
+		t.Error("500 API error matches ErrNotFound")
+	}
+}
+
+func TestChildren(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch category := r.URL.Query().Get("category"); category {
+		case "highlight":
+			fmt.Fprint(w, `{"results": [
+				{"id": "h1", "category": "highlight", "parent_id": "doc123"},
+				{"id": "h2", "category": "highlight", "parent_id": "other"}
+			]}`)
+		case "note":
+			fmt.Fprint(w, `{"results": [{"id": "n1", "category": "note", "parent_id": "doc123"}]}`)
+		default:
+			t.Errorf("unexpected category %q", category)
+		}
+	}))
+	defer server.Close()
+
+	c := &client{
+		baseURL:    server.URL,
+		token:      "test-token",
+		httpClient: &http.Client{},
+	}
+
+	children, err := Children(context.Background(), c, "doc123")
+	if err != nil {
+		t.Fatalf("Children() error = %v", err)
+	}
+	if len(children) != 2 || children[0].ID != "h1" || children[1].ID != "n1" {
+		t.Errorf("Children() = %+v, want h1 and n1", children)
+	}
+}
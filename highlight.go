@@ -0,0 +1,54 @@
+package reader
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// IsHighlight reports whether the document is a highlight or a note that
+// belongs to another document
+func (d Document) IsHighlight() bool {
+	return d.Category == CategoryHighlight || d.Category == CategoryNote
+}
+
+// ListHighlights retrieves every highlight and note, or only those updated
+// after updatedAfter if it is not nil. Highlights are sorted by when they were
+// created, oldest first.
+func ListHighlights(ctx context.Context, c Client, updatedAfter *time.Time) ([]Document, error) {
+	var highlights []Document
+	for _, category := range []Category{CategoryHighlight, CategoryNote} {
+		docs, err := ListAllDocuments(ctx, c, &ListDocumentsOptions{
+			Category:     category,
+			UpdatedAfter: updatedAfter,
+		})
+		if err != nil {
+			return nil, err
+		}
+		highlights = append(highlights, docs...)
+	}
+	sort.SliceStable(highlights, func(i, j int) bool {
+		return timeOrZero(highlights[i].CreatedAt).Before(timeOrZero(highlights[j].CreatedAt))
+	})
+	return highlights, nil
+}
+
+// GroupByParent groups highlights and notes by the ID of the document they
+// belong to, keeping their order. Documents without a parent are left out.
+func GroupByParent(highlights []Document) map[string][]Document {
+	groups := make(map[string][]Document)
+	for _, h := range highlights {
+		if h.ParentID == "" {
+			continue
+		}
+		groups[h.ParentID] = append(groups[h.ParentID], h)
+	}
+	return groups
+}
+
+func timeOrZero(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
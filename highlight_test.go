@@ -0,0 +1,69 @@
+package reader
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestListHighlights(t *testing.T) {
+	since := time.Date(2025, 7, 13, 0, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("updatedAfter"); got != since.Format(time.RFC3339) {
+			t.Errorf("updatedAfter = %q, want %q", got, since.Format(time.RFC3339))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("category") {
+		case "highlight":
+			fmt.Fprint(w, `{"results": [
+				{"id": "h2", "category": "highlight", "parent_id": "doc1", "created_at": "2025-07-15T00:00:00Z"},
+				{"id": "h1", "category": "highlight", "parent_id": "doc2", "created_at": "2025-07-14T00:00:00Z"}
+			]}`)
+		case "note":
+			fmt.Fprint(w, `{"results": [{"id": "n1", "category": "note", "parent_id": "doc1", "created_at": "2025-07-16T00:00:00Z"}]}`)
+		}
+	}))
+	defer server.Close()
+
+	c := &client{
+		baseURL:    server.URL,
+		token:      "test-token",
+		httpClient: &http.Client{},
+	}
+
+	highlights, err := ListHighlights(context.Background(), c, &since)
+	if err != nil {
+		t.Fatalf("ListHighlights() error = %v", err)
+	}
+	var ids []string
+	for _, h := range highlights {
+		ids = append(ids, h.ID)
+		if !h.IsHighlight() {
+			t.Errorf("IsHighlight() = false for %s", h.ID)
+		}
+	}
+	if fmt.Sprint(ids) != "[h1 h2 n1]" {
+		t.Errorf("ListHighlights() = %v, want [h1 h2 n1]", ids)
+	}
+}
+
+func TestGroupByParent(t *testing.T) {
+	groups := GroupByParent([]Document{
+		{ID: "h1", ParentID: "doc1"},
+		{ID: "h2", ParentID: "doc2"},
+		{ID: "h3", ParentID: "doc1"},
+		{ID: "orphan"},
+	})
+	if len(groups) != 2 {
+		t.Fatalf("len(groups) = %d, want 2", len(groups))
+	}
+	if got := groups["doc1"]; len(got) != 2 || got[0].ID != "h1" || got[1].ID != "h3" {
+		t.Errorf("groups[doc1] = %+v, want h1 and h3", got)
+	}
+	if got := groups["doc2"]; len(got) != 1 || got[0].ID != "h2" {
+		t.Errorf("groups[doc2] = %+v, want h2", got)
+	}
+}
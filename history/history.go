@@ -0,0 +1,235 @@
+// Package history keeps a local history of the versions of Readwise Reader
+// documents observed by syncing, watching or receiving webhooks, so that
+// what changed between them can be shown, such as the locations a document
+// was moved between and how its reading progress advanced.
+//
+// Each version is stored with its changes from the previous one (see
+// reader.Diff) and is only recorded if its location, reading progress,
+// title, tags or notes changed. The versions of a document are stored as
+// JSON lines in a file named after its ID in the history directory.
+package history
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	reader "github.com/tcnksm/go-readwise-reader"
+)
+
+// Source is where a version was observed
+type Source string
+
+const (
+	// SourceSync is a version listed by Sync
+	SourceSync Source = "sync"
+
+	// SourceWatch is a version found by polling, see reader.Watch
+	SourceWatch Source = "watch"
+
+	// SourceWebhook is a version received in a webhook event
+	SourceWebhook Source = "webhook"
+)
+
+// Version is an observed version of a document
+type Version struct {
+	// Time is when the version was observed
+	Time time.Time `json:"time"`
+
+	// Source is where the version was observed
+	Source Source `json:"source"`
+
+	// Changes are the changes from the previous version. They are empty for
+	// the first version.
+	Changes []reader.FieldChange `json:"changes,omitempty"`
+
+	// Document is the document, without its HTML content
+	Document reader.Document `json:"document"`
+}
+
+// Store is a history stored in a directory
+type Store struct {
+	dir string
+
+	// mu serializes versions recorded concurrently, e.g. by webhooks
+	mu sync.Mutex
+}
+
+// Open returns the history stored in dir. The directory is created on the
+// first Record.
+func Open(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Record records a version of a document and returns it. It returns nil if
+// the version has no changes from the previous one, or if it is older than
+// the previous one, as when a webhook is delivered late.
+func (s *Store) Record(source Source, doc reader.Document) (*Version, error) {
+	path, err := s.path(doc.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	versions, err := readVersions(path)
+	if err != nil {
+		return nil, err
+	}
+	var changes []reader.FieldChange
+	if len(versions) > 0 {
+		last := versions[len(versions)-1].Document
+		if doc.UpdatedAt != nil && last.UpdatedAt != nil && doc.UpdatedAt.Before(*last.UpdatedAt) {
+			return nil, nil
+		}
+		if changes = reader.Diff(last, doc); len(changes) == 0 {
+			return nil, nil
+		}
+	}
+
+	doc.HTMLContent = ""
+	version := Version{Time: time.Now(), Source: source, Changes: changes, Document: doc}
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create history directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history: %w", err)
+	}
+	defer file.Close()
+
+	if err := json.NewEncoder(file).Encode(version); err != nil {
+		return nil, fmt.Errorf("failed to write history: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write history: %w", err)
+	}
+	return &version, nil
+}
+
+// RecordPayload records the version of a document received in a webhook
+// event, see Record
+func (s *Store) RecordPayload(payload *reader.DocumentWebhookPayload) (*Version, error) {
+	return s.Record(SourceWebhook, payload.ToDocument())
+}
+
+// Versions returns the recorded versions of a document, oldest first
+func (s *Store) Versions(documentID string) ([]Version, error) {
+	path, err := s.path(documentID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return readVersions(path)
+}
+
+// syncState is the state of Sync stored in the history directory
+type syncState struct {
+	LastSync time.Time `json:"last_sync"`
+}
+
+// Sync records the versions of the documents matching opts that were
+// updated since the last sync, or of all of them on the first sync, and
+// returns the number of recorded versions. Highlights and notes are not
+// recorded. An UpdatedAfter in opts overrides the time of the last sync.
+func (s *Store) Sync(ctx context.Context, c reader.Client, opts *reader.ListDocumentsOptions) (int, error) {
+	var o reader.ListDocumentsOptions
+	if opts != nil {
+		o = *opts
+	}
+	statePath := filepath.Join(s.dir, "sync.json")
+	if o.UpdatedAfter == nil {
+		var state syncState
+		data, err := os.ReadFile(statePath)
+		switch {
+		case err == nil:
+			if err := json.Unmarshal(data, &state); err != nil {
+				return 0, fmt.Errorf("failed to read sync state: %w", err)
+			}
+			o.UpdatedAfter = &state.LastSync
+		case !errors.Is(err, fs.ErrNotExist):
+			return 0, fmt.Errorf("failed to read sync state: %w", err)
+		}
+	}
+
+	// Documents updated while listing are listed again by the next sync and
+	// are not recorded twice
+	next := time.Now()
+	docs, err := reader.ListAllDocuments(ctx, c, &o)
+	if err != nil {
+		return 0, err
+	}
+	recorded := 0
+	for _, doc := range docs {
+		if doc.IsHighlight() {
+			continue
+		}
+		version, err := s.Record(SourceSync, doc)
+		if err != nil {
+			return recorded, err
+		}
+		if version != nil {
+			recorded++
+		}
+	}
+
+	data, err := json.Marshal(syncState{LastSync: next})
+	if err != nil {
+		return recorded, err
+	}
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return recorded, fmt.Errorf("failed to create history directory: %w", err)
+	}
+	if err := os.WriteFile(statePath, data, 0o600); err != nil {
+		return recorded, fmt.Errorf("failed to write sync state: %w", err)
+	}
+	return recorded, nil
+}
+
+// path returns the path of the versions of a document
+func (s *Store) path(documentID string) (string, error) {
+	if documentID == "" || strings.ContainsAny(documentID, `/\`) || strings.HasPrefix(documentID, ".") {
+		return "", fmt.Errorf("invalid document ID: %q", documentID)
+	}
+	return filepath.Join(s.dir, documentID+".jsonl"), nil
+}
+
+// readVersions reads the versions stored at path, or none if the file does
+// not exist
+func readVersions(path string) ([]Version, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open history: %w", err)
+	}
+	defer file.Close()
+
+	var versions []Version
+	scanner := bufio.NewScanner(file)
+	// Versions hold summaries and notes, so lines can be long
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var version Version
+		if err := json.Unmarshal(scanner.Bytes(), &version); err != nil {
+			return nil, fmt.Errorf("failed to read version %d: %w", len(versions)+1, err)
+		}
+		versions = append(versions, version)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	return versions, nil
+}
@@ -0,0 +1,132 @@
+package history
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	reader "github.com/tcnksm/go-readwise-reader"
+)
+
+// fakeClient is a reader.Client that lists fixed documents
+type fakeClient struct {
+	reader.Client
+	docs []reader.Document
+	opts []reader.ListDocumentsOptions
+}
+
+func (c *fakeClient) ListDocuments(ctx context.Context, opts *reader.ListDocumentsOptions) (*reader.ListDocumentsResponse, error) {
+	c.opts = append(c.opts, *opts)
+	return &reader.ListDocumentsResponse{Count: len(c.docs), Results: c.docs}, nil
+}
+
+func at(hour int) *time.Time {
+	t := time.Date(2025, 9, 1, hour, 0, 0, 0, time.UTC)
+	return &t
+}
+
+func TestStore_Record(t *testing.T) {
+	s := Open(filepath.Join(t.TempDir(), "history"))
+
+	doc := reader.Document{ID: "doc1", Title: "Essay", Location: reader.LocationNew, UpdatedAt: at(1), HTMLContent: "<p>Essay</p>"}
+	v, err := s.Record(SourceSync, doc)
+	if err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if v == nil || v.Changes != nil || v.Document.HTMLContent != "" {
+		t.Errorf("first version = %+v, want no changes and no content", v)
+	}
+
+	// Versions without changes are not recorded
+	doc.Author = "Jane"
+	doc.UpdatedAt = at(2)
+	if v, err := s.Record(SourceWatch, doc); err != nil || v != nil {
+		t.Errorf("Record() = %+v, %v, want nil for an unchanged version", v, err)
+	}
+
+	doc.Location = reader.LocationLater
+	doc.ReadingProgressPercent = 40
+	doc.UpdatedAt = at(3)
+	v, err = s.Record(SourceWatch, doc)
+	if err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if v == nil || len(v.Changes) != 2 || v.Changes[0].String() != "location: new -> later" || v.Changes[1].String() != "progress: 0% -> 40%" {
+		t.Errorf("version = %+v, want the move and progress", v)
+	}
+
+	// A late webhook with an older version is ignored
+	late := &reader.DocumentWebhookPayload{ID: "doc1", Title: "Essay", Location: reader.LocationNew, UpdatedAt: at(2)}
+	if v, err := s.RecordPayload(late); err != nil || v != nil {
+		t.Errorf("RecordPayload() = %+v, %v, want nil for an older version", v, err)
+	}
+	archived := &reader.DocumentWebhookPayload{ID: "doc1", Title: "Essay", Location: reader.LocationArchive, ReadingProgress: 100, UpdatedAt: at(4)}
+	if v, err := s.RecordPayload(archived); err != nil || v == nil || v.Source != SourceWebhook {
+		t.Errorf("RecordPayload() = %+v, %v, want a webhook version", v, err)
+	}
+
+	versions, err := s.Versions("doc1")
+	if err != nil {
+		t.Fatalf("Versions() error = %v", err)
+	}
+	var locations []reader.Location
+	for _, v := range versions {
+		locations = append(locations, v.Document.Location)
+	}
+	if len(versions) != 3 || locations[0] != reader.LocationNew || locations[1] != reader.LocationLater || locations[2] != reader.LocationArchive {
+		t.Errorf("Versions() locations = %v, want [new later archive]", locations)
+	}
+
+	if versions, err := s.Versions("unknown"); err != nil || versions != nil {
+		t.Errorf("Versions() = %v, %v, want none", versions, err)
+	}
+	for _, id := range []string{"", "../doc1", `a\b`, ".sync"} {
+		if _, err := s.Versions(id); err == nil {
+			t.Errorf("Versions(%q) error = nil, want an invalid ID", id)
+		}
+	}
+}
+
+func TestStore_Sync(t *testing.T) {
+	s := Open(filepath.Join(t.TempDir(), "history"))
+	c := &fakeClient{docs: []reader.Document{
+		{ID: "doc1", Location: reader.LocationNew, UpdatedAt: at(1)},
+		{ID: "doc2", Location: reader.LocationFeed, UpdatedAt: at(1)},
+		{ID: "hl1", Category: reader.CategoryHighlight, ParentID: "doc1", UpdatedAt: at(1)},
+	}}
+
+	before := time.Now()
+	n, err := s.Sync(context.Background(), c, nil)
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if n != 2 {
+		t.Errorf("Sync() = %d, want 2 documents without the highlight", n)
+	}
+	if c.opts[0].UpdatedAfter != nil {
+		t.Errorf("first sync UpdatedAfter = %v, want nil", c.opts[0].UpdatedAfter)
+	}
+
+	// The next sync lists the documents updated since the last one
+	c.docs[0].Location = reader.LocationArchive
+	c.docs[0].UpdatedAt = at(2)
+	n, err = s.Sync(context.Background(), c, nil)
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if n != 1 {
+		t.Errorf("Sync() = %d, want 1 changed document", n)
+	}
+	if after := c.opts[1].UpdatedAfter; after == nil || after.Before(before) {
+		t.Errorf("second sync UpdatedAfter = %v, want the time of the first sync", after)
+	}
+
+	versions, err := s.Versions("doc1")
+	if err != nil {
+		t.Fatalf("Versions() error = %v", err)
+	}
+	if len(versions) != 2 || versions[1].Source != SourceSync || versions[1].Changes[0].From != "new" {
+		t.Errorf("Versions() = %+v", versions)
+	}
+}
@@ -0,0 +1,287 @@
+package reader
+
+import (
+	"context"
+	"crypto/tls"
+	"log/slog"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Instrumentation receives an event before and after every HTTP request the
+// client makes, e.g. to record metrics or emit trace spans. Retried requests
+// are reported once per attempt.
+type Instrumentation interface {
+	// RequestStarted is called before a request is sent. The returned context
+	// is used for the request and passed to RequestFinished, so implementations
+	// can start a span and end it when the request finishes.
+	RequestStarted(ctx context.Context, info RequestInfo) context.Context
+
+	// RequestFinished is called after a request completes or fails
+	RequestFinished(ctx context.Context, info RequestInfo, result RequestResult)
+}
+
+// RequestInfo describes a request made by the client
+type RequestInfo struct {
+	// Endpoint is the API endpoint: "list", "save", "update", "delete" or "auth"
+	Endpoint string
+
+	// Method is the HTTP method
+	Method string
+
+	// DocumentID is the ID of the document the request is about, if any
+	DocumentID string
+
+	// Attempt is the attempt number, starting at 1. Attempts after the first
+	// are retries.
+	Attempt int
+}
+
+// RequestResult describes the outcome of a request made by the client
+type RequestResult struct {
+	// StatusCode is the HTTP status code, or 0 if no response was received
+	StatusCode int
+
+	// Duration is how long the request took
+	Duration time.Duration
+
+	// Err is the error if no response was received
+	Err error
+
+	// RateLimit is the rate limit reported by the response headers, or nil if
+	// there is none, see ParseRateLimit
+	RateLimit *RateLimit
+}
+
+// multiInstrumentation reports to several instrumentations in order
+type multiInstrumentation []Instrumentation
+
+func (m multiInstrumentation) RequestStarted(ctx context.Context, info RequestInfo) context.Context {
+	for _, inst := range m {
+		ctx = inst.RequestStarted(ctx, info)
+	}
+	return ctx
+}
+
+func (m multiInstrumentation) RequestFinished(ctx context.Context, info RequestInfo, result RequestResult) {
+	for _, inst := range m {
+		inst.RequestFinished(ctx, info, result)
+	}
+}
+
+// instrumentations returns the instrumentations the client reports to
+func (c *client) instrumentations() []Instrumentation {
+	if m, ok := c.instrumentation.(multiInstrumentation); ok {
+		return m
+	}
+	if c.instrumentation != nil {
+		return []Instrumentation{c.instrumentation}
+	}
+	return nil
+}
+
+// do executes an HTTP request for an endpoint, reporting it to the
+// instrumentation and retrying it while it is rate limited
+func (c *client) do(req *http.Request, endpoint, documentID string) (*http.Response, error) {
+	info := RequestInfo{
+		Endpoint:   endpoint,
+		Method:     req.Method,
+		DocumentID: documentID,
+	}
+
+	for attempt := 1; ; attempt++ {
+		info.Attempt = attempt
+
+		if err := c.limiter.wait(req.Context()); err != nil {
+			return nil, err
+		}
+
+		ctx := req.Context()
+		if c.instrumentation != nil {
+			ctx = c.instrumentation.RequestStarted(ctx, info)
+		}
+
+		logger := c.log().With(
+			slog.String("endpoint", endpoint),
+			slog.String("method", req.Method),
+			slog.String("document_id", documentID),
+			slog.Int("attempt", attempt),
+		)
+		logger.DebugContext(ctx, "sending request",
+			slog.String("url", req.URL.String()),
+			slog.Any("headers", logHeader(req.Header)),
+		)
+
+		start := time.Now()
+		resp, err := c.httpClient.Do(req.WithContext(ctx))
+		duration := time.Since(start)
+
+		if err != nil {
+			logger.DebugContext(ctx, "request failed", slog.Duration("duration", duration), slog.Any("error", err))
+		} else {
+			logger.DebugContext(ctx, "received response", slog.Duration("duration", duration), slog.Int("status", resp.StatusCode))
+		}
+
+		if c.instrumentation != nil {
+			result := RequestResult{
+				Duration: duration,
+				Err:      err,
+			}
+			if resp != nil {
+				result.StatusCode = resp.StatusCode
+				if rl, ok := ParseRateLimit(resp.Header, time.Now()); ok {
+					result.RateLimit = &rl
+				}
+			}
+			c.instrumentation.RequestFinished(ctx, info, result)
+		}
+
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests || attempt > c.maxRetries {
+			return resp, err
+		}
+
+		// Rate limited: wait and retry with a fresh body
+		wait := retryAfter(resp, attempt)
+		logger.InfoContext(ctx, "rate limited, retrying", slog.Duration("wait", wait))
+		resp.Body.Close()
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// rateLimiter spaces requests at least interval apart
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// wait blocks until the next request may be sent. A nil limiter never blocks.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	at := l.next
+	if at.Before(now) {
+		at = now
+	}
+	l.next = at.Add(l.interval)
+	l.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(time.Until(at)):
+		return nil
+	}
+}
+
+// retryAfter returns how long to wait before retrying a rate limited
+// request: the Retry-After header read by ParseRateLimit, or attempt seconds
+// if the response has none
+func retryAfter(resp *http.Response, attempt int) time.Duration {
+	if rl, _ := ParseRateLimit(resp.Header, time.Now()); rl.RetryAfter != nil {
+		return *rl.RetryAfter
+	}
+	return time.Duration(attempt) * time.Second
+}
+
+// ConnectionTrace holds connection-level timings of a request collected with
+// net/http/httptrace. Durations are zero for phases that did not happen, e.g.
+// DNS lookup and connecting when a connection was reused.
+type ConnectionTrace struct {
+	// Method is the HTTP method
+	Method string
+
+	// URL is the request URL
+	URL string
+
+	// Reused is true if an idle connection was reused
+	Reused bool
+
+	// DNS is the time spent resolving the host name
+	DNS time.Duration
+
+	// Connect is the time spent establishing the TCP connection
+	Connect time.Duration
+
+	// TLSHandshake is the time spent on the TLS handshake
+	TLSHandshake time.Duration
+
+	// TimeToFirstByte is the time from the start of the request until the
+	// first response byte
+	TimeToFirstByte time.Duration
+}
+
+// tracingTransport is an http.RoundTripper that collects httptrace timings
+type tracingTransport struct {
+	base http.RoundTripper
+	fn   func(ctx context.Context, trace ConnectionTrace)
+}
+
+// NewTracingTransport returns an http.RoundTripper that sends requests with
+// base (http.DefaultTransport if nil) and reports the connection timings of
+// each request to fn. Use it with WithHTTPClient.
+func NewTracingTransport(base http.RoundTripper, fn func(ctx context.Context, trace ConnectionTrace)) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &tracingTransport{base: base, fn: fn}
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ct := ConnectionTrace{
+		Method: req.Method,
+		URL:    req.URL.String(),
+	}
+
+	start := time.Now()
+	var dnsStart, connectStart, tlsStart time.Time
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			ct.Reused = info.Reused
+		},
+		DNSStart: func(httptrace.DNSStartInfo) {
+			dnsStart = time.Now()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			ct.DNS = time.Since(dnsStart)
+		},
+		ConnectStart: func(string, string) {
+			connectStart = time.Now()
+		},
+		ConnectDone: func(string, string, error) {
+			ct.Connect = time.Since(connectStart)
+		},
+		TLSHandshakeStart: func() {
+			tlsStart = time.Now()
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			ct.TLSHandshake = time.Since(tlsStart)
+		},
+		GotFirstResponseByte: func() {
+			ct.TimeToFirstByte = time.Since(start)
+		},
+	}
+
+	ctx := req.Context()
+	resp, err := t.base.RoundTrip(req.WithContext(httptrace.WithClientTrace(ctx, trace)))
+	t.fn(ctx, ct)
+	return resp, err
+}
@@ -0,0 +1,76 @@
+// Package otel provides a reader.Instrumentation that emits OpenTelemetry
+// spans for the requests made by a Readwise Reader client.
+//
+// It is a separate module so that the library itself does not depend on
+// OpenTelemetry.
+//
+//	client, err := reader.NewClient(token, reader.WithInstrumentation(otel.New(nil)))
+package otel
+
+import (
+	"context"
+	"net/http"
+
+	reader "github.com/tcnksm/go-readwise-reader"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/tcnksm/go-readwise-reader/instrument/otel"
+
+// Instrumentation emits a client span for every request attempt
+type Instrumentation struct {
+	tracer trace.Tracer
+}
+
+// New creates an Instrumentation that emits spans with tp. If tp is nil, the
+// global tracer provider is used.
+func New(tp trace.TracerProvider) *Instrumentation {
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	return &Instrumentation{
+		tracer: tp.Tracer(instrumentationName),
+	}
+}
+
+// RequestStarted implements reader.Instrumentation. It starts a span named
+// "reader.<endpoint>" with the endpoint and document ID as attributes.
+func (i *Instrumentation) RequestStarted(ctx context.Context, info reader.RequestInfo) context.Context {
+	attrs := []attribute.KeyValue{
+		attribute.String("reader.endpoint", info.Endpoint),
+		attribute.String("http.request.method", info.Method),
+	}
+	if info.DocumentID != "" {
+		attrs = append(attrs, attribute.String("reader.document_id", info.DocumentID))
+	}
+	if info.Attempt > 1 {
+		attrs = append(attrs, attribute.Int("http.request.resend_count", info.Attempt-1))
+	}
+
+	ctx, _ = i.tracer.Start(ctx, "reader."+info.Endpoint,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+	return ctx
+}
+
+// RequestFinished implements reader.Instrumentation. It records the status
+// code or error and ends the span.
+func (i *Instrumentation) RequestFinished(ctx context.Context, info reader.RequestInfo, result reader.RequestResult) {
+	span := trace.SpanFromContext(ctx)
+	defer span.End()
+
+	if result.Err != nil {
+		span.RecordError(result.Err)
+		span.SetStatus(codes.Error, result.Err.Error())
+		return
+	}
+
+	span.SetAttributes(attribute.Int("http.response.status_code", result.StatusCode))
+	if result.StatusCode >= 400 {
+		span.SetStatus(codes.Error, http.StatusText(result.StatusCode))
+	}
+}
@@ -0,0 +1,73 @@
+package otel
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	reader "github.com/tcnksm/go-readwise-reader"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestInstrumentation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/delete/missing/" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	client, err := reader.NewClient("test-token",
+		reader.WithBaseURL(server.URL),
+		reader.WithInstrumentation(New(tp)),
+	)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	if err := client.DeleteDocument(context.Background(), "doc123"); err != nil {
+		t.Fatalf("DeleteDocument() error = %v", err)
+	}
+	if err := client.DeleteDocument(context.Background(), "missing"); err == nil {
+		t.Fatal("Expected error, got none")
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("len(spans) = %d, want 2", len(spans))
+	}
+
+	span := spans[0]
+	if span.Name() != "reader.delete" {
+		t.Errorf("Name() = %v, want reader.delete", span.Name())
+	}
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	if got := attrs["reader.endpoint"].AsString(); got != "delete" {
+		t.Errorf("reader.endpoint = %v, want delete", got)
+	}
+	if got := attrs["reader.document_id"].AsString(); got != "doc123" {
+		t.Errorf("reader.document_id = %v, want doc123", got)
+	}
+	if got := attrs["http.response.status_code"].AsInt64(); got != http.StatusNoContent {
+		t.Errorf("http.response.status_code = %v, want 204", got)
+	}
+	if span.Status().Code != codes.Unset {
+		t.Errorf("Status = %v, want Unset", span.Status().Code)
+	}
+
+	if spans[1].Status().Code != codes.Error {
+		t.Errorf("Status = %v, want Error", spans[1].Status().Code)
+	}
+}
@@ -0,0 +1,94 @@
+// Package prometheus provides a reader.Instrumentation that records Prometheus
+// metrics for the requests made by a Readwise Reader client.
+//
+// It is a separate module so that the library itself does not depend on the
+// Prometheus client.
+//
+//	inst, err := prometheus.New(prom.DefaultRegisterer)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	client, err := reader.NewClient(token, reader.WithInstrumentation(inst))
+package prometheus
+
+import (
+	"context"
+	"strconv"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	reader "github.com/tcnksm/go-readwise-reader"
+)
+
+// Instrumentation records Prometheus metrics for client requests
+type Instrumentation struct {
+	requests   *prom.CounterVec
+	duration   *prom.HistogramVec
+	retries    *prom.CounterVec
+	connection *prom.HistogramVec
+}
+
+// New creates an Instrumentation and registers its metrics with reg:
+//
+//   - reader_requests_total{endpoint, method, code}: requests by status code ("error" if no response was received)
+//   - reader_request_duration_seconds{endpoint, method}: request latency
+//   - reader_request_retries_total{endpoint}: retried requests
+//   - reader_connection_duration_seconds{phase}: connection timings reported by ObserveConnection
+func New(reg prom.Registerer) (*Instrumentation, error) {
+	i := &Instrumentation{
+		requests: prom.NewCounterVec(prom.CounterOpts{
+			Name: "reader_requests_total",
+			Help: "Number of Readwise Reader API requests by endpoint, method and status code.",
+		}, []string{"endpoint", "method", "code"}),
+		duration: prom.NewHistogramVec(prom.HistogramOpts{
+			Name:    "reader_request_duration_seconds",
+			Help:    "Latency of Readwise Reader API requests.",
+			Buckets: prom.DefBuckets,
+		}, []string{"endpoint", "method"}),
+		retries: prom.NewCounterVec(prom.CounterOpts{
+			Name: "reader_request_retries_total",
+			Help: "Number of retried Readwise Reader API requests by endpoint.",
+		}, []string{"endpoint"}),
+		connection: prom.NewHistogramVec(prom.HistogramOpts{
+			Name:    "reader_connection_duration_seconds",
+			Help:    "Connection timings of Readwise Reader API requests by phase.",
+			Buckets: prom.DefBuckets,
+		}, []string{"phase"}),
+	}
+
+	for _, c := range []prom.Collector{i.requests, i.duration, i.retries, i.connection} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+
+	return i, nil
+}
+
+// RequestStarted implements reader.Instrumentation
+func (i *Instrumentation) RequestStarted(ctx context.Context, info reader.RequestInfo) context.Context {
+	if info.Attempt > 1 {
+		i.retries.WithLabelValues(info.Endpoint).Inc()
+	}
+	return ctx
+}
+
+// RequestFinished implements reader.Instrumentation
+func (i *Instrumentation) RequestFinished(ctx context.Context, info reader.RequestInfo, result reader.RequestResult) {
+	code := "error"
+	if result.Err == nil {
+		code = strconv.Itoa(result.StatusCode)
+	}
+	i.requests.WithLabelValues(info.Endpoint, info.Method, code).Inc()
+	i.duration.WithLabelValues(info.Endpoint, info.Method).Observe(result.Duration.Seconds())
+}
+
+// ObserveConnection records connection timings. Pass it to
+// reader.NewTracingTransport to collect them.
+func (i *Instrumentation) ObserveConnection(ctx context.Context, trace reader.ConnectionTrace) {
+	if !trace.Reused {
+		i.connection.WithLabelValues("dns").Observe(trace.DNS.Seconds())
+		i.connection.WithLabelValues("connect").Observe(trace.Connect.Seconds())
+		i.connection.WithLabelValues("tls").Observe(trace.TLSHandshake.Seconds())
+	}
+	i.connection.WithLabelValues("first_byte").Observe(trace.TimeToFirstByte.Seconds())
+}
@@ -0,0 +1,77 @@
+package prometheus
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	reader "github.com/tcnksm/go-readwise-reader"
+)
+
+func TestInstrumentation(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	reg := prom.NewRegistry()
+	inst, err := New(reg)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	client, err := reader.NewClient("test-token",
+		reader.WithBaseURL(server.URL),
+		reader.WithInstrumentation(inst),
+		reader.WithHTTPClient(&http.Client{Transport: reader.NewTracingTransport(nil, inst.ObserveConnection)}),
+		reader.WithMaxRetries(1),
+	)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	if err := client.DeleteDocument(context.Background(), "doc123"); err != nil {
+		t.Fatalf("DeleteDocument() error = %v", err)
+	}
+
+	want := `
+# HELP reader_request_retries_total Number of retried Readwise Reader API requests by endpoint.
+# TYPE reader_request_retries_total counter
+reader_request_retries_total{endpoint="delete"} 1
+# HELP reader_requests_total Number of Readwise Reader API requests by endpoint, method and status code.
+# TYPE reader_requests_total counter
+reader_requests_total{code="204",endpoint="delete",method="DELETE"} 1
+reader_requests_total{code="429",endpoint="delete",method="DELETE"} 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want), "reader_requests_total", "reader_request_retries_total"); err != nil {
+		t.Error(err)
+	}
+
+	if got := testutil.CollectAndCount(inst.duration); got != 1 {
+		t.Errorf("duration series = %d, want 1", got)
+	}
+	if got := testutil.CollectAndCount(inst.connection); got != 4 {
+		t.Errorf("connection series = %d, want 4", got)
+	}
+}
+
+func TestNew_AlreadyRegistered(t *testing.T) {
+	reg := prom.NewRegistry()
+	if _, err := New(reg); err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if _, err := New(reg); err == nil {
+		t.Error("Expected error registering metrics twice, got none")
+	}
+}
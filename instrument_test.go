@@ -0,0 +1,197 @@
+package reader
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type recordingInstrumentation struct {
+	started    []RequestInfo
+	finished   []RequestResult
+	badContext bool
+}
+
+type instrumentationKey struct{}
+
+func (r *recordingInstrumentation) RequestStarted(ctx context.Context, info RequestInfo) context.Context {
+	r.started = append(r.started, info)
+	return context.WithValue(ctx, instrumentationKey{}, info.Attempt)
+}
+
+func (r *recordingInstrumentation) RequestFinished(ctx context.Context, info RequestInfo, result RequestResult) {
+	if ctx.Value(instrumentationKey{}) != info.Attempt {
+		r.badContext = true
+	}
+	r.finished = append(r.finished, result)
+}
+
+func TestClient_Instrumentation(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		if r.ContentLength == 0 {
+			t.Error("Expected request body on retry")
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id": "doc123", "url": "https://read.readwise.io/read/doc123"}`))
+	}))
+	defer server.Close()
+
+	inst1 := &recordingInstrumentation{}
+	inst2 := &recordingInstrumentation{}
+	c, err := NewClient("test-token",
+		WithBaseURL(server.URL),
+		WithInstrumentation(inst1),
+		WithInstrumentation(inst2),
+		WithMaxRetries(1),
+	)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	if _, err := c.UpdateDocument(context.Background(), "doc123", &UpdateDocumentRequest{Title: "t"}); err != nil {
+		t.Fatalf("UpdateDocument() error = %v", err)
+	}
+
+	for _, inst := range []*recordingInstrumentation{inst1, inst2} {
+		if inst.badContext {
+			t.Error("context from RequestStarted not passed to RequestFinished")
+		}
+		if len(inst.started) != 2 || len(inst.finished) != 2 {
+			t.Fatalf("started = %d, finished = %d, want 2 each", len(inst.started), len(inst.finished))
+		}
+		want := RequestInfo{Endpoint: "update", Method: http.MethodPatch, DocumentID: "doc123", Attempt: 2}
+		if inst.started[1] != want {
+			t.Errorf("started[1] = %+v, want %+v", inst.started[1], want)
+		}
+		if inst.finished[0].StatusCode != http.StatusTooManyRequests || inst.finished[1].StatusCode != http.StatusOK {
+			t.Errorf("status codes = %d, %d", inst.finished[0].StatusCode, inst.finished[1].StatusCode)
+		}
+		if inst.finished[0].RateLimit == nil || inst.finished[1].RateLimit != nil {
+			t.Errorf("rate limits = %+v, %+v, want only the first", inst.finished[0].RateLimit, inst.finished[1].RateLimit)
+		}
+	}
+}
+
+func TestClient_RetriesExhausted(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	c, err := NewClient("test-token", WithBaseURL(server.URL), WithMaxRetries(2))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	err = c.DeleteDocument(context.Background(), "doc123")
+	if apiErr, ok := err.(*APIError); !ok || apiErr.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("error = %v, want API error with status 429", err)
+	}
+	if requests != 3 {
+		t.Errorf("requests = %d, want 3", requests)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+	}{
+		{"seconds", "5", 5 * time.Second},
+		{"zero", "0", 0},
+		{"date", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat), time.Hour},
+		{"missing", "", 3 * time.Second},
+		{"invalid", "soon", 3 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}}
+			if tt.value != "" {
+				resp.Header.Set("Retry-After", tt.value)
+			}
+			// HTTP dates have a resolution of a second
+			if got := retryAfter(resp, 3); got > tt.want || got < tt.want-time.Second {
+				t.Errorf("retryAfter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewTracingTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	var traces []ConnectionTrace
+	transport := NewTracingTransport(nil, func(ctx context.Context, trace ConnectionTrace) {
+		traces = append(traces, trace)
+	})
+
+	c, err := NewClient("test-token",
+		WithBaseURL(server.URL),
+		WithHTTPClient(&http.Client{Transport: transport}),
+	)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := c.DeleteDocument(context.Background(), "doc123"); err != nil {
+			t.Fatalf("DeleteDocument() error = %v", err)
+		}
+	}
+
+	if len(traces) != 2 {
+		t.Fatalf("len(traces) = %d, want 2", len(traces))
+	}
+	if traces[0].Method != http.MethodDelete || traces[0].URL != server.URL+"/delete/doc123/" {
+		t.Errorf("traces[0] = %+v", traces[0])
+	}
+	if traces[0].Reused || traces[0].Connect == 0 {
+		t.Errorf("first request should open a new connection: %+v", traces[0])
+	}
+	if !traces[1].Reused {
+		t.Errorf("second request should reuse the connection: %+v", traces[1])
+	}
+	if traces[1].TimeToFirstByte == 0 {
+		t.Errorf("TimeToFirstByte is zero: %+v", traces[1])
+	}
+}
+
+func TestClient_WithRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	c, err := NewClient("test-token", WithBaseURL(server.URL), WithRateLimit(2, 100*time.Millisecond))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := c.DeleteDocument(context.Background(), "doc123"); err != nil {
+			t.Fatalf("DeleteDocument() error = %v", err)
+		}
+	}
+
+	// The second and third requests wait 50ms each
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("3 requests took %v, want at least 100ms", elapsed)
+	}
+}
@@ -0,0 +1,372 @@
+// Package journal keeps a local, append-only log of the changes made to
+// Readwise Reader documents so that they can be undone.
+//
+// Before a document is changed, its state is captured with Snapshot. Once the
+// change succeeded, the snapshots are recorded as one Entry. Undo restores the
+// documents of the most recent entries: updated documents are updated back to
+// their previous state and deleted documents are saved again from their URL,
+// HTML content and notes.
+package journal
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	reader "github.com/tcnksm/go-readwise-reader"
+)
+
+// Operation is the kind of change made to a document
+type Operation string
+
+const (
+	// OperationUpdate is a document update
+	OperationUpdate Operation = "update"
+
+	// OperationDelete is a document deletion
+	OperationDelete Operation = "delete"
+
+	// OperationCreate is a document creation, recorded when undoing a deletion
+	OperationCreate Operation = "create"
+)
+
+// Change is a change made to one document
+type Change struct {
+	// Operation is the kind of change
+	Operation Operation `json:"operation"`
+
+	// DocumentID is the ID of the changed document
+	DocumentID string `json:"document_id"`
+
+	// Before is the state of the document before the change. It is nil for
+	// created documents.
+	Before *reader.Document `json:"before,omitempty"`
+}
+
+// Entry is a set of changes made by one command, undone as a unit
+type Entry struct {
+	// ID is the sequence number of the entry, starting at 1. IDs increase
+	// but may have gaps, e.g. if the file was edited.
+	ID int `json:"id"`
+
+	// Time is when the changes were made
+	Time time.Time `json:"time"`
+
+	// Command describes what made the changes, e.g. "reader update"
+	Command string `json:"command"`
+
+	// Changes are the changes in the order they were made
+	Changes []Change `json:"changes"`
+
+	// Undoes is the ID of the entry this entry undid, if it is an undo
+	Undoes int `json:"undoes,omitempty"`
+
+	// UndoneBy is the ID of the entry that undid this entry, if any. It is
+	// not stored but filled in by Entries.
+	UndoneBy int `json:"-"`
+}
+
+const (
+	// lockTimeout is how long appending waits for another process holding
+	// the lock of the journal
+	lockTimeout = 10 * time.Second
+
+	// staleLock is the age after which the lock file of a process that died
+	// while holding it is removed
+	staleLock = time.Minute
+)
+
+// Journal is a journal stored as JSON lines in a file
+type Journal struct {
+	path string
+}
+
+// Open returns the journal stored at path. The file is created on the first
+// Record.
+func Open(path string) *Journal {
+	return &Journal{path: path}
+}
+
+// Snapshot fetches the current state of a document to record before changing
+// it. Set withContent before deleting the document, so that the HTML content
+// needed to save it again is included.
+func Snapshot(ctx context.Context, c reader.Client, documentID string, withContent bool) (*reader.Document, error) {
+	doc, err := reader.GetDocument(ctx, c, documentID, withContent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch document %s: %w", documentID, err)
+	}
+	return doc, nil
+}
+
+// Record appends an entry with the given changes and returns it
+func (j *Journal) Record(command string, changes []Change) (*Entry, error) {
+	return j.append(Entry{
+		Time:    time.Now(),
+		Command: command,
+		Changes: changes,
+	})
+}
+
+// append appends an entry with the next ID. The journal is locked while
+// doing so, as the CLI and the MCP server may append to it at the same time.
+func (j *Journal) append(entry Entry) (*Entry, error) {
+	unlock, err := j.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	return j.appendLocked(entry)
+}
+
+// appendLocked appends an entry with the next ID to the locked journal
+func (j *Journal) appendLocked(entry Entry) (*Entry, error) {
+	entries, err := j.Entries()
+	if err != nil {
+		return nil, err
+	}
+	entry.ID = 1
+	for _, e := range entries {
+		entry.ID = max(entry.ID, e.ID+1)
+	}
+
+	file, err := os.OpenFile(j.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open journal: %w", err)
+	}
+	defer file.Close()
+
+	if err := json.NewEncoder(file).Encode(entry); err != nil {
+		return nil, fmt.Errorf("failed to write journal: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write journal: %w", err)
+	}
+	return &entry, nil
+}
+
+// lock creates the lock file of the journal, waiting while another process
+// holds it, and returns a function removing it. The lock file is touched
+// while it is held, so that a long undo does not make it look stale.
+func (j *Journal) lock() (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(j.path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create journal directory: %w", err)
+	}
+	path := j.path + ".lock"
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			file.Close()
+			done := make(chan struct{})
+			go func() {
+				ticker := time.NewTicker(staleLock / 4)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-done:
+						return
+					case now := <-ticker.C:
+						os.Chtimes(path, now, now)
+					}
+				}
+			}()
+			return func() {
+				close(done)
+				os.Remove(path)
+			}, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, fmt.Errorf("failed to lock journal: %w", err)
+		}
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > staleLock {
+			os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("failed to lock journal: %s is held by another process", path)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// Entries returns all entries, oldest first
+func (j *Journal) Entries() ([]Entry, error) {
+	file, err := os.Open(j.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open journal: %w", err)
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	// Entries hold HTML content, so lines can be long
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("failed to read journal entry %d: %w", len(entries)+1, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+
+	index := make(map[int]int, len(entries))
+	for i, entry := range entries {
+		index[entry.ID] = i
+	}
+	for _, entry := range entries {
+		if i, ok := index[entry.Undoes]; ok && entry.Undoes > 0 {
+			entries[i].UndoneBy = entry.ID
+		}
+	}
+	return entries, nil
+}
+
+// Undo undoes the n most recent entries that have not been undone yet, newest
+// first, and records each undo as a new entry. Undo entries themselves are
+// never undone. It stops at the first change that cannot be restored and
+// returns the undo entries recorded so far. An entry that was partially
+// restored is recorded as undone, so that restored deletions are not
+// re-created twice. The journal is locked while undoing, so that concurrent
+// undos, e.g. by the CLI and the MCP server, do not restore an entry twice.
+func (j *Journal) Undo(ctx context.Context, c reader.Client, n int) ([]Entry, error) {
+	unlock, err := j.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	entries, err := j.Entries()
+	if err != nil {
+		return nil, err
+	}
+
+	var undone []Entry
+	for i := len(entries) - 1; i >= 0 && len(undone) < n; i-- {
+		entry := entries[i]
+		if entry.Undoes > 0 || entry.UndoneBy > 0 {
+			continue
+		}
+
+		changes, err := restore(ctx, c, entry)
+		if len(changes) > 0 || err == nil {
+			recorded, recordErr := j.appendLocked(Entry{
+				Time:    time.Now(),
+				Command: "undo",
+				Changes: changes,
+				Undoes:  entry.ID,
+			})
+			if recordErr != nil {
+				return undone, recordErr
+			}
+			undone = append(undone, *recorded)
+		}
+		if err != nil {
+			return undone, fmt.Errorf("failed to undo entry %d: %w", entry.ID, err)
+		}
+	}
+	return undone, nil
+}
+
+// restore restores the documents changed by an entry in reverse order and
+// returns the changes it made
+func restore(ctx context.Context, c reader.Client, entry Entry) ([]Change, error) {
+	var changes []Change
+	for i := len(entry.Changes) - 1; i >= 0; i-- {
+		change := entry.Changes[i]
+		switch change.Operation {
+		case OperationUpdate:
+			current, err := Snapshot(ctx, c, change.DocumentID, false)
+			if err != nil {
+				return changes, err
+			}
+			if _, err := c.UpdateDocument(ctx, change.DocumentID, RestoreRequest(change.Before)); err != nil {
+				return changes, fmt.Errorf("failed to restore document %s: %w", change.DocumentID, err)
+			}
+			changes = append(changes, Change{Operation: OperationUpdate, DocumentID: change.DocumentID, Before: current})
+
+		case OperationDelete:
+			url, req := RecreateRequest(change.Before)
+			resp, err := c.CreateDocument(ctx, url, req)
+			if err != nil {
+				return changes, fmt.Errorf("failed to re-create document %s: %w", change.DocumentID, err)
+			}
+			changes = append(changes, Change{Operation: OperationCreate, DocumentID: resp.ID})
+
+		case OperationCreate:
+			if err := c.DeleteDocument(ctx, change.DocumentID); err != nil {
+				return changes, fmt.Errorf("failed to delete document %s: %w", change.DocumentID, err)
+			}
+			changes = append(changes, Change{Operation: OperationDelete, DocumentID: change.DocumentID})
+
+		default:
+			return changes, fmt.Errorf("unknown operation: %s", change.Operation)
+		}
+	}
+	return changes, nil
+}
+
+// RestoreRequest returns the update that restores the location, title, tags
+// and metadata of a document to the given state. Author, summary, image URL,
+// tags and notes that were empty are cleared.
+func RestoreRequest(before *reader.Document) *reader.UpdateDocumentRequest {
+	seen := before.FirstOpenedAt != nil
+	req := &reader.UpdateDocumentRequest{
+		Title:    before.Title,
+		Author:   before.Author,
+		Summary:  before.Summary,
+		ImageURL: before.ImageURL,
+		Seen:     &seen,
+		Tags:     before.TagNames(),
+		Notes:    before.Notes,
+		Location: before.Location,
+		Category: before.Category,
+	}
+	if req.Author == "" {
+		req.Clear = append(req.Clear, reader.UpdateFieldAuthor)
+	}
+	if req.Summary == "" {
+		req.Clear = append(req.Clear, reader.UpdateFieldSummary)
+	}
+	if req.ImageURL == "" {
+		req.Clear = append(req.Clear, reader.UpdateFieldImageURL)
+	}
+	if len(req.Tags) == 0 {
+		req.Clear = append(req.Clear, reader.UpdateFieldTags)
+	}
+	if req.Notes == "" {
+		req.Clear = append(req.Clear, reader.UpdateFieldNotes)
+	}
+	return req
+}
+
+// RecreateRequest returns the URL and request that save a deleted document
+// again from its stored state
+func RecreateRequest(before *reader.Document) (string, *reader.CreateDocumentRequest) {
+	url := before.SourceURL
+	if url == "" {
+		url = before.URL
+	}
+	return url, &reader.CreateDocumentRequest{
+		HTML:     before.HTMLContent,
+		Title:    before.Title,
+		Author:   before.Author,
+		Summary:  before.Summary,
+		Tags:     before.TagNames(),
+		Location: before.Location,
+		Category: before.Category,
+		ImageURL: before.ImageURL,
+		Notes:    before.Notes,
+	}
+}
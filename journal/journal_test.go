@@ -0,0 +1,328 @@
+package journal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+
+	reader "github.com/tcnksm/go-readwise-reader"
+)
+
+// memoryClient is a reader.Client backed by a map of documents
+type memoryClient struct {
+	docs      map[string]reader.Document
+	created   int
+	updateErr error
+}
+
+func newMemoryClient(docs ...reader.Document) *memoryClient {
+	c := &memoryClient{docs: make(map[string]reader.Document)}
+	for _, doc := range docs {
+		c.docs[doc.ID] = doc
+	}
+	return c
+}
+
+func (c *memoryClient) ListDocuments(ctx context.Context, opts *reader.ListDocumentsOptions) (*reader.ListDocumentsResponse, error) {
+	doc, ok := c.docs[opts.ID]
+	if !ok {
+		return &reader.ListDocumentsResponse{}, nil
+	}
+	return &reader.ListDocumentsResponse{Count: 1, Results: []reader.Document{doc}}, nil
+}
+
+func (c *memoryClient) CreateDocument(ctx context.Context, url string, req *reader.CreateDocumentRequest) (*reader.CreateDocumentResponse, error) {
+	c.created++
+	id := fmt.Sprintf("created%d", c.created)
+	doc := reader.Document{
+		ID:          id,
+		URL:         url,
+		Title:       req.Title,
+		Notes:       req.Notes,
+		HTMLContent: req.HTML,
+		Location:    req.Location,
+		Tags:        tagMap(req.Tags),
+	}
+	c.docs[id] = doc
+	return &reader.CreateDocumentResponse{ID: id, URL: url}, nil
+}
+
+func (c *memoryClient) UpdateDocument(ctx context.Context, id string, req *reader.UpdateDocumentRequest) (*reader.UpdateDocumentResponse, error) {
+	if c.updateErr != nil {
+		return nil, c.updateErr
+	}
+	doc, ok := c.docs[id]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	if req.Title != "" {
+		doc.Title = req.Title
+	}
+	if req.Location != "" {
+		doc.Location = req.Location
+	}
+	if req.Tags != nil {
+		doc.Tags = tagMap(req.Tags)
+	}
+	if slices.Contains(req.Clear, reader.UpdateFieldTags) {
+		doc.Tags = nil
+	}
+	c.docs[id] = doc
+	return &reader.UpdateDocumentResponse{ID: id}, nil
+}
+
+func (c *memoryClient) DeleteDocument(ctx context.Context, id string) error {
+	delete(c.docs, id)
+	return nil
+}
+
+func tagMap(tags []string) map[string]interface{} {
+	m := make(map[string]interface{}, len(tags))
+	for _, tag := range tags {
+		m[tag] = map[string]interface{}{"name": tag}
+	}
+	return m
+}
+
+func TestJournal_UndoUpdates(t *testing.T) {
+	ctx := context.Background()
+	client := newMemoryClient(
+		reader.Document{ID: "a", Title: "A", Location: reader.LocationNew, Tags: tagMap([]string{"x"})},
+		reader.Document{ID: "b", Title: "B", Location: reader.LocationLater},
+	)
+	j := Open(filepath.Join(t.TempDir(), "journal.jsonl"))
+
+	// A bulk move recorded as one entry
+	var changes []Change
+	for _, id := range []string{"a", "b"} {
+		before, err := Snapshot(ctx, client, id, false)
+		if err != nil {
+			t.Fatalf("Snapshot() error = %v", err)
+		}
+		if _, err := client.UpdateDocument(ctx, id, &reader.UpdateDocumentRequest{Location: reader.LocationArchive, Tags: []string{"y"}}); err != nil {
+			t.Fatal(err)
+		}
+		changes = append(changes, Change{Operation: OperationUpdate, DocumentID: id, Before: before})
+	}
+	entry, err := j.Record("reader update", changes)
+	if err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if entry.ID != 1 {
+		t.Errorf("entry.ID = %d, want 1", entry.ID)
+	}
+
+	undone, err := j.Undo(ctx, client, 1)
+	if err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+	if len(undone) != 1 || undone[0].Undoes != 1 || len(undone[0].Changes) != 2 {
+		t.Fatalf("Undo() = %+v, want one undo entry with 2 changes", undone)
+	}
+
+	if doc := client.docs["a"]; doc.Location != reader.LocationNew || !slices.Equal(doc.TagNames(), []string{"x"}) {
+		t.Errorf("document a = %+v, want restored to new with tag x", doc)
+	}
+	if doc := client.docs["b"]; doc.Location != reader.LocationLater || len(doc.Tags) != 0 {
+		t.Errorf("document b = %+v, want restored to later without tags", doc)
+	}
+
+	entries, err := j.Entries()
+	if err != nil {
+		t.Fatalf("Entries() error = %v", err)
+	}
+	if len(entries) != 2 || entries[0].UndoneBy != 2 {
+		t.Errorf("Entries() = %+v, want entry 1 undone by entry 2", entries)
+	}
+
+	// Nothing left to undo: undo entries are never undone
+	undone, err = j.Undo(ctx, client, 1)
+	if err != nil || len(undone) != 0 {
+		t.Errorf("Undo() = %+v, %v, want nothing undone", undone, err)
+	}
+}
+
+func TestJournal_UndoDelete(t *testing.T) {
+	ctx := context.Background()
+	client := newMemoryClient(reader.Document{
+		ID:          "a",
+		URL:         "https://read.readwise.io/read/a",
+		SourceURL:   "https://example.com/a",
+		Title:       "A",
+		Notes:       "my notes",
+		HTMLContent: "<p>content</p>",
+		Location:    reader.LocationLater,
+	})
+	j := Open(filepath.Join(t.TempDir(), "journal.jsonl"))
+
+	before, err := Snapshot(ctx, client, "a", true)
+	if err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+	if err := client.DeleteDocument(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := j.Record("reader delete", []Change{{Operation: OperationDelete, DocumentID: "a", Before: before}}); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	undone, err := j.Undo(ctx, client, 5)
+	if err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+	if len(undone) != 1 || len(undone[0].Changes) != 1 || undone[0].Changes[0].Operation != OperationCreate {
+		t.Fatalf("Undo() = %+v, want one create", undone)
+	}
+
+	doc := client.docs[undone[0].Changes[0].DocumentID]
+	if doc.URL != "https://example.com/a" || doc.Title != "A" || doc.Notes != "my notes" || doc.HTMLContent != "<p>content</p>" || doc.Location != reader.LocationLater {
+		t.Errorf("re-created document = %+v", doc)
+	}
+}
+
+func TestJournal_UndoFailure(t *testing.T) {
+	ctx := context.Background()
+	client := newMemoryClient(reader.Document{ID: "a", Location: reader.LocationNew})
+	j := Open(filepath.Join(t.TempDir(), "journal.jsonl"))
+
+	before, _ := Snapshot(ctx, client, "a", false)
+	if _, err := j.Record("reader update", []Change{{Operation: OperationUpdate, DocumentID: "a", Before: before}}); err != nil {
+		t.Fatal(err)
+	}
+
+	client.updateErr = errors.New("boom")
+	if _, err := j.Undo(ctx, client, 1); err == nil {
+		t.Fatal("Expected error, got none")
+	}
+
+	// Nothing was restored, so the entry can still be undone
+	entries, _ := j.Entries()
+	if len(entries) != 1 || entries[0].UndoneBy != 0 {
+		t.Errorf("Entries() = %+v, want entry 1 not undone", entries)
+	}
+}
+
+func TestJournal_EntriesMissingFile(t *testing.T) {
+	entries, err := Open(filepath.Join(t.TempDir(), "missing.jsonl")).Entries()
+	if err != nil || entries != nil {
+		t.Errorf("Entries() = %v, %v, want nil, nil", entries, err)
+	}
+}
+
+func TestJournal_ConcurrentRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+
+	// Each writer opens the journal on its own, like separate processes
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := Open(path).Record(fmt.Sprintf("writer %d", i), nil); err != nil {
+				t.Errorf("Record() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	entries, err := Open(path).Entries()
+	if err != nil {
+		t.Fatalf("Entries() error = %v", err)
+	}
+	var ids, want []int
+	for i, entry := range entries {
+		ids = append(ids, entry.ID)
+		want = append(want, i+1)
+	}
+	slices.Sort(ids)
+	if len(ids) != 20 || !slices.Equal(ids, want) {
+		t.Errorf("IDs = %v, want 1 to 20", ids)
+	}
+}
+
+func TestJournal_ConcurrentUndo(t *testing.T) {
+	ctx := context.Background()
+	client := newMemoryClient()
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	before := &reader.Document{ID: "a", SourceURL: "https://example.com/a", Title: "A"}
+	if _, err := Open(path).Record("reader delete", []Change{{Operation: OperationDelete, DocumentID: "a", Before: before}}); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	// Each undo opens the journal on its own, like separate processes
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := Open(path).Undo(ctx, client, 1); err != nil {
+				t.Errorf("Undo() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if client.created != 1 {
+		t.Errorf("re-created the document %d times, want 1", client.created)
+	}
+	entries, err := Open(path).Entries()
+	if err != nil {
+		t.Fatalf("Entries() error = %v", err)
+	}
+	if len(entries) != 2 || entries[0].UndoneBy != 2 {
+		t.Errorf("entries = %+v, want the delete and one undo", entries)
+	}
+}
+
+func TestJournal_EntriesByID(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	// Entry 2 was removed by hand
+	data := `{"id":1,"command":"reader update"}
+{"id":3,"command":"reader move"}
+{"id":4,"command":"undo","undoes":3}
+{"id":5,"command":"undo","undoes":2}
+`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	j := Open(path)
+	entries, err := j.Entries()
+	if err != nil {
+		t.Fatalf("Entries() error = %v", err)
+	}
+	if entries[0].UndoneBy != 0 || entries[1].UndoneBy != 4 {
+		t.Errorf("UndoneBy = %d, %d, want 0, 4", entries[0].UndoneBy, entries[1].UndoneBy)
+	}
+
+	entry, err := j.Record("reader update", nil)
+	if err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if entry.ID != 6 {
+		t.Errorf("ID = %d, want 6", entry.ID)
+	}
+}
+
+func TestRestoreRequest(t *testing.T) {
+	before := &reader.Document{
+		Title:    "A",
+		Author:   "Jane Doe",
+		Location: reader.LocationLater,
+		Category: reader.CategoryArticle,
+	}
+	data, err := json.Marshal(RestoreRequest(before))
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	want := `{"author":"Jane Doe","category":"article","image_url":"","location":"later","notes":"","seen":false,"summary":"","tags":[],"title":"A"}`
+	if string(data) != want {
+		t.Errorf("RestoreRequest() = %s, want %s", data, want)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"sort"
 	"time"
 )
 
@@ -33,6 +34,7 @@ const (
 	CategoryTweet     Category = "tweet"
 	CategoryVideo     Category = "video"
 	CategoryHighlight Category = "highlight"
+	CategoryNote      Category = "note"
 )
 
 // ListDocumentsOptions holds options for listing documents
@@ -91,6 +93,9 @@ type Document struct {
 	// Notes is a top-level note of the document
 	Notes string `json:"notes"`
 
+	// Tags contains the document tags keyed by tag name
+	Tags map[string]interface{} `json:"tags"`
+
 	// Category is the document type
 	Category Category `json:"category"`
 
@@ -106,6 +111,9 @@ type Document struct {
 	// Summary of the document
 	Summary string `json:"summary"`
 
+	// Content is the highlighted text of a highlight, or the text of a note
+	Content string `json:"content"`
+
 	// HTMLContent is the HTML content of the document (only available if WithHTMLContent is true)
 	HTMLContent string `json:"html_content"`
 
@@ -121,6 +129,9 @@ type Document struct {
 	// SiteName is the name of the website
 	SiteName string `json:"site_name"`
 
+	// ImageURL is the URL of the cover image of the document
+	ImageURL string `json:"image_url"`
+
 	// FirstOpenedAt is when the document was first opened
 	FirstOpenedAt *time.Time `json:"first_opened_at"`
 
@@ -132,6 +143,82 @@ type Document struct {
 
 	// LastMovedAt is when the document was last moved between locations
 	LastMovedAt *time.Time `json:"last_moved_at"`
+
+	// PublishedDate is when the document was originally published
+	PublishedDate string `json:"published_date"`
+
+	// ReadingTime is the estimated reading time, e.g. "5 mins"
+	ReadingTime string `json:"reading_time"`
+
+	// ParentID is the ID of the document a highlight or note belongs to
+	ParentID string `json:"parent_id"`
+}
+
+// UnmarshalJSON decodes a document, parsing its times with ParseTime.
+// Unlike other locations and categories, those of documents are not
+// validated, as Readwise may add new ones.
+func (d *Document) UnmarshalJSON(data []byte) error {
+	// The shared fields shadow the fields of the document, which are
+	// embedded one level deeper
+	type document Document
+	type fields struct{ *document }
+	var shared documentFields
+	if err := json.Unmarshal(data, &struct {
+		fields
+		*documentFields
+	}{fields{(*document)(d)}, &shared}); err != nil {
+		return err
+	}
+	shared.set(&d.Location, &d.Category, &d.CreatedAt, &d.UpdatedAt, &d.FirstOpenedAt, &d.LastOpenedAt, &d.SavedAt, &d.LastMovedAt)
+	return nil
+}
+
+// documentFields are the fields shared by Document and
+// DocumentWebhookPayload that are decoded leniently: the location and
+// category as sent, and the times with ParseTime
+type documentFields struct {
+	Location      string        `json:"location"`
+	Category      string        `json:"category"`
+	CreatedAt     *flexibleTime `json:"created_at"`
+	UpdatedAt     *flexibleTime `json:"updated_at"`
+	FirstOpenedAt *flexibleTime `json:"first_opened_at"`
+	LastOpenedAt  *flexibleTime `json:"last_opened_at"`
+	SavedAt       *flexibleTime `json:"saved_at"`
+	LastMovedAt   *flexibleTime `json:"last_moved_at"`
+}
+
+// set sets the decoded fields to the given fields. Missing, null and empty
+// times are set to nil.
+func (f documentFields) set(location *Location, category *Category, createdAt, updatedAt, firstOpenedAt, lastOpenedAt, savedAt, lastMovedAt **time.Time) {
+	*location = Location(f.Location)
+	*category = Category(f.Category)
+	for _, t := range []struct {
+		src *flexibleTime
+		dst **time.Time
+	}{
+		{f.CreatedAt, createdAt},
+		{f.UpdatedAt, updatedAt},
+		{f.FirstOpenedAt, firstOpenedAt},
+		{f.LastOpenedAt, lastOpenedAt},
+		{f.SavedAt, savedAt},
+		{f.LastMovedAt, lastMovedAt},
+	} {
+		*t.dst = nil
+		if t.src != nil && !time.Time(*t.src).IsZero() {
+			parsed := time.Time(*t.src)
+			*t.dst = &parsed
+		}
+	}
+}
+
+// TagNames returns the names of the document tags in sorted order
+func (d Document) TagNames() []string {
+	names := make([]string, 0, len(d.Tags))
+	for name := range d.Tags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
 
 // ListDocuments retrieves documents from Readwise Reader
@@ -182,7 +269,7 @@ func (c *client) ListDocuments(ctx context.Context, opts *ListDocumentsOptions)
 	req.Header.Set("Accept", "application/json")
 
 	// Execute request
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req, "list", opts.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
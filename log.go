@@ -0,0 +1,65 @@
+package reader
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// redacted replaces the value of sensitive log attributes
+const redacted = "[REDACTED]"
+
+// sensitiveKeys are log attribute keys whose values are redacted by RedactAttr:
+// credentials, and document content that may be private or very large
+var sensitiveKeys = map[string]bool{
+	"authorization": true,
+	"token":         true,
+	"secret":        true,
+	"note":          true,
+	"notes":         true,
+	"html":          true,
+	"html_content":  true,
+	"content":       true,
+}
+
+// WithLogger logs a summary of every request and response to logger at debug
+// level, and rate limited retries at info level. The token is never logged.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *client) {
+		c.logger = logger
+	}
+}
+
+// log returns the logger of the client, discarding logs if there is none
+func (c *client) log() *slog.Logger {
+	if c.logger == nil {
+		return slog.New(slog.DiscardHandler)
+	}
+	return c.logger
+}
+
+// RedactAttr replaces the values of attributes holding credentials, notes or
+// HTML content with "[REDACTED]". It can be used as the ReplaceAttr function of
+// slog.HandlerOptions.
+func RedactAttr(groups []string, a slog.Attr) slog.Attr {
+	if sensitiveKeys[strings.ToLower(a.Key)] && a.Value.Kind() != slog.KindGroup {
+		return slog.String(a.Key, redacted)
+	}
+	return a
+}
+
+// logHeader logs HTTP headers with the token in the Authorization header redacted
+type logHeader http.Header
+
+func (h logHeader) LogValue() slog.Value {
+	attrs := make([]slog.Attr, 0, len(h))
+	for key, values := range h {
+		value := strings.Join(values, ", ")
+		if strings.EqualFold(key, "Authorization") {
+			scheme, _, _ := strings.Cut(value, " ")
+			value = scheme + " " + redacted
+		}
+		attrs = append(attrs, slog.String(key, value))
+	}
+	return slog.GroupValue(attrs...)
+}
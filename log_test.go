@@ -0,0 +1,66 @@
+package reader
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_WithLogger(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	c, err := NewClient("secret-token", WithBaseURL(server.URL), WithLogger(logger))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if err := c.DeleteDocument(context.Background(), "doc123"); err != nil {
+		t.Fatalf("DeleteDocument() error = %v", err)
+	}
+
+	out := buf.String()
+	if strings.Contains(out, "secret-token") {
+		t.Errorf("log contains the token: %s", out)
+	}
+	for _, want := range []string{
+		`msg="sending request"`,
+		`headers.Authorization="Token [REDACTED]"`,
+		`msg="received response"`,
+		"endpoint=delete",
+		"document_id=doc123",
+		"status=204",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log does not contain %s: %s", want, out)
+		}
+	}
+}
+
+func TestRedactAttr(t *testing.T) {
+	tests := []struct {
+		attr slog.Attr
+		want string
+	}{
+		{slog.String("notes", "private"), redacted},
+		{slog.String("note", "private"), redacted},
+		{slog.String("HTML", "<p>body</p>"), redacted},
+		{slog.String("token", "abc"), redacted},
+		{slog.Int("content", 42), redacted},
+		{slog.String("title", "Public"), "Public"},
+	}
+
+	for _, tt := range tests {
+		if got := RedactAttr(nil, tt.attr).Value.String(); got != tt.want {
+			t.Errorf("RedactAttr(%v) = %v, want %v", tt.attr, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+// Package markdown renders Readwise Reader documents and their highlights
+// as Markdown.
+//
+// Document exports the content of a document with its highlights marked
+// inline where they are found in the text, and Review renders highlights
+// grouped by document, e.g. for a daily highlights review.
+package markdown
+
+import (
+	"fmt"
+	"strings"
+
+	reader "github.com/tcnksm/go-readwise-reader"
+)
+
+// Group is a document and the highlights and notes that belong to it
+type Group struct {
+	Document   reader.Document   `json:"document"`
+	Highlights []reader.Document `json:"highlights"`
+}
+
+// Document renders a document with its HTML content as Markdown. Highlights
+// are marked with ==text== in the paragraph where their text is found, and
+// their notes follow the paragraph as quotes. Highlights that cannot be
+// placed, for example because they span paragraphs, and notes on the
+// document are listed at the end.
+func Document(doc reader.Document, highlights []reader.Document) string {
+	var b strings.Builder
+	writeHeader(&b, "#", doc)
+
+	placed := make([]bool, len(highlights))
+	for _, paragraph := range strings.Split(PlainText(doc.HTMLContent), "\n") {
+		if paragraph == "" {
+			continue
+		}
+		var notes []string
+		for i, h := range highlights {
+			text := highlightText(h)
+			if placed[i] || h.Category == reader.CategoryNote || text == "" {
+				continue
+			}
+			if marked, ok := mark(paragraph, text); ok {
+				paragraph = marked
+				placed[i] = true
+				if note := strings.TrimSpace(h.Notes); note != "" {
+					notes = append(notes, note)
+				}
+			}
+		}
+		b.WriteString(paragraph + "\n\n")
+		for _, note := range notes {
+			writeQuote(&b, "**Note:** "+note)
+		}
+	}
+
+	var rest []reader.Document
+	for i, h := range highlights {
+		if !placed[i] {
+			rest = append(rest, h)
+		}
+	}
+	if len(rest) > 0 {
+		b.WriteString("## Highlights\n\n")
+		writeHighlights(&b, rest)
+	}
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+// Review renders highlights grouped by document under a title
+func Review(title string, groups []Group) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	if len(groups) == 0 {
+		b.WriteString("No highlights.\n")
+		return b.String()
+	}
+	for _, g := range groups {
+		writeHeader(&b, "##", g.Document)
+		writeHighlights(&b, g.Highlights)
+	}
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+// writeHeader writes the title of a document with its author and link
+func writeHeader(b *strings.Builder, level string, doc reader.Document) {
+	title := doc.Title
+	if title == "" {
+		title = doc.URL
+	}
+	fmt.Fprintf(b, "%s %s\n\n", level, title)
+
+	var meta []string
+	if doc.Author != "" {
+		meta = append(meta, doc.Author)
+	}
+	if url := sourceURL(doc); url != "" {
+		meta = append(meta, fmt.Sprintf("[%s](%s)", url, url))
+	}
+	if len(meta) > 0 {
+		b.WriteString(strings.Join(meta, " · ") + "\n\n")
+	}
+}
+
+// writeHighlights writes highlights as quotes followed by their notes, and
+// notes as plain paragraphs
+func writeHighlights(b *strings.Builder, highlights []reader.Document) {
+	for _, h := range highlights {
+		text := highlightText(h)
+		if h.Category == reader.CategoryNote {
+			if text != "" {
+				fmt.Fprintf(b, "**Note:** %s\n\n", text)
+			}
+			continue
+		}
+		writeQuote(b, text)
+		if note := strings.TrimSpace(h.Notes); note != "" {
+			fmt.Fprintf(b, "**Note:** %s\n\n", note)
+		}
+	}
+}
+
+func writeQuote(b *strings.Builder, text string) {
+	for _, line := range strings.Split(text, "\n") {
+		b.WriteString(strings.TrimRight("> "+line, " ") + "\n")
+	}
+	b.WriteString("\n")
+}
+
+// highlightText returns the text of a highlight or note
+func highlightText(h reader.Document) string {
+	return strings.TrimSpace(h.Content)
+}
+
+// mark wraps the first occurrence of text in paragraph with ==, comparing
+// with whitespace collapsed since the content and highlights are formatted
+// differently
+func mark(paragraph, text string) (string, bool) {
+	text = strings.Join(strings.Fields(text), " ")
+	i := strings.Index(paragraph, text)
+	if i < 0 {
+		return paragraph, false
+	}
+	return paragraph[:i] + "==" + text + "==" + paragraph[i+len(text):], true
+}
+
+func sourceURL(doc reader.Document) string {
+	if doc.SourceURL != "" {
+		return doc.SourceURL
+	}
+	return doc.URL
+}
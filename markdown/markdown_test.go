@@ -0,0 +1,79 @@
+package markdown
+
+import (
+	"testing"
+
+	reader "github.com/tcnksm/go-readwise-reader"
+)
+
+func TestDocument(t *testing.T) {
+	doc := reader.Document{
+		Title:       "Deep Dive",
+		Author:      "Jane Doe",
+		SourceURL:   "https://example.com/deep-dive",
+		HTMLContent: "<h1>Deep Dive</h1><p>Go is a   language.</p><p>Interfaces are <em>implicit</em>. Channels are typed.</p><script>x()</script>",
+	}
+	highlights := []reader.Document{
+		{Category: reader.CategoryHighlight, Content: "Channels are typed.", Notes: "Nice"},
+		{Category: reader.CategoryHighlight, Content: "Go is a\nlanguage."},
+		{Category: reader.CategoryHighlight, Content: "Not in the text"},
+		{Category: reader.CategoryNote, Content: "Read again"},
+	}
+
+	want := `# Deep Dive
+
+Jane Doe · [https://example.com/deep-dive](https://example.com/deep-dive)
+
+Deep Dive
+
+==Go is a language.==
+
+Interfaces are implicit. ==Channels are typed.==
+
+> **Note:** Nice
+
+## Highlights
+
+> Not in the text
+
+**Note:** Read again
+`
+	if got := Document(doc, highlights); got != want {
+		t.Errorf("Document() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestReview(t *testing.T) {
+	groups := []Group{
+		{
+			Document:   reader.Document{Title: "Deep Dive", URL: "https://read.readwise.io/read/1"},
+			Highlights: []reader.Document{{Category: reader.CategoryHighlight, Content: "First\nSecond", Notes: "Why"}},
+		},
+	}
+
+	want := `# Highlights
+
+## Deep Dive
+
+[https://read.readwise.io/read/1](https://read.readwise.io/read/1)
+
+> First
+> Second
+
+**Note:** Why
+`
+	if got := Review("Highlights", groups); got != want {
+		t.Errorf("Review() =\n%s\nwant\n%s", got, want)
+	}
+
+	if got, want := Review("Highlights", nil), "# Highlights\n\nNo highlights.\n"; got != want {
+		t.Errorf("Review(nil) = %q, want %q", got, want)
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	got := PlainText("<p>Fish &amp; chips</p>\n<style>p {}</style><div>Two\n  lines</div>")
+	if want := "Fish & chips\n\nTwo lines"; got != want {
+		t.Errorf("PlainText() = %q, want %q", got, want)
+	}
+}
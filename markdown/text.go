@@ -0,0 +1,67 @@
+package markdown
+
+import (
+	"html"
+	"strings"
+)
+
+// blockTags are HTML elements that start a new line in PlainText
+var blockTags = map[string]bool{
+	"p": true, "div": true, "br": true, "li": true, "tr": true, "section": true, "article": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"blockquote": true, "pre": true, "ul": true, "ol": true, "table": true, "figure": true,
+}
+
+// PlainText converts HTML content to plain text, keeping paragraph breaks
+// and dropping scripts and styles. Each paragraph is on one line.
+func PlainText(s string) string {
+	var b strings.Builder
+	skip := ""
+	for len(s) > 0 {
+		i := strings.IndexByte(s, '<')
+		if i < 0 {
+			i = len(s)
+		}
+		if skip == "" {
+			// Line breaks in HTML source are spaces; only block tags break lines
+			b.WriteString(strings.ReplaceAll(s[:i], "\n", " "))
+		}
+		s = s[i:]
+		if s == "" {
+			break
+		}
+
+		end := strings.IndexByte(s, '>')
+		if end < 0 {
+			break
+		}
+		tag := strings.ToLower(strings.Trim(s[1:end], "/ "))
+		closing := strings.HasPrefix(s[1:end], "/")
+		if name, _, _ := strings.Cut(tag, " "); name != "" {
+			tag = name
+		}
+		s = s[end+1:]
+
+		switch {
+		case tag == "script" || tag == "style":
+			if closing {
+				skip = ""
+			} else {
+				skip = tag
+			}
+		case blockTags[tag] && skip == "":
+			b.WriteString("\n")
+		}
+	}
+
+	// Collapse whitespace within lines and blank lines between paragraphs
+	var lines []string
+	for _, line := range strings.Split(html.UnescapeString(b.String()), "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" && (len(lines) == 0 || lines[len(lines)-1] == "") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
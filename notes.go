@@ -0,0 +1,26 @@
+package reader
+
+import (
+	"strings"
+	"time"
+)
+
+// AppendNote returns notes with note added as a new paragraph, the way
+// MergeDuplicates joins the notes of duplicates
+func AppendNote(notes, note string) string {
+	notes = strings.TrimSpace(notes)
+	note = strings.TrimSpace(note)
+	switch {
+	case notes == "":
+		return note
+	case note == "":
+		return notes
+	}
+	return notes + "\n\n" + note
+}
+
+// TimestampNote prefixes note with the date and time it was written, for
+// keeping notes as a journal
+func TimestampNote(t time.Time, note string) string {
+	return t.Format("[2006-01-02 15:04] ") + strings.TrimSpace(note)
+}
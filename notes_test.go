@@ -0,0 +1,34 @@
+package reader
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAppendNote(t *testing.T) {
+	tests := []struct {
+		name  string
+		notes string
+		note  string
+		want  string
+	}{
+		{name: "empty notes", notes: "", note: "first", want: "first"},
+		{name: "new paragraph", notes: "first", note: "second", want: "first\n\nsecond"},
+		{name: "trims whitespace", notes: "first\n\n", note: "  second\n", want: "first\n\nsecond"},
+		{name: "empty note", notes: "first", note: " ", want: "first"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AppendNote(tt.notes, tt.note); got != tt.want {
+				t.Errorf("AppendNote(%q, %q) = %q, want %q", tt.notes, tt.note, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimestampNote(t *testing.T) {
+	now := time.Date(2025, 7, 20, 9, 5, 0, 0, time.UTC)
+	if got, want := TimestampNote(now, "read chapter 3\n"), "[2025-07-20 09:05] read chapter 3"; got != want {
+		t.Errorf("TimestampNote() = %q, want %q", got, want)
+	}
+}
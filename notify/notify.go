@@ -0,0 +1,320 @@
+// Package notify forwards Readwise Reader webhook events to chat and
+// notification services.
+//
+// A Config lists sinks: Slack, Discord and Mattermost incoming webhooks,
+// ntfy and Gotify servers, and email over SMTP. Each sink renders the
+// events it accepts with text/template templates executed on the
+// reader.DocumentWebhookPayload, e.g.
+//
+//	Finished: {{.Title}} ({{.WordCount}} words)
+//
+// Events found by reader.Watch are forwarded through their Payload.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	reader "github.com/tcnksm/go-readwise-reader"
+)
+
+// SinkType is the kind of service a sink sends to
+type SinkType string
+
+// Sink type constants
+const (
+	// SinkSlack posts to a Slack incoming webhook URL
+	SinkSlack SinkType = "slack"
+
+	// SinkDiscord posts to a Discord webhook URL
+	SinkDiscord SinkType = "discord"
+
+	// SinkMattermost posts to a Mattermost incoming webhook URL
+	SinkMattermost SinkType = "mattermost"
+
+	// SinkNtfy publishes to an ntfy topic URL, e.g. https://ntfy.sh/mytopic
+	SinkNtfy SinkType = "ntfy"
+
+	// SinkGotify sends to a Gotify server URL with an application token
+	SinkGotify SinkType = "gotify"
+
+	// SinkEmail sends email through an SMTP server
+	SinkEmail SinkType = "email"
+)
+
+const (
+	defaultTitle    = "{{.Title}}"
+	defaultTemplate = "{{.EventType}}: {{.Title}} {{.URL}}"
+)
+
+// SendTimeout bounds sending one message to a sink, so that a sink that
+// hangs does not block the events after it
+const SendTimeout = 30 * time.Second
+
+// SendAttempts is the number of times a message is sent to a sink before
+// its failure is returned. Attempts are spaced by doubling delays starting
+// at one second.
+const SendAttempts = 3
+
+// retryDelay is the delay before the second attempt to send a message
+var retryDelay = time.Second
+
+// defaultHTTPClient sends the requests of sinks if Config.HTTPClient is nil
+var defaultHTTPClient = &http.Client{Timeout: SendTimeout}
+
+// Config is a list of sinks
+type Config struct {
+	// Sinks receive every event that matches their filter
+	Sinks []Sink `json:"sinks" yaml:"sinks"`
+
+	// HTTPClient sends the requests of chat, ntfy and Gotify sinks. A client
+	// with a timeout of SendTimeout is used if nil.
+	HTTPClient *http.Client `json:"-" yaml:"-"`
+}
+
+// Sink is a service that events are sent to
+type Sink struct {
+	// Name identifies the sink in errors
+	Name string `json:"name" yaml:"name"`
+
+	// Type is the kind of service
+	Type SinkType `json:"type" yaml:"type"`
+
+	// URL is the webhook URL, the ntfy topic URL or the Gotify server URL.
+	// Environment variables such as $SLACK_WEBHOOK_URL are expanded.
+	URL string `json:"url,omitempty" yaml:"url,omitempty"`
+
+	// Token is the ntfy access token or the Gotify application token.
+	// Environment variables are expanded.
+	Token string `json:"token,omitempty" yaml:"token,omitempty"`
+
+	// Priority is the ntfy (1-5) or Gotify priority of messages
+	Priority int `json:"priority,omitempty" yaml:"priority,omitempty"`
+
+	// SMTP is the server that email is sent through
+	SMTP *SMTPConfig `json:"smtp,omitempty" yaml:"smtp,omitempty"`
+
+	// Title is the template of the message title, used by ntfy and Gotify
+	// and as the email subject. Default: {{.Title}}
+	Title string `json:"title,omitempty" yaml:"title,omitempty"`
+
+	// Template is the template of the message. Default:
+	// {{.EventType}}: {{.Title}} {{.URL}}
+	Template string `json:"template,omitempty" yaml:"template,omitempty"`
+
+	// When selects the events sent to the sink. All events are sent if it
+	// is empty.
+	When Filter `json:"when,omitempty" yaml:"when,omitempty"`
+
+	title   *template.Template
+	message *template.Template
+	sender  sender
+}
+
+// SMTPConfig is an SMTP server and the addresses of email
+type SMTPConfig struct {
+	// Addr is the host and port of the server, e.g. smtp.example.com:587.
+	// STARTTLS is used when the server supports it.
+	Addr string `json:"addr" yaml:"addr"`
+
+	// Username and Password authenticate with PLAIN auth if set.
+	// Environment variables in the password are expanded.
+	Username string `json:"username,omitempty" yaml:"username,omitempty"`
+	Password string `json:"password,omitempty" yaml:"password,omitempty"`
+
+	// From is the sender address
+	From string `json:"from" yaml:"from"`
+
+	// To are the recipient addresses
+	To []string `json:"to" yaml:"to"`
+}
+
+// Filter selects events. All non-empty fields must match for an event to
+// be selected, and each field matches if any of its values does.
+type Filter struct {
+	// EventTypes matches the event type
+	EventTypes []reader.WebhookEventType `json:"event_types,omitempty" yaml:"event_types,omitempty"`
+
+	// Categories matches the document category
+	Categories []reader.Category `json:"categories,omitempty" yaml:"categories,omitempty"`
+
+	// Tags matches documents that have one of these tags
+	Tags []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+// message is a rendered event
+type message struct {
+	Title string
+	Body  string
+	URL   string
+}
+
+// sender sends messages to a service
+type sender interface {
+	send(ctx context.Context, httpClient *http.Client, msg message) error
+}
+
+// Compile validates the sinks and parses their templates. It must be called
+// before events are sent.
+func (c *Config) Compile() error {
+	for i := range c.Sinks {
+		s := &c.Sinks[i]
+		if s.Name == "" {
+			return fmt.Errorf("sink %d: name is required", i+1)
+		}
+		if err := s.compile(); err != nil {
+			return fmt.Errorf("sink %q: %w", s.Name, err)
+		}
+	}
+	return nil
+}
+
+func (s *Sink) compile() error {
+	title, message := s.Title, s.Template
+	if title == "" {
+		title = defaultTitle
+	}
+	if message == "" {
+		message = defaultTemplate
+	}
+	var err error
+	if s.title, err = template.New("title").Parse(title); err != nil {
+		return fmt.Errorf("invalid title template: %w", err)
+	}
+	if s.message, err = template.New("template").Parse(message); err != nil {
+		return fmt.Errorf("invalid template: %w", err)
+	}
+
+	url := os.ExpandEnv(s.URL)
+	token := os.ExpandEnv(s.Token)
+	if s.Type != SinkEmail && url == "" {
+		return errors.New("url is required")
+	}
+	switch s.Type {
+	case SinkSlack, SinkMattermost:
+		s.sender = &chatSender{url: url, field: "text"}
+	case SinkDiscord:
+		s.sender = &chatSender{url: url, field: "content"}
+	case SinkNtfy:
+		s.sender = &ntfySender{url: url, token: token, priority: s.Priority}
+	case SinkGotify:
+		if token == "" {
+			return errors.New("token is required")
+		}
+		s.sender = &gotifySender{url: url, token: token, priority: s.Priority}
+	case SinkEmail:
+		if s.SMTP == nil || s.SMTP.Addr == "" || s.SMTP.From == "" || len(s.SMTP.To) == 0 {
+			return errors.New("smtp addr, from and to are required")
+		}
+		smtp := *s.SMTP
+		smtp.Password = os.ExpandEnv(smtp.Password)
+		s.sender = &emailSender{config: smtp}
+	default:
+		return fmt.Errorf("invalid type: %s. Valid values: slack, discord, mattermost, ntfy, gotify, email", s.Type)
+	}
+	return nil
+}
+
+// Match reports whether the filter selects an event
+func (f *Filter) Match(payload *reader.DocumentWebhookPayload) bool {
+	if len(f.EventTypes) > 0 && !slices.Contains(f.EventTypes, payload.EventType) {
+		return false
+	}
+	if len(f.Categories) > 0 && !slices.Contains(f.Categories, payload.Category) {
+		return false
+	}
+	if len(f.Tags) > 0 {
+		tags := payload.ToDocument().TagNames()
+		if !slices.ContainsFunc(f.Tags, func(tag string) bool { return slices.Contains(tags, tag) }) {
+			return false
+		}
+	}
+	return true
+}
+
+// Notify sends an event to every sink whose filter selects it. A sink that
+// fails is retried up to SendAttempts times without sending the event to the
+// others again. A failed sink does not stop the others, and the errors of all
+// failed sinks are returned. It has the signature of a
+// reader.WebhookHandlerFunc.
+func (c *Config) Notify(ctx context.Context, payload *reader.DocumentWebhookPayload) error {
+	var errs []error
+	for i := range c.Sinks {
+		s := &c.Sinks[i]
+		if !s.When.Match(payload) {
+			continue
+		}
+		if err := c.NotifySink(ctx, s, payload); err != nil {
+			errs = append(errs, fmt.Errorf("failed to notify %s: %w", s.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// NotifySink sends an event to s, one of the sinks of c, retrying like
+// Notify, whether or not its filter selects the event. It lets callers keep
+// track of the sinks notified of an event.
+func (c *Config) NotifySink(ctx context.Context, s *Sink, payload *reader.DocumentWebhookPayload) error {
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = defaultHTTPClient
+	}
+	return s.notify(ctx, httpClient, payload)
+}
+
+// notify sends an event to the sink, each attempt within SendTimeout
+func (s *Sink) notify(ctx context.Context, httpClient *http.Client, payload *reader.DocumentWebhookPayload) error {
+	if s.sender == nil {
+		return errors.New("sink is not compiled")
+	}
+	title, err := render(s.title, payload)
+	if err != nil {
+		return err
+	}
+	body, err := render(s.message, payload)
+	if err != nil {
+		return err
+	}
+	url := payload.SourceURL
+	if url == "" {
+		url = payload.URL
+	}
+	msg := message{Title: title, Body: body, URL: url}
+
+	delay := retryDelay
+	for attempt := 1; ; attempt++ {
+		err = s.send(ctx, httpClient, msg)
+		if err == nil || attempt == SendAttempts {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// send makes one attempt to send a message within SendTimeout
+func (s *Sink) send(ctx context.Context, httpClient *http.Client, msg message) error {
+	ctx, cancel := context.WithTimeout(ctx, SendTimeout)
+	defer cancel()
+	return s.sender.send(ctx, httpClient, msg)
+}
+
+func render(t *template.Template, payload *reader.DocumentWebhookPayload) (string, error) {
+	var b bytes.Buffer
+	if err := t.Execute(&b, payload); err != nil {
+		return "", fmt.Errorf("failed to render %s: %w", t.Name(), err)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
@@ -0,0 +1,387 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	reader "github.com/tcnksm/go-readwise-reader"
+)
+
+func testPayload() *reader.DocumentWebhookPayload {
+	return &reader.DocumentWebhookPayload{
+		EventType: reader.EventDocumentFinished,
+		ID:        "doc1",
+		URL:       "https://read.readwise.io/read/doc1",
+		SourceURL: "https://example.com/post",
+		Title:     "Deep Dive",
+		Category:  reader.CategoryArticle,
+		Tags:      map[string]interface{}{"go": map[string]interface{}{"name": "go"}},
+		WordCount: 1200,
+	}
+}
+
+// request is an HTTP request received by a stand-in server
+type request struct {
+	path   string
+	header http.Header
+	body   string
+}
+
+func newServer(t *testing.T, status int) (*httptest.Server, chan request) {
+	requests := make(chan request, SendAttempts)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- request{path: r.URL.RequestURI(), header: r.Header, body: string(body)}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, requests
+}
+
+// fastRetries shortens the delay between attempts for the test
+func fastRetries(t *testing.T) {
+	delay := retryDelay
+	retryDelay = time.Millisecond
+	t.Cleanup(func() { retryDelay = delay })
+}
+
+func compile(t *testing.T, sinks ...Sink) *Config {
+	t.Helper()
+	cfg := &Config{Sinks: sinks}
+	if err := cfg.Compile(); err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+	return cfg
+}
+
+func TestNotify_Chat(t *testing.T) {
+	tests := []struct {
+		sinkType SinkType
+		want     string
+	}{
+		{SinkSlack, `{"text":"Finished: Deep Dive (1200 words)"}`},
+		{SinkMattermost, `{"text":"Finished: Deep Dive (1200 words)"}`},
+		{SinkDiscord, `{"content":"Finished: Deep Dive (1200 words)"}`},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.sinkType), func(t *testing.T) {
+			server, requests := newServer(t, http.StatusOK)
+			cfg := compile(t, Sink{
+				Name:     "chat",
+				Type:     tt.sinkType,
+				URL:      server.URL + "/hooks/abc",
+				Template: "Finished: {{.Title}} ({{.WordCount}} words)",
+			})
+
+			if err := cfg.Notify(context.Background(), testPayload()); err != nil {
+				t.Fatalf("Notify() error = %v", err)
+			}
+			r := <-requests
+			if r.path != "/hooks/abc" || r.body != tt.want {
+				t.Errorf("request = %s %s, want /hooks/abc %s", r.path, r.body, tt.want)
+			}
+			if got := r.header.Get("Content-Type"); got != "application/json" {
+				t.Errorf("Content-Type = %q", got)
+			}
+		})
+	}
+}
+
+func TestNotify_Ntfy(t *testing.T) {
+	server, requests := newServer(t, http.StatusOK)
+	t.Setenv("NTFY_TOKEN", "tk_secret")
+	cfg := compile(t, Sink{Name: "phone", Type: SinkNtfy, URL: server.URL + "/reading", Token: "$NTFY_TOKEN", Priority: 4})
+
+	if err := cfg.Notify(context.Background(), testPayload()); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	r := <-requests
+	if r.path != "/reading" || r.body != "reader.document.finished: Deep Dive https://read.readwise.io/read/doc1" {
+		t.Errorf("request = %s %q", r.path, r.body)
+	}
+	for header, want := range map[string]string{
+		"Title":         "Deep Dive",
+		"Click":         "https://example.com/post",
+		"Priority":      "4",
+		"Authorization": "Bearer tk_secret",
+	} {
+		if got := r.header.Get(header); got != want {
+			t.Errorf("%s = %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestNotify_Gotify(t *testing.T) {
+	server, requests := newServer(t, http.StatusOK)
+	cfg := compile(t, Sink{Name: "gotify", Type: SinkGotify, URL: server.URL + "/", Token: "app-token", Title: "Read: {{.Title}}", Template: "{{.URL}}"})
+
+	if err := cfg.Notify(context.Background(), testPayload()); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	r := <-requests
+	if r.path != "/message" || r.header.Get("X-Gotify-Key") != "app-token" {
+		t.Errorf("request = %s key %q", r.path, r.header.Get("X-Gotify-Key"))
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal([]byte(r.body), &got); err != nil {
+		t.Fatalf("invalid body %q: %v", r.body, err)
+	}
+	if got["title"] != "Read: Deep Dive" || got["message"] != "https://read.readwise.io/read/doc1" {
+		t.Errorf("body = %v", got)
+	}
+}
+
+func TestNotify_Error(t *testing.T) {
+	fastRetries(t)
+	failing, failed := newServer(t, http.StatusServiceUnavailable)
+	ok, requests := newServer(t, http.StatusOK)
+	cfg := compile(t,
+		Sink{Name: "down", Type: SinkSlack, URL: failing.URL},
+		Sink{Name: "up", Type: SinkSlack, URL: ok.URL},
+	)
+
+	err := cfg.Notify(context.Background(), testPayload())
+	if err == nil || !strings.Contains(err.Error(), "failed to notify down: unexpected status 503") {
+		t.Errorf("Notify() error = %v, want status 503 from down", err)
+	}
+	select {
+	case <-requests:
+	default:
+		t.Error("a failed sink stopped the others")
+	}
+	if len(failed) != SendAttempts {
+		t.Errorf("failed sink got %d requests, want %d", len(failed), SendAttempts)
+	}
+}
+
+func TestNotify_Retry(t *testing.T) {
+	fastRetries(t)
+	var attempts atomic.Int32
+	flaky := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	t.Cleanup(flaky.Close)
+	ok, requests := newServer(t, http.StatusOK)
+	cfg := compile(t,
+		Sink{Name: "flaky", Type: SinkSlack, URL: flaky.URL},
+		Sink{Name: "up", Type: SinkSlack, URL: ok.URL},
+	)
+
+	if err := cfg.Notify(context.Background(), testPayload()); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if got := attempts.Load(); got != 2 {
+		t.Errorf("flaky sink got %d requests, want 2", got)
+	}
+	if len(requests) != 1 {
+		t.Errorf("up sink got %d requests, want 1", len(requests))
+	}
+}
+
+func TestNotify_HTTPClient(t *testing.T) {
+	fastRetries(t)
+	release := make(chan struct{})
+	hanging := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	t.Cleanup(hanging.Close)
+	t.Cleanup(func() { close(release) })
+	ok, requests := newServer(t, http.StatusOK)
+	cfg := compile(t,
+		Sink{Name: "hanging", Type: SinkSlack, URL: hanging.URL},
+		Sink{Name: "up", Type: SinkSlack, URL: ok.URL},
+	)
+	cfg.HTTPClient = &http.Client{Timeout: 50 * time.Millisecond}
+
+	err := cfg.Notify(context.Background(), testPayload())
+	if err == nil || !strings.Contains(err.Error(), "failed to notify hanging") {
+		t.Errorf("Notify() error = %v, want a timeout from hanging", err)
+	}
+	select {
+	case <-requests:
+	default:
+		t.Error("a hanging sink stopped the others")
+	}
+
+	if defaultHTTPClient.Timeout == 0 {
+		t.Error("default HTTP client has no timeout")
+	}
+}
+
+func TestNotify_Filter(t *testing.T) {
+	server, requests := newServer(t, http.StatusOK)
+	cfg := compile(t, Sink{
+		Name: "finished go articles",
+		Type: SinkSlack,
+		URL:  server.URL,
+		When: Filter{
+			EventTypes: []reader.WebhookEventType{reader.EventDocumentFinished},
+			Categories: []reader.Category{reader.CategoryArticle, reader.CategoryPDF},
+			Tags:       []string{"rust", "go"},
+		},
+	})
+
+	for _, tt := range []struct {
+		name   string
+		modify func(p *reader.DocumentWebhookPayload)
+		sent   bool
+	}{
+		{"match", func(p *reader.DocumentWebhookPayload) {}, true},
+		{"event type", func(p *reader.DocumentWebhookPayload) { p.EventType = reader.EventDocumentArchived }, false},
+		{"category", func(p *reader.DocumentWebhookPayload) { p.Category = reader.CategoryRSS }, false},
+		{"tags", func(p *reader.DocumentWebhookPayload) { p.Tags = nil }, false},
+	} {
+		p := testPayload()
+		tt.modify(p)
+		if err := cfg.Notify(context.Background(), p); err != nil {
+			t.Fatalf("%s: Notify() error = %v", tt.name, err)
+		}
+		select {
+		case <-requests:
+			if !tt.sent {
+				t.Errorf("%s: event sent, want filtered", tt.name)
+			}
+		default:
+			if tt.sent {
+				t.Errorf("%s: event filtered, want sent", tt.name)
+			}
+		}
+	}
+}
+
+func TestNotify_Email(t *testing.T) {
+	addr, messages := newSMTPServer(t)
+	cfg := compile(t, Sink{
+		Name:     "email",
+		Type:     SinkEmail,
+		Title:    "Finished: {{.Title}}",
+		Template: "{{.WordCount}} words",
+		SMTP: &SMTPConfig{
+			Addr: addr,
+			From: "reader@example.com",
+			To:   []string{"me@example.com", "team@example.com"},
+		},
+	})
+
+	if err := cfg.Notify(context.Background(), testPayload()); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	m := <-messages
+	if m.from != "<reader@example.com>" || strings.Join(m.to, ",") != "<me@example.com>,<team@example.com>" {
+		t.Errorf("envelope = from %s to %v", m.from, m.to)
+	}
+	for _, want := range []string{
+		"Subject: Finished: Deep Dive\r\n",
+		"To: me@example.com, team@example.com\r\n",
+		"\r\n\r\n1200 words\r\n\r\nhttps://example.com/post\r\n",
+	} {
+		if !strings.Contains(m.data, want) {
+			t.Errorf("email does not contain %q:\n%s", want, m.data)
+		}
+	}
+}
+
+func TestConfig_Compile(t *testing.T) {
+	tests := []struct {
+		name string
+		sink Sink
+		want string
+	}{
+		{"missing name", Sink{Type: SinkSlack, URL: "http://x"}, "sink 1: name is required"},
+		{"invalid type", Sink{Name: "s", Type: "pager", URL: "http://x"}, `sink "s": invalid type: pager`},
+		{"missing url", Sink{Name: "s", Type: SinkDiscord}, `sink "s": url is required`},
+		{"missing token", Sink{Name: "s", Type: SinkGotify, URL: "http://x"}, `sink "s": token is required`},
+		{"missing smtp", Sink{Name: "s", Type: SinkEmail}, `sink "s": smtp addr, from and to are required`},
+		{"invalid template", Sink{Name: "s", Type: SinkSlack, URL: "http://x", Template: "{{.Title"}, `sink "s": invalid template`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Sinks: []Sink{tt.sink}}
+			err := cfg.Compile()
+			if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("Compile() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// smtpMessage is an email received by the stand-in SMTP server
+type smtpMessage struct {
+	from string
+	to   []string
+	data string
+}
+
+// newSMTPServer starts a minimal SMTP server that accepts one email per
+// connection
+func newSMTPServer(t *testing.T) (string, chan smtpMessage) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	messages := make(chan smtpMessage, 1)
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go serveSMTP(conn, messages)
+		}
+	}()
+	return l.Addr().String(), messages
+}
+
+func serveSMTP(conn net.Conn, messages chan smtpMessage) {
+	defer conn.Close()
+	tp := textproto.NewConn(conn)
+	tp.PrintfLine("220 localhost ESMTP")
+
+	var m smtpMessage
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			tp.PrintfLine("250 localhost")
+		case "MAIL":
+			m.from = strings.TrimPrefix(arg, "FROM:")
+			tp.PrintfLine("250 OK")
+		case "RCPT":
+			m.to = append(m.to, strings.TrimPrefix(arg, "TO:"))
+			tp.PrintfLine("250 OK")
+		case "DATA":
+			tp.PrintfLine("354 Go ahead")
+			data, err := io.ReadAll(bufio.NewReader(tp.DotReader()))
+			if err != nil {
+				return
+			}
+			// DotReader converts line endings to \n
+			m.data = strings.ReplaceAll(string(data), "\n", "\r\n")
+			tp.PrintfLine("250 OK")
+			messages <- m
+		case "QUIT":
+			tp.PrintfLine("221 Bye")
+			return
+		default:
+			tp.PrintfLine("502 Not implemented")
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// chatSender posts a message to a Slack, Discord or Mattermost incoming
+// webhook as a JSON object with the message in field
+type chatSender struct {
+	url   string
+	field string
+}
+
+func (s *chatSender) send(ctx context.Context, httpClient *http.Client, msg message) error {
+	body, err := json.Marshal(map[string]string{s.field: msg.Body})
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return do(httpClient, req)
+}
+
+// ntfySender publishes a message to an ntfy topic
+type ntfySender struct {
+	url      string
+	token    string
+	priority int
+}
+
+func (s *ntfySender) send(ctx context.Context, httpClient *http.Client, msg message) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, strings.NewReader(msg.Body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if msg.Title != "" {
+		// Headers must be ASCII, so non-ASCII titles are encoded
+		req.Header.Set("Title", mime.QEncoding.Encode("utf-8", msg.Title))
+	}
+	if msg.URL != "" {
+		req.Header.Set("Click", msg.URL)
+	}
+	if s.priority > 0 {
+		req.Header.Set("Priority", strconv.Itoa(s.priority))
+	}
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+	return do(httpClient, req)
+}
+
+// gotifySender sends a message to a Gotify server
+type gotifySender struct {
+	url      string
+	token    string
+	priority int
+}
+
+func (s *gotifySender) send(ctx context.Context, httpClient *http.Client, msg message) error {
+	body, err := json.Marshal(struct {
+		Title    string `json:"title,omitempty"`
+		Message  string `json:"message"`
+		Priority int    `json:"priority,omitempty"`
+	}{msg.Title, msg.Body, s.priority})
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+	url := strings.TrimRight(s.url, "/") + "/message"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Gotify-Key", s.token)
+	return do(httpClient, req)
+}
+
+// do sends a request and fails on non-2xx responses
+func do(httpClient *http.Client, req *http.Request) error {
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// emailSender sends a message as a plain text email
+type emailSender struct {
+	config SMTPConfig
+}
+
+func (s *emailSender) send(ctx context.Context, _ *http.Client, msg message) error {
+	host, _, err := net.SplitHostPort(s.config.Addr)
+	if err != nil {
+		return fmt.Errorf("invalid smtp addr: %w", err)
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", s.config.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to smtp server: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to connect to smtp server: %w", err)
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return fmt.Errorf("failed to start tls: %w", err)
+		}
+	}
+	if s.config.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", s.config.Username, s.config.Password, host)); err != nil {
+			return fmt.Errorf("failed to authenticate: %w", err)
+		}
+	}
+	if err := c.Mail(s.config.From); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	for _, to := range s.config.To {
+		if err := c.Rcpt(to); err != nil {
+			return fmt.Errorf("failed to send email to %s: %w", to, err)
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	if _, err := w.Write(s.email(msg)); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return c.Quit()
+}
+
+// email formats a message as an email with headers
+func (s *emailSender) email(msg message) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", s.config.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(s.config.To, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Title))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	body := msg.Body
+	if msg.URL != "" && !strings.Contains(body, msg.URL) {
+		body += "\n\n" + msg.URL
+	}
+	b.WriteString(strings.ReplaceAll(body, "\n", "\r\n") + "\r\n")
+	return b.Bytes()
+}
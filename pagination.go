@@ -0,0 +1,47 @@
+package reader
+
+import (
+	"context"
+)
+
+// ListAllDocuments retrieves every document matching opts by following
+// NextPageCursor until the last page. The PageCursor in opts is used as the
+// starting point and opts itself is not modified.
+func ListAllDocuments(ctx context.Context, c Client, opts *ListDocumentsOptions) ([]Document, error) {
+	return FindDocuments(ctx, c, opts, nil, 0)
+}
+
+// FindDocuments lists documents matching opts page by page and returns those
+// for which match returns true. Unlike filtering a single page, this finds
+// matches on later pages too. It stops once limit documents are found, or
+// reads every page when limit is 0.
+func FindDocuments(ctx context.Context, c Client, opts *ListDocumentsOptions, match func(Document) bool, limit int) ([]Document, error) {
+	var o ListDocumentsOptions
+	if opts != nil {
+		o = *opts
+	}
+
+	var documents []Document
+	for {
+		resp, err := c.ListDocuments(ctx, &o)
+		if err != nil {
+			return nil, err
+		}
+		for _, doc := range resp.Results {
+			if match != nil && !match(doc) {
+				continue
+			}
+			documents = append(documents, doc)
+			if limit > 0 && len(documents) == limit {
+				return documents, nil
+			}
+		}
+
+		if resp.NextPageCursor == nil || *resp.NextPageCursor == "" {
+			break
+		}
+		o.PageCursor = *resp.NextPageCursor
+	}
+
+	return documents, nil
+}
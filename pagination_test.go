@@ -0,0 +1,140 @@
+package reader
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestListAllDocuments(t *testing.T) {
+	pages := map[string]ListDocumentsResponse{
+		"": {
+			Count:          3,
+			NextPageCursor: stringPtr("page2"),
+			Results:        []Document{{ID: "doc1"}, {ID: "doc2"}},
+		},
+		"page2": {
+			Count:   3,
+			Results: []Document{{ID: "doc3"}},
+		},
+	}
+
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if got := r.URL.Query().Get("location"); got != "later" {
+			t.Errorf("location = %v, want later", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(pages[r.URL.Query().Get("pageCursor")])
+	}))
+	defer server.Close()
+
+	c := &client{
+		baseURL:    server.URL,
+		token:      "test-token",
+		httpClient: &http.Client{},
+	}
+
+	opts := &ListDocumentsOptions{Location: LocationLater}
+	docs, err := ListAllDocuments(context.Background(), c, opts)
+	if err != nil {
+		t.Fatalf("ListAllDocuments() error = %v", err)
+	}
+
+	if requests != 2 {
+		t.Errorf("requests = %d, want 2", requests)
+	}
+	if len(docs) != 3 {
+		t.Fatalf("len(docs) = %d, want 3", len(docs))
+	}
+	for i, want := range []string{"doc1", "doc2", "doc3"} {
+		if docs[i].ID != want {
+			t.Errorf("docs[%d].ID = %v, want %v", i, docs[i].ID, want)
+		}
+	}
+	if opts.PageCursor != "" {
+		t.Errorf("opts.PageCursor was modified to %v", opts.PageCursor)
+	}
+}
+
+func TestListAllDocuments_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	c := &client{
+		baseURL:    server.URL,
+		token:      "test-token",
+		httpClient: &http.Client{},
+	}
+
+	if _, err := ListAllDocuments(context.Background(), c, nil); err == nil {
+		t.Error("Expected error, got none")
+	}
+}
+
+func TestFindDocuments(t *testing.T) {
+	pages := map[string]ListDocumentsResponse{
+		"": {
+			NextPageCursor: stringPtr("page2"),
+			Results:        []Document{{ID: "doc1", ReadingProgressPercent: 100}, {ID: "doc2"}},
+		},
+		"page2": {
+			NextPageCursor: stringPtr("page3"),
+			Results:        []Document{{ID: "doc3", ReadingProgressPercent: 100}, {ID: "doc4", ReadingProgressPercent: 100}},
+		},
+		"page3": {
+			Results: []Document{{ID: "doc5", ReadingProgressPercent: 100}},
+		},
+	}
+
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(pages[r.URL.Query().Get("pageCursor")])
+	}))
+	defer server.Close()
+
+	c := &client{
+		baseURL:    server.URL,
+		token:      "test-token",
+		httpClient: &http.Client{},
+	}
+
+	finished := func(doc Document) bool { return doc.Progress() == ProgressFinished }
+
+	tests := []struct {
+		name         string
+		limit        int
+		wantIDs      []string
+		wantRequests int
+	}{
+		{name: "all pages", limit: 0, wantIDs: []string{"doc1", "doc3", "doc4", "doc5"}, wantRequests: 3},
+		{name: "stops at limit", limit: 2, wantIDs: []string{"doc1", "doc3"}, wantRequests: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests = 0
+			docs, err := FindDocuments(context.Background(), c, nil, finished, tt.limit)
+			if err != nil {
+				t.Fatalf("FindDocuments() error = %v", err)
+			}
+			if requests != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", requests, tt.wantRequests)
+			}
+			if len(docs) != len(tt.wantIDs) {
+				t.Fatalf("len(docs) = %d, want %d", len(docs), len(tt.wantIDs))
+			}
+			for i, want := range tt.wantIDs {
+				if docs[i].ID != want {
+					t.Errorf("docs[%d].ID = %v, want %v", i, docs[i].ID, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package reader
+
+import (
+	"context"
+	"fmt"
+)
+
+// Progress is a range of reading progress used to filter documents
+type Progress string
+
+// Progress constants for document filtering
+const (
+	// ProgressUnstarted matches documents with no reading progress
+	ProgressUnstarted Progress = "unstarted"
+
+	// ProgressInProgress matches documents read partway
+	ProgressInProgress Progress = "in_progress"
+
+	// ProgressFinished matches documents read to the end
+	ProgressFinished Progress = "finished"
+)
+
+// ParseProgress parses a progress range name. "in-progress" is accepted as
+// well as "in_progress".
+func ParseProgress(s string) (Progress, error) {
+	switch s {
+	case "unstarted":
+		return ProgressUnstarted, nil
+	case "in_progress", "in-progress":
+		return ProgressInProgress, nil
+	case "finished":
+		return ProgressFinished, nil
+	}
+	return "", fmt.Errorf("invalid progress: %s. Valid values: unstarted, in_progress, finished", s)
+}
+
+// Progress returns the progress range of the document
+func (d Document) Progress() Progress {
+	switch {
+	case d.ReadingProgressPercent <= 0:
+		return ProgressUnstarted
+	case d.ReadingProgressPercent >= 100:
+		return ProgressFinished
+	default:
+		return ProgressInProgress
+	}
+}
+
+// Seen reports whether the document has been opened
+func (d Document) Seen() bool {
+	return d.FirstOpenedAt != nil
+}
+
+// MarkSeen marks a document as opened
+func MarkSeen(ctx context.Context, c Client, documentID string) (*UpdateDocumentResponse, error) {
+	seen := true
+	return c.UpdateDocument(ctx, documentID, &UpdateDocumentRequest{Seen: &seen})
+}
+
+// MarkUnseen marks a document as not opened, clearing its opened times
+func MarkUnseen(ctx context.Context, c Client, documentID string) (*UpdateDocumentResponse, error) {
+	seen := false
+	return c.UpdateDocument(ctx, documentID, &UpdateDocumentRequest{Seen: &seen})
+}
@@ -0,0 +1,95 @@
+package reader
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDocumentProgress(t *testing.T) {
+	tests := []struct {
+		progress float64
+		want     Progress
+	}{
+		{progress: 0, want: ProgressUnstarted},
+		{progress: 0.5, want: ProgressInProgress},
+		{progress: 99.9, want: ProgressInProgress},
+		{progress: 100, want: ProgressFinished},
+	}
+	for _, tt := range tests {
+		doc := Document{ReadingProgressPercent: tt.progress}
+		if got := doc.Progress(); got != tt.want {
+			t.Errorf("Progress() with %v%% = %v, want %v", tt.progress, got, tt.want)
+		}
+	}
+}
+
+func TestParseProgress(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Progress
+		wantErr bool
+	}{
+		{input: "unstarted", want: ProgressUnstarted},
+		{input: "in_progress", want: ProgressInProgress},
+		{input: "in-progress", want: ProgressInProgress},
+		{input: "finished", want: ProgressFinished},
+		{input: "done", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseProgress(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseProgress(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseProgress(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestMarkSeen(t *testing.T) {
+	tests := []struct {
+		name     string
+		mark     func(context.Context, Client, string) (*UpdateDocumentResponse, error)
+		wantSeen bool
+	}{
+		{name: "seen", mark: MarkSeen, wantSeen: true},
+		{name: "unseen", mark: MarkUnseen, wantSeen: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/update/doc123/" {
+					t.Errorf("path = %v, want /update/doc123/", r.URL.Path)
+				}
+				var body map[string]interface{}
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Fatalf("failed to decode body: %v", err)
+				}
+				if len(body) != 1 || body["seen"] != tt.wantSeen {
+					t.Errorf("body = %v, want only seen=%v", body, tt.wantSeen)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(UpdateDocumentResponse{ID: "doc123"})
+			}))
+			defer server.Close()
+
+			c := &client{
+				baseURL:    server.URL,
+				token:      "test-token",
+				httpClient: &http.Client{},
+			}
+
+			resp, err := tt.mark(context.Background(), c, "doc123")
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if resp.ID != "doc123" {
+				t.Errorf("ID = %v, want doc123", resp.ID)
+			}
+		})
+	}
+}
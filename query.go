@@ -0,0 +1,407 @@
+package reader
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Query is a document filter parsed from a query string such as
+//
+//	location=feed and saved_at < -90d
+//
+// A query is a list of comparisons joined by "and". Each comparison is a
+// field, an operator and a value:
+//
+//   - Text fields (id, title, author, site_name, url, category, location, tag)
+//     support = and !=, and ~ for a regular expression match. The tag field
+//     matches documents having or not having the tag. Locations and
+//     categories compared with = and != must be valid, see ParseLocation and
+//     ParseCategory.
+//   - Number fields (word_count, reading_progress) support =, !=, <, <=, > and >=.
+//   - Time fields (created_at, updated_at, saved_at, first_opened_at,
+//     last_opened_at, last_moved_at) support <, <=, > and >=. Values are
+//     dates (2006-01-02), RFC 3339 times, or durations relative to now such
+//     as -90d, -12h or -2w. Documents without the time never match.
+//   - The progress field supports = and != with unstarted, in_progress or
+//     finished.
+//   - The seen field supports = and != with true or false.
+//
+// Values containing spaces can be quoted with double or single quotes.
+type Query struct {
+	clauses []queryClause
+}
+
+type queryClause struct {
+	field string
+	op    string
+	value string
+
+	number float64
+	time   time.Time
+	re     *regexp.Regexp
+	bool   bool
+}
+
+type queryFieldKind int
+
+const (
+	queryText queryFieldKind = iota
+	queryNumber
+	queryTime
+	queryBool
+)
+
+// queryFields are the fields a query can compare and their kinds
+var queryFields = map[string]queryFieldKind{
+	"id":               queryText,
+	"title":            queryText,
+	"author":           queryText,
+	"site_name":        queryText,
+	"url":              queryText,
+	"category":         queryText,
+	"location":         queryText,
+	"tag":              queryText,
+	"word_count":       queryNumber,
+	"reading_progress": queryNumber,
+	"created_at":       queryTime,
+	"updated_at":       queryTime,
+	"saved_at":         queryTime,
+	"first_opened_at":  queryTime,
+	"last_opened_at":   queryTime,
+	"last_moved_at":    queryTime,
+	"progress":         queryText,
+	"seen":             queryBool,
+}
+
+// queryOperators are the operators in the order they are matched, longest first
+var queryOperators = []string{"!=", "<=", ">=", "=", "<", ">", "~"}
+
+// ParseQuery parses a query string. Relative times are resolved against now.
+func ParseQuery(s string, now time.Time) (*Query, error) {
+	p := &queryParser{input: s}
+	q := &Query{}
+	for {
+		p.skipSpace()
+		if p.done() {
+			if len(q.clauses) == 0 {
+				return nil, fmt.Errorf("empty query")
+			}
+			return q, nil
+		}
+		if len(q.clauses) > 0 {
+			if word := p.word(); !strings.EqualFold(word, "and") {
+				return nil, fmt.Errorf("expected \"and\" at %q", word)
+			}
+			p.skipSpace()
+		}
+
+		clause, err := p.clause(now)
+		if err != nil {
+			return nil, err
+		}
+		q.clauses = append(q.clauses, clause)
+	}
+}
+
+type queryParser struct {
+	input string
+	pos   int
+}
+
+func (p *queryParser) done() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *queryParser) skipSpace() {
+	for !p.done() && (p.input[p.pos] == ' ' || p.input[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// word reads a run of letters, digits and underscores
+func (p *queryParser) word() string {
+	start := p.pos
+	for !p.done() {
+		c := p.input[p.pos]
+		if !(c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			break
+		}
+		p.pos++
+	}
+	return p.input[start:p.pos]
+}
+
+// value reads a quoted string or a run of non-space characters
+func (p *queryParser) value() (string, error) {
+	if p.done() {
+		return "", fmt.Errorf("missing value at end of query")
+	}
+	if quote := p.input[p.pos]; quote == '"' || quote == '\'' {
+		end := strings.IndexByte(p.input[p.pos+1:], quote)
+		if end < 0 {
+			return "", fmt.Errorf("unterminated quote in query")
+		}
+		v := p.input[p.pos+1 : p.pos+1+end]
+		p.pos += end + 2
+		return v, nil
+	}
+	start := p.pos
+	for !p.done() && p.input[p.pos] != ' ' && p.input[p.pos] != '\t' {
+		p.pos++
+	}
+	return p.input[start:p.pos], nil
+}
+
+func (p *queryParser) clause(now time.Time) (queryClause, error) {
+	c := queryClause{field: strings.ToLower(p.word())}
+	kind, ok := queryFields[c.field]
+	if !ok {
+		return c, fmt.Errorf("unknown query field: %q", c.field)
+	}
+
+	p.skipSpace()
+	for _, op := range queryOperators {
+		if strings.HasPrefix(p.input[p.pos:], op) {
+			c.op = op
+			p.pos += len(op)
+			break
+		}
+	}
+	if c.op == "" {
+		return c, fmt.Errorf("missing operator after %s", c.field)
+	}
+
+	p.skipSpace()
+	var err error
+	if c.value, err = p.value(); err != nil {
+		return c, err
+	}
+
+	invalidOp := func() (queryClause, error) {
+		return c, fmt.Errorf("operator %s is not supported for %s", c.op, c.field)
+	}
+	switch kind {
+	case queryText:
+		if c.field == "progress" {
+			if c.op != "=" && c.op != "!=" {
+				return invalidOp()
+			}
+			progress, err := ParseProgress(c.value)
+			if err != nil {
+				return c, err
+			}
+			c.value = string(progress)
+			break
+		}
+		if c.op == "=" || c.op == "!=" {
+			switch c.field {
+			case "location":
+				if _, err := ParseLocation(c.value); err != nil {
+					return c, err
+				}
+			case "category":
+				if _, err := ParseCategory(c.value); err != nil {
+					return c, err
+				}
+			}
+		}
+		switch c.op {
+		case "=", "!=":
+		case "~":
+			if c.re, err = regexp.Compile(c.value); err != nil {
+				return c, fmt.Errorf("invalid pattern for %s: %w", c.field, err)
+			}
+		default:
+			return invalidOp()
+		}
+	case queryNumber:
+		if c.op == "~" {
+			return invalidOp()
+		}
+		if c.number, err = strconv.ParseFloat(c.value, 64); err != nil {
+			return c, fmt.Errorf("invalid number for %s: %s", c.field, c.value)
+		}
+	case queryTime:
+		if c.op == "=" || c.op == "!=" || c.op == "~" {
+			return invalidOp()
+		}
+		if c.time, err = parseQueryTime(c.value, now); err != nil {
+			return c, fmt.Errorf("invalid time for %s: %w", c.field, err)
+		}
+	case queryBool:
+		if c.op != "=" && c.op != "!=" {
+			return invalidOp()
+		}
+		if c.bool, err = strconv.ParseBool(c.value); err != nil {
+			return c, fmt.Errorf("invalid boolean for %s: %s", c.field, c.value)
+		}
+	}
+	return c, nil
+}
+
+// parseQueryTime parses a date, an RFC 3339 time, or a duration relative to
+// now in s, m, h, d or w such as -90d
+func parseQueryTime(s string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, now.Location()); err == nil {
+		return t, nil
+	}
+
+	units := map[byte]time.Duration{
+		's': time.Second,
+		'm': time.Minute,
+		'h': time.Hour,
+		'd': 24 * time.Hour,
+		'w': 7 * 24 * time.Hour,
+	}
+	if len(s) >= 2 && (s[0] == '-' || s[0] == '+') {
+		if unit, ok := units[s[len(s)-1]]; ok {
+			if n, err := strconv.Atoi(s[:len(s)-1]); err == nil {
+				return now.Add(time.Duration(n) * unit), nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q is not a date, an RFC 3339 time or a relative duration like -90d", s)
+}
+
+// Match reports whether a document matches every comparison of the query
+func (q *Query) Match(doc Document) bool {
+	for _, c := range q.clauses {
+		if !c.match(doc) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *queryClause) match(doc Document) bool {
+	switch c.field {
+	case "tag":
+		has := slices.Contains(doc.TagNames(), c.value)
+		if c.re != nil {
+			has = slices.ContainsFunc(doc.TagNames(), c.re.MatchString)
+		}
+		return has == (c.op != "!=")
+	case "id":
+		return c.matchText(doc.ID)
+	case "title":
+		return c.matchText(doc.Title)
+	case "author":
+		return c.matchText(doc.Author)
+	case "site_name":
+		return c.matchText(doc.SiteName)
+	case "url":
+		if c.op == "!=" {
+			return doc.URL != c.value && doc.SourceURL != c.value
+		}
+		return c.matchText(doc.URL) || c.matchText(doc.SourceURL)
+	case "category":
+		return c.matchText(string(doc.Category))
+	case "location":
+		return c.matchText(string(doc.Location))
+	case "word_count":
+		return c.matchNumber(float64(doc.WordCount))
+	case "reading_progress":
+		return c.matchNumber(doc.ReadingProgressPercent)
+	case "created_at":
+		return c.matchTime(doc.CreatedAt)
+	case "updated_at":
+		return c.matchTime(doc.UpdatedAt)
+	case "saved_at":
+		return c.matchTime(doc.SavedAt)
+	case "first_opened_at":
+		return c.matchTime(doc.FirstOpenedAt)
+	case "last_opened_at":
+		return c.matchTime(doc.LastOpenedAt)
+	case "last_moved_at":
+		return c.matchTime(doc.LastMovedAt)
+	case "progress":
+		return c.matchText(string(doc.Progress()))
+	case "seen":
+		return doc.Seen() == (c.bool == (c.op == "="))
+	}
+	return false
+}
+
+func (c *queryClause) matchText(v string) bool {
+	switch c.op {
+	case "=":
+		return v == c.value
+	case "!=":
+		return v != c.value
+	case "~":
+		return c.re.MatchString(v)
+	}
+	return false
+}
+
+func (c *queryClause) matchNumber(v float64) bool {
+	switch c.op {
+	case "=":
+		return v == c.number
+	case "!=":
+		return v != c.number
+	case "<":
+		return v < c.number
+	case "<=":
+		return v <= c.number
+	case ">":
+		return v > c.number
+	case ">=":
+		return v >= c.number
+	}
+	return false
+}
+
+func (c *queryClause) matchTime(t *time.Time) bool {
+	if t == nil {
+		return false
+	}
+	switch c.op {
+	case "<":
+		return t.Before(c.time)
+	case "<=":
+		return !t.After(c.time)
+	case ">":
+		return t.After(c.time)
+	case ">=":
+		return !t.Before(c.time)
+	}
+	return false
+}
+
+// ListOptions returns the options that let the API do as much of the
+// filtering as it supports: location, category, tag and a lower bound on
+// updated_at. Documents listed with them must still be checked with Match.
+func (q *Query) ListOptions() *ListDocumentsOptions {
+	opts := &ListDocumentsOptions{}
+	for _, c := range q.clauses {
+		if c.op != "=" && !(c.field == "updated_at" && (c.op == ">" || c.op == ">=")) {
+			continue
+		}
+		switch c.field {
+		case "location":
+			opts.Location = Location(c.value)
+		case "category":
+			opts.Category = Category(c.value)
+		case "tag":
+			opts.Tag = c.value
+		case "updated_at":
+			// The API filters strictly after UpdatedAfter, so back off for >=
+			after := c.time
+			if c.op == ">=" {
+				after = after.Add(-time.Second)
+			}
+			if opts.UpdatedAfter == nil || after.After(*opts.UpdatedAfter) {
+				opts.UpdatedAfter = &after
+			}
+		}
+	}
+	return opts
+}
@@ -0,0 +1,132 @@
+package reader
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseQuery(t *testing.T) {
+	now := time.Date(2025, 7, 20, 12, 0, 0, 0, time.UTC)
+	old := now.AddDate(0, 0, -100)
+	recent := now.AddDate(0, 0, -10)
+	opened := now.AddDate(0, 0, -1)
+
+	feedOld := Document{
+		ID:        "1",
+		Title:     "Weekly Newsletter #42",
+		URL:       "https://read.readwise.io/read/1",
+		SourceURL: "https://example.com/newsletter/42",
+		Location:  LocationFeed,
+		Category:  CategoryEmail,
+		SavedAt:   &old,
+		WordCount: 800,
+		Tags:      map[string]interface{}{"news": map[string]interface{}{"name": "news"}},
+	}
+	laterRecent := Document{
+		ID:                     "2",
+		Title:                  "Deep Dive",
+		Author:                 "Jane Doe",
+		SiteName:               "Example Blog",
+		Location:               LocationLater,
+		Category:               CategoryArticle,
+		SavedAt:                &recent,
+		FirstOpenedAt:          &opened,
+		WordCount:              4000,
+		ReadingProgressPercent: 50,
+	}
+
+	tests := []struct {
+		query     string
+		wantFeed  bool
+		wantLater bool
+	}{
+		{query: "location=feed and saved_at < -90d", wantFeed: true},
+		{query: "location = later", wantLater: true},
+		{query: "location != later", wantFeed: true},
+		{query: "saved_at >= -30d", wantLater: true},
+		{query: "saved_at > 2025-01-01", wantFeed: true, wantLater: true},
+		{query: "saved_at < 2025-05-01T00:00:00Z", wantFeed: true},
+		{query: "word_count > 1000", wantLater: true},
+		{query: "word_count <= 800", wantFeed: true},
+		{query: "reading_progress >= 50", wantLater: true},
+		{query: "title ~ '(?i)newsletter'", wantFeed: true},
+		{query: `author = "Jane Doe"`, wantLater: true},
+		{query: "url ~ example.com/newsletter", wantFeed: true},
+		{query: "tag = news", wantFeed: true},
+		{query: "tag != news", wantLater: true},
+		{query: "progress = in_progress", wantLater: true},
+		{query: "progress != in-progress", wantFeed: true},
+		{query: "seen = true", wantLater: true},
+		{query: "seen = false", wantFeed: true},
+		{query: "first_opened_at > -2d", wantLater: true},
+		{query: "category=email AND word_count < 1000", wantFeed: true},
+		{query: "location=feed and location=later"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, err := ParseQuery(tt.query, now)
+			if err != nil {
+				t.Fatalf("ParseQuery() error = %v", err)
+			}
+			if got := q.Match(feedOld); got != tt.wantFeed {
+				t.Errorf("Match(feed document) = %v, want %v", got, tt.wantFeed)
+			}
+			if got := q.Match(laterRecent); got != tt.wantLater {
+				t.Errorf("Match(later document) = %v, want %v", got, tt.wantLater)
+			}
+		})
+	}
+}
+
+func TestParseQuery_Errors(t *testing.T) {
+	for _, query := range []string{
+		"",
+		"color = red",
+		"location feed",
+		"location =",
+		"location = feed or location = later",
+		"saved_at = -90d",
+		"saved_at < yesterday",
+		"word_count > many",
+		"word_count ~ 10",
+		"title ~ '('",
+		"title = 'unterminated",
+		"seen = maybe",
+		"progress = halfway",
+		"progress ~ started",
+		"location > feed",
+		"location = inbox",
+		"location != trash",
+		"category = artcle",
+		"category != podcast",
+	} {
+		t.Run(query, func(t *testing.T) {
+			if _, err := ParseQuery(query, time.Now()); err == nil {
+				t.Error("Expected error, got none")
+			}
+		})
+	}
+}
+
+func TestQuery_ListOptions(t *testing.T) {
+	now := time.Date(2025, 7, 20, 12, 0, 0, 0, time.UTC)
+	q, err := ParseQuery("location=feed and category=rss and tag=news and updated_at > -1d and saved_at < -90d", now)
+	if err != nil {
+		t.Fatalf("ParseQuery() error = %v", err)
+	}
+
+	opts := q.ListOptions()
+	if opts.Location != LocationFeed || opts.Category != CategoryRSS || opts.Tag != "news" {
+		t.Errorf("ListOptions() = %+v", opts)
+	}
+	if want := now.AddDate(0, 0, -1); opts.UpdatedAfter == nil || !opts.UpdatedAfter.Equal(want) {
+		t.Errorf("UpdatedAfter = %v, want %v", opts.UpdatedAfter, want)
+	}
+
+	// Negated filters cannot be pushed down
+	q, _ = ParseQuery("location != feed", now)
+	if opts := q.ListOptions(); opts.Location != "" {
+		t.Errorf("Location = %v, want empty", opts.Location)
+	}
+}
@@ -0,0 +1,74 @@
+package reader
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimit is the rate limit state reported by the headers of an API
+// response. Fields are nil or zero when the response does not report them.
+type RateLimit struct {
+	// Limit is the number of requests allowed in the current window, from
+	// the X-RateLimit-Limit or RateLimit-Limit header
+	Limit *int
+
+	// Remaining is the number of requests left in the current window, from
+	// the X-RateLimit-Remaining or RateLimit-Remaining header
+	Remaining *int
+
+	// Reset is when the current window ends, from the X-RateLimit-Reset or
+	// RateLimit-Reset header, either in seconds or as a Unix time
+	Reset time.Time
+
+	// RetryAfter is how long to wait before retrying a rate limited request,
+	// from the Retry-After header in seconds or as an HTTP date
+	RetryAfter *time.Duration
+}
+
+// ParseRateLimit reads the rate limit headers of a response received at now.
+// It returns false if the response has none.
+func ParseRateLimit(header http.Header, now time.Time) (RateLimit, bool) {
+	var rl RateLimit
+	found := false
+	if n, ok := headerInt(header, "X-RateLimit-Limit", "RateLimit-Limit"); ok {
+		rl.Limit = &n
+		found = true
+	}
+	if n, ok := headerInt(header, "X-RateLimit-Remaining", "RateLimit-Remaining"); ok {
+		rl.Remaining = &n
+		found = true
+	}
+	if n, ok := headerInt(header, "X-RateLimit-Reset", "RateLimit-Reset"); ok {
+		// Small values are seconds until the reset, large ones a Unix time
+		if n > 1e9 {
+			rl.Reset = time.Unix(int64(n), 0)
+		} else {
+			rl.Reset = now.Add(time.Duration(n) * time.Second)
+		}
+		found = true
+	}
+	if value := header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+			wait := time.Duration(seconds) * time.Second
+			rl.RetryAfter = &wait
+			found = true
+		} else if at, err := http.ParseTime(value); err == nil {
+			wait := max(at.Sub(now), 0)
+			rl.RetryAfter = &wait
+			found = true
+		}
+	}
+	return rl, found
+}
+
+// headerInt returns the first of the given headers that holds a
+// non-negative integer
+func headerInt(header http.Header, keys ...string) (int, bool) {
+	for _, key := range keys {
+		if n, err := strconv.Atoi(header.Get(key)); err == nil && n >= 0 {
+			return n, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,102 @@
+package reader
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	now := time.Date(2025, 9, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		header        http.Header
+		wantOK        bool
+		wantLimit     int
+		wantRemaining int
+		wantReset     time.Time
+		wantRetry     time.Duration
+	}{
+		{
+			name:   "none",
+			header: http.Header{},
+		},
+		{
+			name: "seconds until reset",
+			header: http.Header{
+				"X-Ratelimit-Limit":     {"20"},
+				"X-Ratelimit-Remaining": {"7"},
+				"X-Ratelimit-Reset":     {"30"},
+			},
+			wantOK:        true,
+			wantLimit:     20,
+			wantRemaining: 7,
+			wantReset:     now.Add(30 * time.Second),
+		},
+		{
+			name: "unix reset",
+			header: http.Header{
+				"Ratelimit-Remaining": {"0"},
+				"Ratelimit-Reset":     {"1756720860"},
+			},
+			wantOK:    true,
+			wantLimit: -1,
+			wantReset: time.Unix(1756720860, 0),
+		},
+		{
+			name:          "retry after seconds",
+			header:        http.Header{"Retry-After": {"12"}},
+			wantOK:        true,
+			wantLimit:     -1,
+			wantRemaining: -1,
+			wantRetry:     12 * time.Second,
+		},
+		{
+			name:          "retry after date",
+			header:        http.Header{"Retry-After": {now.Add(time.Minute).Format(http.TimeFormat)}},
+			wantOK:        true,
+			wantLimit:     -1,
+			wantRemaining: -1,
+			wantRetry:     time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rl, ok := ParseRateLimit(tt.header, now)
+			if ok != tt.wantOK {
+				t.Fatalf("ParseRateLimit() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if got := intOr(rl.Limit, -1); got != tt.wantLimit {
+				t.Errorf("Limit = %d, want %d", got, tt.wantLimit)
+			}
+			if got := intOr(rl.Remaining, -1); got != tt.wantRemaining {
+				t.Errorf("Remaining = %d, want %d", got, tt.wantRemaining)
+			}
+			if !rl.Reset.Equal(tt.wantReset) {
+				t.Errorf("Reset = %v, want %v", rl.Reset, tt.wantReset)
+			}
+			if got := durationOr(rl.RetryAfter, 0); got != tt.wantRetry {
+				t.Errorf("RetryAfter = %v, want %v", got, tt.wantRetry)
+			}
+		})
+	}
+}
+
+func intOr(n *int, fallback int) int {
+	if n == nil {
+		return fallback
+	}
+	return *n
+}
+
+func durationOr(d *time.Duration, fallback time.Duration) time.Duration {
+	if d == nil {
+		return fallback
+	}
+	return *d
+}
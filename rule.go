@@ -0,0 +1,268 @@
+package reader
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RuleSet is an ordered list of triage rules
+type RuleSet struct {
+	// Rules are evaluated in order
+	Rules []Rule `json:"rules" yaml:"rules"`
+}
+
+// Rule is a declarative triage rule: when a document matches the condition,
+// the action is applied to it
+type Rule struct {
+	// Name identifies the rule in dry-run output and the audit log
+	Name string `json:"name" yaml:"name"`
+
+	// When is the condition a document must match for the rule to fire
+	When RuleCondition `json:"when" yaml:"when"`
+
+	// Then is the change applied to matching documents
+	Then RuleAction `json:"then" yaml:"then"`
+}
+
+// RuleCondition is the condition part of a rule. All non-empty fields must
+// match for the condition to match. String patterns are regular expressions.
+type RuleCondition struct {
+	// EventType matches the webhook event type. Rules with an event type never
+	// match documents fetched by polling.
+	EventType WebhookEventType `json:"event_type,omitempty" yaml:"event_type,omitempty"`
+
+	// Category matches the document category
+	Category Category `json:"category,omitempty" yaml:"category,omitempty"`
+
+	// Location matches the document location
+	Location Location `json:"location,omitempty" yaml:"location,omitempty"`
+
+	// Tag matches documents that have this tag
+	Tag string `json:"tag,omitempty" yaml:"tag,omitempty"`
+
+	// SiteName is a pattern matched against the site name
+	SiteName string `json:"site_name,omitempty" yaml:"site_name,omitempty"`
+
+	// Author is a pattern matched against the author
+	Author string `json:"author,omitempty" yaml:"author,omitempty"`
+
+	// Title is a pattern matched against the title
+	Title string `json:"title,omitempty" yaml:"title,omitempty"`
+
+	// TitleContains matches titles containing this text
+	TitleContains string `json:"title_contains,omitempty" yaml:"title_contains,omitempty"`
+
+	// URL is a pattern matched against the URL and the source URL
+	URL string `json:"url,omitempty" yaml:"url,omitempty"`
+
+	// WordCountLT matches documents with fewer words than this
+	WordCountLT int `json:"word_count_lt,omitempty" yaml:"word_count_lt,omitempty"`
+
+	// WordCountGT matches documents with more words than this
+	WordCountGT int `json:"word_count_gt,omitempty" yaml:"word_count_gt,omitempty"`
+
+	siteName *regexp.Regexp
+	author   *regexp.Regexp
+	title    *regexp.Regexp
+	url      *regexp.Regexp
+}
+
+// RuleAction is the action part of a rule
+type RuleAction struct {
+	// Location moves the document to this location
+	Location Location `json:"location,omitempty" yaml:"location,omitempty"`
+
+	// AddTags adds these tags to the document
+	AddTags []string `json:"add_tags,omitempty" yaml:"add_tags,omitempty"`
+
+	// Seen marks the document as seen or unseen
+	Seen *bool `json:"seen,omitempty" yaml:"seen,omitempty"`
+}
+
+// RuleMatch is a rule that fired on a document together with the update it produces
+type RuleMatch struct {
+	// Rule is the name of the rule that fired
+	Rule string `json:"rule"`
+
+	// DocumentID is the ID of the matched document
+	DocumentID string `json:"document_id"`
+
+	// Title is the title of the matched document
+	Title string `json:"title"`
+
+	// Update is the change the rule makes to the document
+	Update *UpdateDocumentRequest `json:"update"`
+}
+
+// RuleResult is the outcome of applying a rule match, as recorded in an audit log
+type RuleResult struct {
+	RuleMatch
+
+	// Time is when the match was applied
+	Time time.Time `json:"time"`
+
+	// DryRun is true if the update was not sent
+	DryRun bool `json:"dry_run"`
+
+	// Error is the error returned by the API, if any
+	Error string `json:"error,omitempty"`
+}
+
+// Compile validates the rules and compiles their patterns. It must be called
+// before the rule set is evaluated.
+func (rs *RuleSet) Compile() error {
+	for i := range rs.Rules {
+		r := &rs.Rules[i]
+		if r.Name == "" {
+			return fmt.Errorf("rule %d: name is required", i+1)
+		}
+		if r.Then.Location == "" && len(r.Then.AddTags) == 0 && r.Then.Seen == nil {
+			return fmt.Errorf("rule %q: action is empty", r.Name)
+		}
+
+		var err error
+		for _, p := range []struct {
+			field   string
+			pattern string
+			re      **regexp.Regexp
+		}{
+			{"site_name", r.When.SiteName, &r.When.siteName},
+			{"author", r.When.Author, &r.When.author},
+			{"title", r.When.Title, &r.When.title},
+			{"url", r.When.URL, &r.When.url},
+		} {
+			if p.pattern == "" {
+				continue
+			}
+			if *p.re, err = regexp.Compile(p.pattern); err != nil {
+				return fmt.Errorf("rule %q: invalid %s pattern: %w", r.Name, p.field, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Evaluate returns the rules that fire on a document fetched by polling.
+// Rules are evaluated in order against the document as changed by the rules
+// before them, and rules that would not change anything are skipped.
+func (rs *RuleSet) Evaluate(doc Document) []RuleMatch {
+	return rs.evaluate(doc, "")
+}
+
+// EvaluateWebhook returns the rules that fire on a webhook payload.
+// See Evaluate for the evaluation order.
+func (rs *RuleSet) EvaluateWebhook(payload *DocumentWebhookPayload) []RuleMatch {
+	return rs.evaluate(payload.ToDocument(), payload.EventType)
+}
+
+func (rs *RuleSet) evaluate(doc Document, event WebhookEventType) []RuleMatch {
+	tags := doc.TagNames()
+
+	var matches []RuleMatch
+	for _, r := range rs.Rules {
+		if !r.When.match(doc, tags, event) {
+			continue
+		}
+
+		update := &UpdateDocumentRequest{}
+		if r.Then.Location != "" && r.Then.Location != doc.Location {
+			update.Location = r.Then.Location
+			doc.Location = r.Then.Location
+		}
+		added := false
+		for _, tag := range r.Then.AddTags {
+			if !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+				added = true
+			}
+		}
+		if added {
+			sort.Strings(tags)
+			update.Tags = slices.Clone(tags)
+		}
+		if r.Then.Seen != nil && *r.Then.Seen != (doc.FirstOpenedAt != nil) {
+			update.Seen = r.Then.Seen
+			if *r.Then.Seen {
+				now := time.Now()
+				doc.FirstOpenedAt = &now
+			} else {
+				doc.FirstOpenedAt = nil
+			}
+		}
+		if update.Location == "" && update.Tags == nil && update.Seen == nil {
+			continue
+		}
+
+		matches = append(matches, RuleMatch{
+			Rule:       r.Name,
+			DocumentID: doc.ID,
+			Title:      doc.Title,
+			Update:     update,
+		})
+	}
+
+	return matches
+}
+
+func (cond *RuleCondition) match(doc Document, tags []string, event WebhookEventType) bool {
+	if cond.EventType != "" && cond.EventType != event {
+		return false
+	}
+	if cond.Category != "" && cond.Category != doc.Category {
+		return false
+	}
+	if cond.Location != "" && cond.Location != doc.Location {
+		return false
+	}
+	if cond.Tag != "" && !slices.Contains(tags, cond.Tag) {
+		return false
+	}
+	if cond.siteName != nil && !cond.siteName.MatchString(doc.SiteName) {
+		return false
+	}
+	if cond.author != nil && !cond.author.MatchString(doc.Author) {
+		return false
+	}
+	if cond.title != nil && !cond.title.MatchString(doc.Title) {
+		return false
+	}
+	if cond.TitleContains != "" && !strings.Contains(doc.Title, cond.TitleContains) {
+		return false
+	}
+	if cond.url != nil && !cond.url.MatchString(doc.URL) && !cond.url.MatchString(doc.SourceURL) {
+		return false
+	}
+	if cond.WordCountLT > 0 && doc.WordCount >= cond.WordCountLT {
+		return false
+	}
+	if cond.WordCountGT > 0 && doc.WordCount <= cond.WordCountGT {
+		return false
+	}
+	return true
+}
+
+// ApplyRuleMatches sends the update of each match to the API in order and
+// returns the results for the audit log. With dryRun nothing is sent.
+// A failed update is recorded in its result and does not stop the others.
+func ApplyRuleMatches(ctx context.Context, c Client, matches []RuleMatch, dryRun bool) []RuleResult {
+	results := make([]RuleResult, 0, len(matches))
+	for _, m := range matches {
+		result := RuleResult{
+			RuleMatch: m,
+			Time:      time.Now(),
+			DryRun:    dryRun,
+		}
+		if !dryRun {
+			if _, err := c.UpdateDocument(ctx, m.DocumentID, m.Update); err != nil {
+				result.Error = err.Error()
+			}
+		}
+		results = append(results, result)
+	}
+	return results
+}
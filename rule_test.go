@@ -0,0 +1,225 @@
+package reader
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func testRuleSet(t *testing.T) *RuleSet {
+	t.Helper()
+	rs := &RuleSet{
+		Rules: []Rule{
+			{
+				Name: "archive short rss",
+				When: RuleCondition{
+					Category:    CategoryRSS,
+					SiteName:    "^Hacker News$",
+					WordCountLT: 300,
+				},
+				Then: RuleAction{Location: LocationArchive},
+			},
+			{
+				Name: "standards",
+				When: RuleCondition{TitleContains: "RFC"},
+				Then: RuleAction{Location: LocationLater, AddTags: []string{"standards"}},
+			},
+			{
+				Name: "finished",
+				When: RuleCondition{EventType: EventDocumentFinished},
+				Then: RuleAction{AddTags: []string{"done"}},
+			},
+		},
+	}
+	if err := rs.Compile(); err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+	return rs
+}
+
+func TestRuleSet_Compile(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    Rule
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			rule: Rule{Name: "r", When: RuleCondition{Title: "(?i)go"}, Then: RuleAction{Location: LocationLater}},
+		},
+		{
+			name:    "missing name",
+			rule:    Rule{Then: RuleAction{Location: LocationLater}},
+			wantErr: true,
+		},
+		{
+			name:    "empty action",
+			rule:    Rule{Name: "r"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid pattern",
+			rule:    Rule{Name: "r", When: RuleCondition{SiteName: "("}, Then: RuleAction{Location: LocationLater}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rs := &RuleSet{Rules: []Rule{tt.rule}}
+			if err := rs.Compile(); (err != nil) != tt.wantErr {
+				t.Errorf("Compile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRuleSet_Evaluate(t *testing.T) {
+	rs := testRuleSet(t)
+
+	tests := []struct {
+		name      string
+		doc       Document
+		wantRules []string
+		wantLast  *UpdateDocumentRequest
+	}{
+		{
+			name: "short rss archived",
+			doc: Document{
+				ID:        "doc1",
+				Category:  CategoryRSS,
+				SiteName:  "Hacker News",
+				WordCount: 120,
+				Location:  LocationFeed,
+			},
+			wantRules: []string{"archive short rss"},
+			wantLast:  &UpdateDocumentRequest{Location: LocationArchive},
+		},
+		{
+			name: "long rss not archived",
+			doc: Document{
+				ID:        "doc2",
+				Category:  CategoryRSS,
+				SiteName:  "Hacker News",
+				WordCount: 300,
+				Location:  LocationFeed,
+			},
+		},
+		{
+			name: "RFC tagged and moved with existing tags kept",
+			doc: Document{
+				ID:       "doc3",
+				Title:    "RFC 9110: HTTP Semantics",
+				Location: LocationNew,
+				Tags:     map[string]interface{}{"http": true},
+			},
+			wantRules: []string{"standards"},
+			wantLast: &UpdateDocumentRequest{
+				Location: LocationLater,
+				Tags:     []string{"http", "standards"},
+			},
+		},
+		{
+			name: "already applied",
+			doc: Document{
+				ID:       "doc4",
+				Title:    "RFC 9110: HTTP Semantics",
+				Location: LocationLater,
+				Tags:     map[string]interface{}{"standards": true},
+			},
+		},
+		{
+			name: "event rule does not fire when polling",
+			doc:  Document{ID: "doc5", Title: "Anything"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches := rs.Evaluate(tt.doc)
+
+			var rules []string
+			for _, m := range matches {
+				rules = append(rules, m.Rule)
+				if m.DocumentID != tt.doc.ID {
+					t.Errorf("DocumentID = %v, want %v", m.DocumentID, tt.doc.ID)
+				}
+			}
+			if !reflect.DeepEqual(rules, tt.wantRules) {
+				t.Fatalf("rules = %v, want %v", rules, tt.wantRules)
+			}
+			if tt.wantLast != nil && !reflect.DeepEqual(matches[len(matches)-1].Update, tt.wantLast) {
+				t.Errorf("Update = %+v, want %+v", matches[len(matches)-1].Update, tt.wantLast)
+			}
+		})
+	}
+}
+
+func TestRuleSet_EvaluateWebhook(t *testing.T) {
+	rs := testRuleSet(t)
+
+	matches := rs.EvaluateWebhook(&DocumentWebhookPayload{
+		EventType: EventDocumentFinished,
+		ID:        "doc1",
+		Title:     "RFC 1149",
+		Location:  LocationArchive,
+	})
+
+	var rules []string
+	for _, m := range matches {
+		rules = append(rules, m.Rule)
+	}
+	if !reflect.DeepEqual(rules, []string{"standards", "finished"}) {
+		t.Fatalf("rules = %v, want [standards finished]", rules)
+	}
+	if !reflect.DeepEqual(matches[1].Update.Tags, []string{"done", "standards"}) {
+		t.Errorf("Tags = %v, want [done standards]", matches[1].Update.Tags)
+	}
+}
+
+func TestApplyRuleMatches(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if r.URL.Path == "/update/bad/" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(UpdateDocumentResponse{ID: "doc1"})
+	}))
+	defer server.Close()
+
+	c := &client{
+		baseURL:    server.URL,
+		token:      "test-token",
+		httpClient: &http.Client{},
+	}
+
+	matches := []RuleMatch{
+		{Rule: "r1", DocumentID: "doc1", Update: &UpdateDocumentRequest{Location: LocationArchive}},
+		{Rule: "r2", DocumentID: "bad", Update: &UpdateDocumentRequest{Location: LocationArchive}},
+	}
+
+	results := ApplyRuleMatches(context.Background(), c, matches, true)
+	if len(paths) != 0 {
+		t.Errorf("dry run sent requests: %v", paths)
+	}
+	if len(results) != 2 || !results[0].DryRun {
+		t.Errorf("dry run results = %+v", results)
+	}
+
+	results = ApplyRuleMatches(context.Background(), c, matches, false)
+	if !reflect.DeepEqual(paths, []string{"/update/doc1/", "/update/bad/"}) {
+		t.Errorf("paths = %v", paths)
+	}
+	if results[0].Error != "" {
+		t.Errorf("results[0].Error = %v", results[0].Error)
+	}
+	if results[1].Error == "" {
+		t.Error("results[1].Error is empty, want API error")
+	}
+}
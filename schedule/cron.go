@@ -0,0 +1,203 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron expression
+type Schedule struct {
+	spec string
+
+	minute, hour, dom, month, dow uint64
+
+	// domAny and dowAny are true when the day of month or day of week field
+	// is *. When both are restricted, a day matching either one matches.
+	domAny, dowAny bool
+}
+
+// cronField is the range of values of a cron expression field
+type cronField struct {
+	name     string
+	min, max int
+	names    []string
+}
+
+var (
+	minuteField = cronField{name: "minute", min: 0, max: 59}
+	hourField   = cronField{name: "hour", min: 0, max: 23}
+	domField    = cronField{name: "day of month", min: 1, max: 31}
+	monthField  = cronField{name: "month", min: 1, max: 12, names: []string{
+		"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec",
+	}}
+	// Sunday is both 0 and 7
+	dowField = cronField{name: "day of week", min: 0, max: 7, names: []string{
+		"sun", "mon", "tue", "wed", "thu", "fri", "sat",
+	}}
+)
+
+// cronMacros are the predefined schedules
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Parse parses a cron expression of five fields: minute, hour, day of month,
+// month and day of week. Fields are *, values, ranges (1-5) and lists of
+// them (1,15), with an optional step (*/15, 0-30/10). Months and days of
+// week can be given by their first three letters (jan, mon). The macros
+// @yearly, @monthly, @weekly, @daily and @hourly are also accepted.
+func Parse(spec string) (*Schedule, error) {
+	expr := strings.TrimSpace(spec)
+	if macro, ok := cronMacros[strings.ToLower(expr)]; ok {
+		expr = macro
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid schedule %q: expected 5 fields, got %d", spec, len(fields))
+	}
+
+	s := &Schedule{
+		spec:   spec,
+		domAny: fields[2] == "*",
+		dowAny: fields[4] == "*",
+	}
+	for i, p := range []struct {
+		field cronField
+		bits  *uint64
+	}{
+		{minuteField, &s.minute},
+		{hourField, &s.hour},
+		{domField, &s.dom},
+		{monthField, &s.month},
+		{dowField, &s.dow},
+	} {
+		bits, err := p.field.parse(fields[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %w", spec, err)
+		}
+		*p.bits = bits
+	}
+	// Sunday is matched as 0
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	return s, nil
+}
+
+// parse returns the set of values of the field as a bit set
+func (f cronField) parse(s string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(s, ",") {
+		rng, stepText, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepText); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid %s step %q", f.name, stepText)
+			}
+		}
+
+		var lo, hi int
+		switch {
+		case rng == "*":
+			lo, hi = f.min, f.max
+		case strings.Contains(rng, "-"):
+			loText, hiText, _ := strings.Cut(rng, "-")
+			var err error
+			if lo, err = f.value(loText); err != nil {
+				return 0, err
+			}
+			if hi, err = f.value(hiText); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("invalid %s range %q", f.name, rng)
+			}
+		default:
+			var err error
+			if lo, err = f.value(rng); err != nil {
+				return 0, err
+			}
+			hi = lo
+			// 5/15 means from 5 to the end every 15
+			if hasStep {
+				hi = f.max
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+// value parses a single value of the field, a number or a name
+func (f cronField) value(s string) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(s, name) {
+			return f.min + i, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid %s %q: must be between %d and %d", f.name, s, f.min, f.max)
+	}
+	return v, nil
+}
+
+// String returns the cron expression the schedule was parsed from
+func (s *Schedule) String() string {
+	return s.spec
+}
+
+// Next returns the first time after t that matches the schedule, in the
+// location of t. It returns the zero time if the schedule never matches, e.g.
+// for February 30.
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	// A matching time is found within a few years unless there is none
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if s.month&(1<<int(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hour&(1<<t.Hour()) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minute&(1<<t.Minute()) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s *Schedule) matchDay(t time.Time) bool {
+	dom := s.dom&(1<<t.Day()) != 0
+	dow := s.dow&(1<<int(t.Weekday())) != 0
+	switch {
+	case s.domAny && s.dowAny:
+		return true
+	case s.domAny:
+		return dow
+	case s.dowAny:
+		return dom
+	default:
+		return dom || dow
+	}
+}
@@ -0,0 +1,62 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSchedule_Next(t *testing.T) {
+	// A Wednesday
+	from := time.Date(2025, 7, 16, 10, 30, 15, 0, time.UTC)
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2025, 7, 16, 10, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2025, 7, 16, 10, 45, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2025, 7, 17, 3, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2025, 7, 17, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2025, 7, 16, 11, 0, 0, 0, time.UTC)},
+		{"0 9 * * mon-fri", time.Date(2025, 7, 17, 9, 0, 0, 0, time.UTC)},
+		{"0 9 * * 0", time.Date(2025, 7, 20, 9, 0, 0, 0, time.UTC)},
+		{"0 9 * * 7", time.Date(2025, 7, 20, 9, 0, 0, 0, time.UTC)},
+		{"30 10 1,15 * *", time.Date(2025, 8, 1, 10, 30, 0, 0, time.UTC)},
+		{"0 0 1 jan *", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 12 5-25/10 * *", time.Date(2025, 7, 25, 12, 0, 0, 0, time.UTC)},
+		// Either the day of month or the day of week matches
+		{"0 0 1 * fri", time.Date(2025, 7, 18, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			s, err := Parse(tt.spec)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := s.Next(from); !got.Equal(tt.want) {
+				t.Errorf("Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"0 12 5-40/10 * *",
+		"a * * * *",
+	} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("Parse(%q) error = nil, want error", spec)
+		}
+	}
+}
@@ -0,0 +1,372 @@
+package schedule
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	reader "github.com/tcnksm/go-readwise-reader"
+	"github.com/tcnksm/go-readwise-reader/journal"
+	"github.com/tcnksm/go-readwise-reader/subscriptions"
+)
+
+// JobType is the kind of a configured job
+type JobType string
+
+const (
+	// JobMove moves the documents matching a query to a location, e.g. to
+	// archive feed documents older than 30 days
+	JobMove JobType = "move"
+
+	// JobRules applies a rule set to the documents updated since the last
+	// successful run
+	JobRules JobType = "rules"
+
+	// JobExport writes the documents matching a query to a file as JSON lines
+	JobExport JobType = "export"
+
+	// JobFeeds saves the new entries of subscribed feeds, as reader feeds poll
+	JobFeeds JobType = "feeds"
+)
+
+// defaultRulesSince is how far back a rules job looks on its first run
+const defaultRulesSince = 24 * time.Hour
+
+// feedTimeout is the timeout for fetching a feed in a feeds job
+const feedTimeout = 30 * time.Second
+
+// Config is a schedule of jobs, typically loaded from a YAML file
+type Config struct {
+	Jobs []JobConfig `json:"jobs" yaml:"jobs"`
+}
+
+// JobConfig is a configured job
+type JobConfig struct {
+	// Name identifies the job
+	Name string `json:"name" yaml:"name"`
+
+	// Schedule is a cron expression, see Parse
+	Schedule string `json:"schedule" yaml:"schedule"`
+
+	// Type is what the job does
+	Type JobType `json:"type" yaml:"type"`
+
+	// Query selects the documents to move or export (see reader.ParseQuery).
+	// For rules jobs it optionally narrows the documents evaluated. Relative
+	// times such as -30d are relative to the start of each run.
+	Query string `json:"query,omitempty" yaml:"query,omitempty"`
+
+	// To is the location documents are moved to. Default: archive.
+	To reader.Location `json:"to,omitempty" yaml:"to,omitempty"`
+
+	// Rules is the path of the YAML rules file of a rules job. The rule set
+	// is loaded by the caller into RuleSet.
+	Rules   string          `json:"rules,omitempty" yaml:"rules,omitempty"`
+	RuleSet *reader.RuleSet `json:"-" yaml:"-"`
+
+	// Since is how far back a rules job looks for updated documents on its
+	// first run, e.g. 24h. Later runs evaluate the documents updated since
+	// the last successful run. Default: 24h.
+	Since string `json:"since,omitempty" yaml:"since,omitempty"`
+
+	// Path is the file an export job writes
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+
+	// Content includes the HTML content of exported documents
+	Content bool `json:"content,omitempty" yaml:"content,omitempty"`
+
+	// State is the feed state of a feeds job, as used by reader feeds
+	State string `json:"state,omitempty" yaml:"state,omitempty"`
+
+	// MaxEntries is the number of new entries saved per feed by a feeds
+	// job. Default: subscriptions.DefaultMaxEntries.
+	MaxEntries int `json:"max_entries,omitempty" yaml:"max_entries,omitempty"`
+
+	// DryRun reports what the job would change without changing it
+	DryRun bool `json:"dry_run,omitempty" yaml:"dry_run,omitempty"`
+
+	schedule *Schedule
+	since    time.Duration
+}
+
+// Compile validates the jobs and parses their schedules. It must be called
+// before Build.
+func (cfg *Config) Compile() error {
+	names := make(map[string]bool)
+	for i := range cfg.Jobs {
+		job := &cfg.Jobs[i]
+		if job.Name == "" {
+			return fmt.Errorf("job %d: name is required", i+1)
+		}
+		if names[job.Name] {
+			return fmt.Errorf("job %s: duplicate name", job.Name)
+		}
+		names[job.Name] = true
+		if err := job.compile(); err != nil {
+			return fmt.Errorf("job %s: %w", job.Name, err)
+		}
+	}
+	return nil
+}
+
+func (job *JobConfig) compile() error {
+	var err error
+	if job.schedule, err = Parse(job.Schedule); err != nil {
+		return err
+	}
+	if job.Query != "" {
+		if _, err := reader.ParseQuery(job.Query, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	switch job.Type {
+	case JobMove:
+		if job.Query == "" {
+			return errors.New("query is required")
+		}
+		if job.To == "" {
+			job.To = reader.LocationArchive
+		} else if !job.To.Valid() {
+			return fmt.Errorf("invalid location: %s", job.To)
+		}
+	case JobRules:
+		if job.Rules == "" {
+			return errors.New("rules is required")
+		}
+		job.since = defaultRulesSince
+		if job.Since != "" {
+			if job.since, err = time.ParseDuration(job.Since); err != nil || job.since <= 0 {
+				return fmt.Errorf("invalid since: %s", job.Since)
+			}
+		}
+	case JobExport:
+		if job.Path == "" {
+			return errors.New("path is required")
+		}
+	case JobFeeds:
+		if job.State == "" {
+			return errors.New("state is required")
+		}
+		if job.MaxEntries < 0 {
+			return fmt.Errorf("invalid max_entries: %d", job.MaxEntries)
+		}
+	default:
+		return fmt.Errorf("invalid type %q: must be move, rules, export or feeds", job.Type)
+	}
+	return nil
+}
+
+// Build returns the configured jobs run with c. Documents moved by move jobs
+// are recorded in j, if not nil, so that they can be moved back with reader
+// undo.
+func (cfg *Config) Build(c reader.Client, j *journal.Journal) ([]*Job, error) {
+	jobs := make([]*Job, 0, len(cfg.Jobs))
+	for i := range cfg.Jobs {
+		job := &cfg.Jobs[i]
+		if job.schedule == nil {
+			return nil, fmt.Errorf("job %s: not compiled", job.Name)
+		}
+		if job.Type == JobRules && job.RuleSet == nil {
+			return nil, fmt.Errorf("job %s: rule set not loaded", job.Name)
+		}
+		jobs = append(jobs, &Job{
+			Name:     job.Name,
+			Type:     job.Type,
+			Schedule: job.schedule,
+			Func:     job.run(c, j),
+		})
+	}
+	return jobs, nil
+}
+
+func (job *JobConfig) run(c reader.Client, j *journal.Journal) Func {
+	return func(ctx context.Context, lastSuccess time.Time) (any, error) {
+		switch job.Type {
+		case JobMove:
+			return job.move(ctx, c, j)
+		case JobRules:
+			return job.applyRules(ctx, c, lastSuccess)
+		case JobExport:
+			return job.export(ctx, c)
+		case JobFeeds:
+			return job.pollFeeds(ctx, c)
+		}
+		return nil, fmt.Errorf("invalid type %q", job.Type)
+	}
+}
+
+// MoveResult is the result of a move job
+type MoveResult struct {
+	// Moved are the IDs of the documents moved, or that would be moved in a
+	// dry run
+	Moved []string `json:"moved"`
+
+	// DryRun is true if the documents were not moved
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+func (job *JobConfig) move(ctx context.Context, c reader.Client, j *journal.Journal) (*MoveResult, error) {
+	docs, err := findDocuments(ctx, c, job.Query, nil, false)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &MoveResult{Moved: []string{}, DryRun: job.DryRun}
+	var changes []journal.Change
+	var errs []error
+	for _, doc := range docs {
+		if doc.Location == job.To {
+			continue
+		}
+		if !job.DryRun {
+			if _, err := c.UpdateDocument(ctx, doc.ID, &reader.UpdateDocumentRequest{Location: job.To}); err != nil {
+				errs = append(errs, fmt.Errorf("failed to move document %s: %w", doc.ID, err))
+				continue
+			}
+			changes = append(changes, journal.Change{Operation: journal.OperationUpdate, DocumentID: doc.ID, Before: &doc})
+		}
+		result.Moved = append(result.Moved, doc.ID)
+	}
+	if j != nil && len(changes) > 0 {
+		if _, err := j.Record("reader cron "+job.Name, changes); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return result, errors.Join(errs...)
+}
+
+// RulesResult is the result of a rules job
+type RulesResult struct {
+	// UpdatedAfter is the start of the window of evaluated documents
+	UpdatedAfter time.Time `json:"updated_after"`
+
+	// Documents is the number of documents evaluated
+	Documents int `json:"documents"`
+
+	// Results are the rules that fired and their outcome
+	Results []reader.RuleResult `json:"results"`
+}
+
+func (job *JobConfig) applyRules(ctx context.Context, c reader.Client, lastSuccess time.Time) (*RulesResult, error) {
+	since := lastSuccess
+	if since.IsZero() {
+		since = time.Now().Add(-job.since)
+	}
+	docs, err := findDocuments(ctx, c, job.Query, &since, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []reader.RuleMatch
+	for _, doc := range docs {
+		matches = append(matches, job.RuleSet.Evaluate(doc)...)
+	}
+	result := &RulesResult{
+		UpdatedAfter: since,
+		Documents:    len(docs),
+		Results:      reader.ApplyRuleMatches(ctx, c, matches, job.DryRun),
+	}
+	var errs []error
+	for _, r := range result.Results {
+		if r.Error != "" {
+			errs = append(errs, fmt.Errorf("rule %s on document %s: %s", r.Rule, r.DocumentID, r.Error))
+		}
+	}
+	return result, errors.Join(errs...)
+}
+
+// ExportResult is the result of an export job
+type ExportResult struct {
+	// Path is the file written
+	Path string `json:"path"`
+
+	// Documents is the number of documents exported
+	Documents int `json:"documents"`
+}
+
+func (job *JobConfig) export(ctx context.Context, c reader.Client) (*ExportResult, error) {
+	docs, err := findDocuments(ctx, c, job.Query, nil, job.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	for _, doc := range docs {
+		line, err := json.Marshal(doc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode document: %w", err)
+		}
+		data = append(append(data, line...), '\n')
+	}
+	if err := os.MkdirAll(filepath.Dir(job.Path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create export directory: %w", err)
+	}
+	// Write atomically so that readers never see a partial export
+	tmp := job.Path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write export: %w", err)
+	}
+	if err := os.Rename(tmp, job.Path); err != nil {
+		return nil, fmt.Errorf("failed to write export: %w", err)
+	}
+	return &ExportResult{Path: job.Path, Documents: len(docs)}, nil
+}
+
+func (job *JobConfig) pollFeeds(ctx context.Context, c reader.Client) ([]subscriptions.Result, error) {
+	state, err := subscriptions.LoadState(job.State)
+	if err != nil {
+		return nil, err
+	}
+	im := &subscriptions.Importer{
+		Client:     c,
+		HTTPClient: &http.Client{Timeout: feedTimeout},
+		State:      state,
+		MaxEntries: job.MaxEntries,
+		DryRun:     job.DryRun,
+	}
+	results := im.Poll(ctx, state.Subscriptions())
+	if !job.DryRun {
+		if err := state.Save(); err != nil {
+			return results, err
+		}
+	}
+
+	var errs []error
+	for _, r := range results {
+		if r.Error != "" {
+			errs = append(errs, fmt.Errorf("%s: %s", r.Feed, r.Error))
+		}
+	}
+	return results, errors.Join(errs...)
+}
+
+// findDocuments lists the documents matching query, all documents if query
+// is empty, updated after updatedAfter if not nil. Relative times in the
+// query are relative to now.
+func findDocuments(ctx context.Context, c reader.Client, query string, updatedAfter *time.Time, withContent bool) ([]reader.Document, error) {
+	opts := &reader.ListDocumentsOptions{}
+	var match func(reader.Document) bool
+	if query != "" {
+		q, err := reader.ParseQuery(query, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		opts, match = q.ListOptions(), q.Match
+	}
+	if updatedAfter != nil && (opts.UpdatedAfter == nil || updatedAfter.After(*opts.UpdatedAfter)) {
+		opts.UpdatedAfter = updatedAfter
+	}
+	opts.WithHTMLContent = withContent
+
+	docs, err := reader.FindDocuments(ctx, c, opts, match, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list documents: %w", err)
+	}
+	return docs, nil
+}
@@ -0,0 +1,231 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	reader "github.com/tcnksm/go-readwise-reader"
+	"github.com/tcnksm/go-readwise-reader/journal"
+)
+
+// fakeClient is a reader.Client that lists fixed documents and records
+// updates
+type fakeClient struct {
+	reader.Client
+	docs    []reader.Document
+	opts    []reader.ListDocumentsOptions
+	updates map[string]*reader.UpdateDocumentRequest
+	failID  string
+}
+
+func (c *fakeClient) ListDocuments(ctx context.Context, opts *reader.ListDocumentsOptions) (*reader.ListDocumentsResponse, error) {
+	c.opts = append(c.opts, *opts)
+	var docs []reader.Document
+	for _, doc := range c.docs {
+		if opts.Location == "" || doc.Location == opts.Location {
+			docs = append(docs, doc)
+		}
+	}
+	return &reader.ListDocumentsResponse{Count: len(docs), Results: docs}, nil
+}
+
+func (c *fakeClient) UpdateDocument(ctx context.Context, documentID string, req *reader.UpdateDocumentRequest) (*reader.UpdateDocumentResponse, error) {
+	if documentID == c.failID {
+		return nil, errors.New("rate limited")
+	}
+	if c.updates == nil {
+		c.updates = make(map[string]*reader.UpdateDocumentRequest)
+	}
+	c.updates[documentID] = req
+	return &reader.UpdateDocumentResponse{ID: documentID}, nil
+}
+
+func daysAgo(n int) *time.Time {
+	t := time.Now().AddDate(0, 0, -n)
+	return &t
+}
+
+func compileJob(t *testing.T, c reader.Client, j *journal.Journal, job JobConfig) *Job {
+	t.Helper()
+	job.Name = "test"
+	job.Schedule = "@daily"
+	cfg := &Config{Jobs: []JobConfig{job}}
+	if err := cfg.Compile(); err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+	jobs, err := cfg.Build(c, j)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	return jobs[0]
+}
+
+func TestMoveJob(t *testing.T) {
+	c := &fakeClient{docs: []reader.Document{
+		{ID: "old", Location: reader.LocationFeed, SavedAt: daysAgo(45)},
+		{ID: "fail", Location: reader.LocationFeed, SavedAt: daysAgo(40)},
+		{ID: "recent", Location: reader.LocationFeed, SavedAt: daysAgo(3)},
+		{ID: "later", Location: reader.LocationLater, SavedAt: daysAgo(90)},
+	}, failID: "fail"}
+	j := journal.Open(filepath.Join(t.TempDir(), "journal.jsonl"))
+
+	job := compileJob(t, c, j, JobConfig{Type: JobMove, Query: "location=feed and saved_at < -30d"})
+	result, err := job.Func(context.Background(), time.Time{})
+	if err == nil || !strings.Contains(err.Error(), "failed to move document fail") {
+		t.Errorf("Func() error = %v, want the failed move", err)
+	}
+	moved := result.(*MoveResult).Moved
+	if len(moved) != 1 || moved[0] != "old" {
+		t.Errorf("Moved = %v, want [old]", moved)
+	}
+	if req := c.updates["old"]; req == nil || req.Location != reader.LocationArchive {
+		t.Errorf("update = %+v, want a move to archive", req)
+	}
+	if c.opts[0].Location != reader.LocationFeed {
+		t.Errorf("ListDocuments() options = %+v, want the feed location", c.opts[0])
+	}
+
+	// The move can be undone
+	entries, err := j.Entries()
+	if err != nil {
+		t.Fatalf("Entries() error = %v", err)
+	}
+	if len(entries) != 1 || entries[0].Command != "reader cron test" || len(entries[0].Changes) != 1 ||
+		entries[0].Changes[0].Before.Location != reader.LocationFeed {
+		t.Errorf("journal = %+v", entries)
+	}
+}
+
+func TestMoveJob_DryRun(t *testing.T) {
+	c := &fakeClient{docs: []reader.Document{
+		{ID: "stale", Location: reader.LocationLater, SavedAt: daysAgo(120)},
+	}}
+
+	job := compileJob(t, c, nil, JobConfig{Type: JobMove, Query: "location=later and saved_at < -90d", DryRun: true})
+	result, err := job.Func(context.Background(), time.Time{})
+	if err != nil {
+		t.Fatalf("Func() error = %v", err)
+	}
+	if moved := result.(*MoveResult).Moved; len(moved) != 1 || len(c.updates) != 0 {
+		t.Errorf("Moved = %v, updates = %v, want a dry run", moved, c.updates)
+	}
+}
+
+func TestRulesJob(t *testing.T) {
+	c := &fakeClient{docs: []reader.Document{
+		{ID: "doc1", Title: "Weekly newsletter", Location: reader.LocationNew},
+		{ID: "doc2", Title: "Essay", Location: reader.LocationNew},
+	}}
+
+	ruleSet := &reader.RuleSet{Rules: []reader.Rule{{
+		Name: "newsletters",
+		When: reader.RuleCondition{TitleContains: "newsletter"},
+		Then: reader.RuleAction{Location: reader.LocationFeed},
+	}}}
+	if err := ruleSet.Compile(); err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+	job := compileJob(t, c, nil, JobConfig{Type: JobRules, Rules: "rules.yaml", RuleSet: ruleSet, Since: "2h"})
+
+	// The first run looks back Since, later runs from the last success
+	lastSuccess := time.Now().Add(-10 * time.Minute).Truncate(time.Second)
+	for _, since := range []time.Time{{}, lastSuccess} {
+		before := time.Now()
+		result, err := job.Func(context.Background(), since)
+		if err != nil {
+			t.Fatalf("Func() error = %v", err)
+		}
+		r := result.(*RulesResult)
+		if r.Documents != 2 || len(r.Results) != 1 || r.Results[0].DocumentID != "doc1" {
+			t.Errorf("result = %+v", r)
+		}
+		updatedAfter := *c.opts[len(c.opts)-1].UpdatedAfter
+		want := since
+		if since.IsZero() {
+			want = before.Add(-2 * time.Hour)
+		}
+		if updatedAfter.Sub(want).Abs() > time.Second {
+			t.Errorf("UpdatedAfter = %v, want %v", updatedAfter, want)
+		}
+	}
+	if req := c.updates["doc1"]; req == nil || req.Location != reader.LocationFeed {
+		t.Errorf("update = %+v, want a move to feed", req)
+	}
+}
+
+func TestExportJob(t *testing.T) {
+	c := &fakeClient{docs: []reader.Document{
+		{ID: "doc1", Location: reader.LocationArchive, Tags: map[string]interface{}{"share": nil}},
+		{ID: "doc2", Location: reader.LocationArchive},
+	}}
+
+	path := filepath.Join(t.TempDir(), "exports", "shared.jsonl")
+	job := compileJob(t, c, nil, JobConfig{Type: JobExport, Query: "location=archive and tag=share", Path: path, Content: true})
+	result, err := job.Func(context.Background(), time.Time{})
+	if err != nil {
+		t.Fatalf("Func() error = %v", err)
+	}
+	if r := result.(*ExportResult); r.Documents != 1 || r.Path != path {
+		t.Errorf("result = %+v", r)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(string(data)), "\n"); len(lines) != 1 || !strings.Contains(lines[0], `"id":"doc1"`) {
+		t.Errorf("export = %s", data)
+	}
+	if !c.opts[0].WithHTMLContent {
+		t.Error("WithHTMLContent = false, want true")
+	}
+}
+
+func TestConfig_Compile(t *testing.T) {
+	tests := []struct {
+		name string
+		job  JobConfig
+		want string
+	}{
+		{"no name", JobConfig{Schedule: "@daily", Type: JobExport, Path: "out.jsonl"}, "name is required"},
+		{"bad schedule", JobConfig{Name: "a", Schedule: "daily", Type: JobExport, Path: "out.jsonl"}, "invalid schedule"},
+		{"bad type", JobConfig{Name: "a", Schedule: "@daily", Type: "sync"}, "invalid type"},
+		{"bad query", JobConfig{Name: "a", Schedule: "@daily", Type: JobMove, Query: "size>1"}, "job a:"},
+		{"move without query", JobConfig{Name: "a", Schedule: "@daily", Type: JobMove}, "query is required"},
+		{"move to bad location", JobConfig{Name: "a", Schedule: "@daily", Type: JobMove, Query: "location=feed", To: "trash"}, "invalid location"},
+		{"rules without file", JobConfig{Name: "a", Schedule: "@daily", Type: JobRules}, "rules is required"},
+		{"rules bad since", JobConfig{Name: "a", Schedule: "@daily", Type: JobRules, Rules: "r.yaml", Since: "1d"}, "invalid since"},
+		{"export without path", JobConfig{Name: "a", Schedule: "@daily", Type: JobExport}, "path is required"},
+		{"feeds without state", JobConfig{Name: "a", Schedule: "@daily", Type: JobFeeds}, "state is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Jobs: []JobConfig{tt.job}}
+			err := cfg.Compile()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Compile() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	cfg := &Config{Jobs: []JobConfig{
+		{Name: "a", Schedule: "@daily", Type: JobExport, Path: "a.jsonl"},
+		{Name: "a", Schedule: "@daily", Type: JobExport, Path: "b.jsonl"},
+	}}
+	if err := cfg.Compile(); err == nil || !strings.Contains(err.Error(), "duplicate name") {
+		t.Errorf("Compile() error = %v, want duplicate name", err)
+	}
+
+	// A rules job needs its rule set loaded
+	cfg = &Config{Jobs: []JobConfig{{Name: "a", Schedule: "@daily", Type: JobRules, Rules: "r.yaml"}}}
+	if err := cfg.Compile(); err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+	if _, err := cfg.Build(&fakeClient{}, nil); err == nil {
+		t.Error("Build() error = nil, want error for a rule set not loaded")
+	}
+}
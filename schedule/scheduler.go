@@ -0,0 +1,425 @@
+// Package schedule runs recurring maintenance jobs against a Readwise Reader
+// library on cron schedules, such as archiving old feed documents, running
+// triage rules, exporting documents and polling feed subscriptions.
+//
+// A Scheduler runs each job at the times of its schedule. A job that is
+// still running when it is due again is skipped rather than run twice. Every
+// run is appended to a history file with its result or error, and Handler
+// serves the status of the jobs and their history over HTTP.
+package schedule
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Func runs a job. lastSuccess is when the last successful run started, or
+// the zero time if the job never succeeded. The result is recorded in the
+// run history as JSON.
+type Func func(ctx context.Context, lastSuccess time.Time) (any, error)
+
+// Job is a named function run on a schedule
+type Job struct {
+	// Name identifies the job in the history and the status
+	Name string
+
+	// Type describes what the job does, for the status
+	Type JobType
+
+	// Schedule is when the job runs
+	Schedule *Schedule
+
+	// Func runs the job
+	Func Func
+}
+
+// RunStatus is the outcome of a run
+type RunStatus string
+
+const (
+	RunSucceeded RunStatus = "succeeded"
+	RunFailed    RunStatus = "failed"
+
+	// RunSkipped is recorded when a job is due while it is still running
+	RunSkipped RunStatus = "skipped"
+)
+
+// Run is a run of a job as recorded in the history
+type Run struct {
+	// Job is the name of the job
+	Job string `json:"job"`
+
+	// Start and End are when the run started and ended
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+
+	// Status is the outcome of the run
+	Status RunStatus `json:"status"`
+
+	// Result is what the job returned, if anything
+	Result json.RawMessage `json:"result,omitempty"`
+
+	// Error is the error the job returned, if any
+	Error string `json:"error,omitempty"`
+}
+
+// JobStatus is the state of a job
+type JobStatus struct {
+	Name     string  `json:"name"`
+	Type     JobType `json:"type,omitempty"`
+	Schedule string  `json:"schedule"`
+
+	// Next is when the job runs next, if the scheduler is running
+	Next *time.Time `json:"next,omitempty"`
+
+	// Running is true while the job runs
+	Running bool `json:"running"`
+
+	// LastRun is the last run of the job, if any
+	LastRun *Run `json:"last_run,omitempty"`
+
+	// LastSuccess is when the last successful run started, if any
+	LastSuccess *time.Time `json:"last_success,omitempty"`
+}
+
+// Scheduler runs jobs on their schedules and records their runs
+type Scheduler struct {
+	// Logger logs the runs of Run. Logs are discarded if nil.
+	Logger *slog.Logger
+
+	path string
+	jobs []*Job
+
+	mu          sync.Mutex
+	running     map[string]bool
+	last        map[string]*Run
+	lastSuccess map[string]time.Time
+	next        map[string]time.Time
+
+	// now and after are replaced in tests
+	now   func() time.Time
+	after func(time.Duration) <-chan time.Time
+}
+
+// New returns a scheduler of jobs that records their runs in the history
+// file at path. The last runs of the jobs are read from the history.
+func New(path string, jobs []*Job) (*Scheduler, error) {
+	s := &Scheduler{
+		path:        path,
+		jobs:        jobs,
+		running:     make(map[string]bool),
+		last:        make(map[string]*Run),
+		lastSuccess: make(map[string]time.Time),
+		next:        make(map[string]time.Time),
+		now:         time.Now,
+		after:       time.After,
+	}
+	names := make(map[string]bool)
+	for _, job := range jobs {
+		if job.Name == "" || job.Schedule == nil || job.Func == nil {
+			return nil, errors.New("job must have a name, a schedule and a function")
+		}
+		if names[job.Name] {
+			return nil, fmt.Errorf("duplicate job name %q", job.Name)
+		}
+		names[job.Name] = true
+	}
+
+	runs, err := s.runs()
+	if err != nil {
+		return nil, err
+	}
+	for _, run := range runs {
+		s.update(&run)
+	}
+	return s, nil
+}
+
+// Run runs the jobs at the times of their schedules until ctx is done, then
+// waits for the running jobs, which see ctx done too.
+func (s *Scheduler) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	now := s.now()
+	s.mu.Lock()
+	for _, job := range s.jobs {
+		s.next[job.Name] = job.Schedule.Next(now)
+	}
+	s.mu.Unlock()
+
+	for {
+		s.mu.Lock()
+		var wake time.Time
+		for _, next := range s.next {
+			if !next.IsZero() && (wake.IsZero() || next.Before(wake)) {
+				wake = next
+			}
+		}
+		s.mu.Unlock()
+
+		var timer <-chan time.Time
+		if !wake.IsZero() {
+			timer = s.after(wake.Sub(s.now()))
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-timer:
+		}
+
+		now := s.now()
+		for _, job := range s.jobs {
+			s.mu.Lock()
+			next := s.next[job.Name]
+			due := !next.IsZero() && !next.After(now)
+			if due {
+				s.next[job.Name] = job.Schedule.Next(now)
+			}
+			s.mu.Unlock()
+			if !due {
+				continue
+			}
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				run, err := s.run(ctx, job)
+				s.log(ctx, run, err)
+			}()
+		}
+	}
+}
+
+// log logs a run made by Run
+func (s *Scheduler) log(ctx context.Context, run *Run, err error) {
+	logger := s.Logger
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+	if err != nil {
+		logger.ErrorContext(ctx, "failed to record run", slog.String("job", run.Job), slog.Any("error", err))
+		return
+	}
+	attrs := []any{
+		slog.String("job", run.Job),
+		slog.String("status", string(run.Status)),
+		slog.Duration("duration", run.End.Sub(run.Start)),
+	}
+	if run.Error != "" {
+		logger.WarnContext(ctx, "job run", append(attrs, slog.String("error", run.Error))...)
+		return
+	}
+	logger.InfoContext(ctx, "job run", attrs...)
+}
+
+// RunJob runs the named job now and returns the recorded run. The run is
+// skipped if the job is already running.
+func (s *Scheduler) RunJob(ctx context.Context, name string) (*Run, error) {
+	for _, job := range s.jobs {
+		if job.Name == name {
+			return s.run(ctx, job)
+		}
+	}
+	return nil, fmt.Errorf("job not found: %s", name)
+}
+
+func (s *Scheduler) run(ctx context.Context, job *Job) (*Run, error) {
+	run := &Run{Job: job.Name, Start: s.now()}
+
+	s.mu.Lock()
+	if s.running[job.Name] {
+		s.mu.Unlock()
+		run.End = run.Start
+		run.Status = RunSkipped
+		run.Error = "previous run still in progress"
+		return run, s.record(run)
+	}
+	s.running[job.Name] = true
+	lastSuccess := s.lastSuccess[job.Name]
+	s.mu.Unlock()
+
+	result, err := job.Func(ctx, lastSuccess)
+	run.End = s.now()
+	run.Status = RunSucceeded
+	if err != nil {
+		run.Status = RunFailed
+		run.Error = err.Error()
+	}
+	if result != nil {
+		data, err := json.Marshal(result)
+		if err != nil {
+			run.Status = RunFailed
+			run.Error = fmt.Sprintf("failed to encode result: %v", err)
+		} else if string(data) != "null" {
+			run.Result = data
+		}
+	}
+
+	s.mu.Lock()
+	delete(s.running, job.Name)
+	s.mu.Unlock()
+	return run, s.record(run)
+}
+
+// record appends a run to the history
+func (s *Scheduler) record(run *Run) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.update(run)
+
+	data, err := json.Marshal(run)
+	if err != nil {
+		return fmt.Errorf("failed to encode run: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open history: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	return f.Close()
+}
+
+// update updates the last runs with run. s.mu must be held.
+func (s *Scheduler) update(run *Run) {
+	s.last[run.Job] = run
+	if run.Status == RunSucceeded {
+		s.lastSuccess[run.Job] = run.Start
+	}
+}
+
+// History returns the recorded runs of the named job, or of all jobs if
+// name is empty, most recent first. At most limit runs are returned, or all
+// of them if limit is 0.
+func (s *Scheduler) History(name string, limit int) ([]Run, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	runs, err := s.runs()
+	if err != nil {
+		return nil, err
+	}
+	var history []Run
+	for _, run := range slices.Backward(runs) {
+		if name != "" && run.Job != name {
+			continue
+		}
+		history = append(history, run)
+		if limit > 0 && len(history) == limit {
+			break
+		}
+	}
+	return history, nil
+}
+
+// runs reads the history in the order the runs were recorded
+func (s *Scheduler) runs() ([]Run, error) {
+	f, err := os.Open(s.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open history: %w", err)
+	}
+	defer f.Close()
+
+	var runs []Run
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var run Run
+		if err := json.Unmarshal(scanner.Bytes(), &run); err != nil {
+			return nil, fmt.Errorf("failed to decode history: %w", err)
+		}
+		runs = append(runs, run)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	return runs, nil
+}
+
+// Status returns the status of the jobs in order
+func (s *Scheduler) Status() []JobStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := make([]JobStatus, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		status := JobStatus{
+			Name:     job.Name,
+			Type:     job.Type,
+			Schedule: job.Schedule.String(),
+			Running:  s.running[job.Name],
+			LastRun:  s.last[job.Name],
+		}
+		if next, ok := s.next[job.Name]; ok && !next.IsZero() {
+			status.Next = &next
+		}
+		if t, ok := s.lastSuccess[job.Name]; ok {
+			status.LastSuccess = &t
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// Handler returns an HTTP handler serving the status of the jobs as JSON at
+// GET /status, and the run history at GET /runs. The history can be
+// filtered with ?job=<name> and is limited to the 100 most recent runs
+// unless ?limit=<n> is given.
+func (s *Scheduler) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /status", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, s.Status())
+	})
+	mux.HandleFunc("GET /runs", func(w http.ResponseWriter, r *http.Request) {
+		limit := 100
+		if v := r.URL.Query().Get("limit"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				http.Error(w, "invalid limit", http.StatusBadRequest)
+				return
+			}
+			limit = n
+		}
+		runs, err := s.History(r.URL.Query().Get("job"), limit)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if runs == nil {
+			runs = []Run{}
+		}
+		writeJSON(w, runs)
+	})
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(v)
+}
@@ -0,0 +1,232 @@
+package schedule
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func mustParse(t *testing.T, spec string) *Schedule {
+	t.Helper()
+	s, err := Parse(spec)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	return s
+}
+
+func TestScheduler_RunJob(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	var calls []time.Time
+	fail := false
+	jobs := []*Job{{
+		Name:     "count",
+		Type:     JobExport,
+		Schedule: mustParse(t, "@daily"),
+		Func: func(ctx context.Context, lastSuccess time.Time) (any, error) {
+			calls = append(calls, lastSuccess)
+			if fail {
+				return nil, errors.New("boom")
+			}
+			return map[string]int{"documents": len(calls)}, nil
+		},
+	}}
+	s, err := New(path, jobs)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	run, err := s.RunJob(context.Background(), "count")
+	if err != nil {
+		t.Fatalf("RunJob() error = %v", err)
+	}
+	if run.Status != RunSucceeded || string(run.Result) != `{"documents":1}` || !calls[0].IsZero() {
+		t.Errorf("RunJob() = %+v, lastSuccess = %v", run, calls[0])
+	}
+	fail = true
+	run, _ = s.RunJob(context.Background(), "count")
+	if run.Status != RunFailed || run.Error != "boom" || run.Result != nil {
+		t.Errorf("RunJob() = %+v, want failed", run)
+	}
+	if _, err := s.RunJob(context.Background(), "missing"); err == nil {
+		t.Error("RunJob() error = nil, want error for an unknown job")
+	}
+
+	// The last successful run is read back from the history
+	s, err = New(path, jobs)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	fail = false
+	run, _ = s.RunJob(context.Background(), "count")
+	if first, _ := s.History("count", 0); len(first) != 3 || !calls[2].Equal(first[2].Start) {
+		t.Errorf("lastSuccess = %v, history = %+v", calls[2], first)
+	}
+	status := s.Status()
+	if len(status) != 1 || status[0].LastRun == nil || status[0].LastRun.Start != run.Start || status[0].Schedule != "@daily" {
+		t.Errorf("Status() = %+v", status)
+	}
+
+	history, err := s.History("", 2)
+	if err != nil {
+		t.Fatalf("History() error = %v", err)
+	}
+	if len(history) != 2 || history[0].Status != RunSucceeded || history[1].Status != RunFailed {
+		t.Errorf("History() = %+v, want the 2 most recent runs", history)
+	}
+}
+
+func TestScheduler_RunJob_Overlap(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	s, err := New(filepath.Join(t.TempDir(), "history.jsonl"), []*Job{{
+		Name:     "slow",
+		Schedule: mustParse(t, "* * * * *"),
+		Func: func(ctx context.Context, lastSuccess time.Time) (any, error) {
+			close(started)
+			<-release
+			return nil, nil
+		},
+	}})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.RunJob(context.Background(), "slow")
+	}()
+	<-started
+	if status := s.Status(); !status[0].Running {
+		t.Errorf("Status() = %+v, want running", status)
+	}
+	run, err := s.RunJob(context.Background(), "slow")
+	if err != nil || run.Status != RunSkipped {
+		t.Errorf("RunJob() = %+v, %v, want skipped", run, err)
+	}
+	close(release)
+	wg.Wait()
+
+	history, _ := s.History("slow", 0)
+	if len(history) != 2 || history[0].Status != RunSucceeded || history[1].Status != RunSkipped {
+		t.Errorf("History() = %+v", history)
+	}
+}
+
+func TestScheduler_Run(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// A clock that jumps to the first time waited for, then waits forever
+	var mu sync.Mutex
+	clock := time.Date(2025, 7, 16, 10, 30, 15, 0, time.UTC)
+	waited := false
+	now := func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return clock
+	}
+	after := func(d time.Duration) <-chan time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		if waited {
+			return nil
+		}
+		waited = true
+		clock = clock.Add(d)
+		ch := make(chan time.Time, 1)
+		ch <- clock
+		return ch
+	}
+
+	var runs []time.Time
+	s, err := New(filepath.Join(t.TempDir(), "history.jsonl"), []*Job{{
+		Name:     "hourly",
+		Schedule: mustParse(t, "@hourly"),
+		Func: func(ctx context.Context, lastSuccess time.Time) (any, error) {
+			runs = append(runs, now())
+			cancel()
+			return nil, nil
+		},
+	}})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	s.now, s.after = now, after
+
+	if err := s.Run(ctx); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(runs) != 1 || !runs[0].Equal(time.Date(2025, 7, 16, 11, 0, 0, 0, time.UTC)) {
+		t.Errorf("runs = %v, want one at 11:00", runs)
+	}
+}
+
+func TestScheduler_Handler(t *testing.T) {
+	s, err := New(filepath.Join(t.TempDir(), "history.jsonl"), []*Job{{
+		Name:     "archive",
+		Type:     JobMove,
+		Schedule: mustParse(t, "0 3 * * *"),
+		Func: func(ctx context.Context, lastSuccess time.Time) (any, error) {
+			return &MoveResult{Moved: []string{"doc1"}}, nil
+		},
+	}})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	s.RunJob(context.Background(), "archive")
+	server := httptest.NewServer(s.Handler())
+	defer server.Close()
+
+	var status []JobStatus
+	getJSON(t, server.URL+"/status", &status)
+	if len(status) != 1 || status[0].Name != "archive" || status[0].Type != JobMove || status[0].LastSuccess == nil {
+		t.Errorf("GET /status = %+v", status)
+	}
+
+	var runs []Run
+	getJSON(t, server.URL+"/runs?job=archive&limit=1", &runs)
+	if len(runs) != 1 {
+		t.Fatalf("GET /runs = %+v, want 1 run", runs)
+	}
+	var result MoveResult
+	if err := json.Unmarshal(runs[0].Result, &result); err != nil || len(result.Moved) != 1 || result.Moved[0] != "doc1" {
+		t.Errorf("result = %s, want the move result", runs[0].Result)
+	}
+	getJSON(t, server.URL+"/runs?job=other", &runs)
+	if len(runs) != 0 {
+		t.Errorf("GET /runs?job=other = %+v, want none", runs)
+	}
+
+	resp, err := http.Get(server.URL + "/runs?limit=x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+}
+
+func getJSON(t *testing.T, url string, v any) {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET %s status = %d", url, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		t.Fatalf("failed to decode %s: %v", url, err)
+	}
+}
@@ -0,0 +1,410 @@
+// Package stats computes reading statistics over Readwise Reader documents.
+//
+// A document counts as finished once it is in the archive location, and it is
+// treated as finished at its LastMovedAt time.
+package stats
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	reader "github.com/tcnksm/go-readwise-reader"
+)
+
+const (
+	// DefaultWordsPerMinute is the reading speed used to estimate reading time
+	DefaultWordsPerMinute = 238
+
+	// DefaultTop is the number of entries in the top sites and authors lists
+	DefaultTop = 10
+)
+
+// Options holds options for computing statistics
+type Options struct {
+	// Since excludes documents saved before this time (optional)
+	Since time.Time
+
+	// Until excludes documents saved after this time (optional)
+	Until time.Time
+
+	// WordsPerMinute is the reading speed used to estimate reading time.
+	// Defaults to DefaultWordsPerMinute.
+	WordsPerMinute int
+
+	// Top is the number of entries in the top sites and authors lists.
+	// Defaults to DefaultTop.
+	Top int
+}
+
+// Report holds the statistics computed from a set of documents
+type Report struct {
+	// Documents is the number of documents the report covers
+	Documents int `json:"documents"`
+
+	// Weekly is the number of documents saved and finished per week
+	Weekly []Week `json:"weekly"`
+
+	// WordsRead summarizes the word count of finished documents
+	WordsRead WordCount `json:"words_read"`
+
+	// Backlog is the estimated reading time left per location
+	Backlog []Backlog `json:"backlog"`
+
+	// TopSitesBySaves are the sites with the most saved documents
+	TopSitesBySaves []Source `json:"top_sites_by_saves"`
+
+	// TopSitesByCompletion are the sites with the highest completion rate
+	TopSitesByCompletion []Source `json:"top_sites_by_completion"`
+
+	// TopAuthorsBySaves are the authors with the most saved documents
+	TopAuthorsBySaves []Source `json:"top_authors_by_saves"`
+
+	// TopAuthorsByCompletion are the authors with the highest completion rate
+	TopAuthorsByCompletion []Source `json:"top_authors_by_completion"`
+
+	// TimeToArchive summarizes how long documents sat before being archived
+	TimeToArchive Durations `json:"time_to_archive"`
+
+	// Progress is the distribution of reading progress
+	Progress []ProgressBucket `json:"progress"`
+}
+
+// Week holds the number of documents saved and finished in a week
+type Week struct {
+	// Start is the Monday the week starts on (UTC)
+	Start time.Time `json:"start"`
+
+	// Saved is the number of documents saved in the week
+	Saved int `json:"saved"`
+
+	// Finished is the number of documents finished in the week
+	Finished int `json:"finished"`
+}
+
+// WordCount summarizes the word count of a set of documents
+type WordCount struct {
+	// Documents is the number of documents
+	Documents int `json:"documents"`
+
+	// Total is the total number of words
+	Total int `json:"total"`
+
+	// Median is the median number of words per document
+	Median float64 `json:"median"`
+}
+
+// Backlog holds the unread documents of a location
+type Backlog struct {
+	// Location is the document location
+	Location reader.Location `json:"location"`
+
+	// Documents is the number of documents in the location
+	Documents int `json:"documents"`
+
+	// Words is the number of words left to read
+	Words int `json:"words"`
+
+	// ReadingMinutes is the estimated reading time left in minutes
+	ReadingMinutes float64 `json:"reading_minutes"`
+}
+
+// Source holds the statistics of a site or an author
+type Source struct {
+	// Name is the site name or author
+	Name string `json:"name"`
+
+	// Saved is the number of saved documents
+	Saved int `json:"saved"`
+
+	// Finished is the number of finished documents
+	Finished int `json:"finished"`
+
+	// CompletionRate is Finished divided by Saved
+	CompletionRate float64 `json:"completion_rate"`
+}
+
+// Durations summarizes a set of durations in days
+type Durations struct {
+	// Count is the number of durations
+	Count int `json:"count"`
+
+	// MeanDays is the mean duration in days
+	MeanDays float64 `json:"mean_days"`
+
+	// MedianDays is the median duration in days
+	MedianDays float64 `json:"median_days"`
+
+	// P90Days is the 90th percentile duration in days
+	P90Days float64 `json:"p90_days"`
+}
+
+// ProgressBucket is a bucket of the reading progress distribution
+type ProgressBucket struct {
+	// Label describes the bucket range
+	Label string `json:"label"`
+
+	// Count is the number of documents in the bucket
+	Count int `json:"count"`
+}
+
+// progressBuckets are the reading progress distribution buckets in order.
+// Each bucket holds progress values up to and excluding its upper bound.
+var progressBuckets = []struct {
+	label string
+	upper float64
+}{
+	{"0%", 0},
+	{"1-24%", 25},
+	{"25-49%", 50},
+	{"50-74%", 75},
+	{"75-99%", 100},
+	{"100%", math.Inf(1)},
+}
+
+// Compute computes statistics over documents. Highlights are ignored since
+// they are not read on their own.
+func Compute(documents []reader.Document, opts *Options) *Report {
+	if opts == nil {
+		opts = &Options{}
+	}
+	wpm := opts.WordsPerMinute
+	if wpm <= 0 {
+		wpm = DefaultWordsPerMinute
+	}
+	top := opts.Top
+	if top <= 0 {
+		top = DefaultTop
+	}
+
+	report := &Report{
+		Progress: make([]ProgressBucket, len(progressBuckets)),
+	}
+	for i, b := range progressBuckets {
+		report.Progress[i].Label = b.label
+	}
+
+	weeks := make(map[time.Time]*Week)
+	backlog := make(map[reader.Location]*Backlog)
+	sites := make(map[string]*Source)
+	authors := make(map[string]*Source)
+	var wordsRead []int
+	var toArchive []time.Duration
+
+	for _, doc := range documents {
+		if doc.IsHighlight() || !inWindow(doc, opts) {
+			continue
+		}
+		report.Documents++
+		finished := IsFinished(doc)
+
+		// Weekly saved and finished
+		if doc.SavedAt != nil {
+			weekOf(weeks, *doc.SavedAt).Saved++
+		}
+		if finished && doc.LastMovedAt != nil {
+			weekOf(weeks, *doc.LastMovedAt).Finished++
+		}
+
+		// Words read and reading backlog
+		if finished {
+			wordsRead = append(wordsRead, doc.WordCount)
+		} else {
+			b, ok := backlog[doc.Location]
+			if !ok {
+				b = &Backlog{Location: doc.Location}
+				backlog[doc.Location] = b
+			}
+			b.Documents++
+			b.Words += int(float64(doc.WordCount) * (1 - clampProgress(doc.ReadingProgressPercent)/100))
+		}
+
+		// Sites and authors
+		countSource(sites, doc.SiteName, finished)
+		countSource(authors, doc.Author, finished)
+
+		// Time before archiving
+		if finished && doc.SavedAt != nil && doc.LastMovedAt != nil && doc.LastMovedAt.After(*doc.SavedAt) {
+			toArchive = append(toArchive, doc.LastMovedAt.Sub(*doc.SavedAt))
+		}
+
+		// Progress distribution
+		progress := clampProgress(doc.ReadingProgressPercent)
+		for i, b := range progressBuckets {
+			if progress < b.upper || (b.upper == 0 && progress == 0) {
+				report.Progress[i].Count++
+				break
+			}
+		}
+	}
+
+	for _, w := range weeks {
+		report.Weekly = append(report.Weekly, *w)
+	}
+	sort.Slice(report.Weekly, func(i, j int) bool {
+		return report.Weekly[i].Start.Before(report.Weekly[j].Start)
+	})
+
+	report.WordsRead = WordCount{
+		Documents: len(wordsRead),
+		Total:     sum(wordsRead),
+		Median:    median(wordsRead),
+	}
+
+	for _, b := range backlog {
+		b.ReadingMinutes = float64(b.Words) / float64(wpm)
+		report.Backlog = append(report.Backlog, *b)
+	}
+	sort.Slice(report.Backlog, func(i, j int) bool {
+		return report.Backlog[i].Location < report.Backlog[j].Location
+	})
+
+	report.TopSitesBySaves, report.TopSitesByCompletion = topSources(sites, top)
+	report.TopAuthorsBySaves, report.TopAuthorsByCompletion = topSources(authors, top)
+	report.TimeToArchive = summarizeDurations(toArchive)
+
+	return report
+}
+
+// IsFinished reports whether a document has been finished
+func IsFinished(doc reader.Document) bool {
+	return doc.Location == reader.LocationArchive
+}
+
+func inWindow(doc reader.Document, opts *Options) bool {
+	if opts.Since.IsZero() && opts.Until.IsZero() {
+		return true
+	}
+	if doc.SavedAt == nil {
+		return false
+	}
+	if !opts.Since.IsZero() && doc.SavedAt.Before(opts.Since) {
+		return false
+	}
+	if !opts.Until.IsZero() && doc.SavedAt.After(opts.Until) {
+		return false
+	}
+	return true
+}
+
+// weekOf returns the week t falls in, adding it to weeks if needed
+func weekOf(weeks map[time.Time]*Week, t time.Time) *Week {
+	t = t.UTC()
+	daysSinceMonday := (int(t.Weekday()) + 6) % 7
+	start := time.Date(t.Year(), t.Month(), t.Day()-daysSinceMonday, 0, 0, 0, 0, time.UTC)
+
+	w, ok := weeks[start]
+	if !ok {
+		w = &Week{Start: start}
+		weeks[start] = w
+	}
+	return w
+}
+
+func countSource(sources map[string]*Source, name string, finished bool) {
+	if name == "" {
+		return
+	}
+	s, ok := sources[name]
+	if !ok {
+		s = &Source{Name: name}
+		sources[name] = s
+	}
+	s.Saved++
+	if finished {
+		s.Finished++
+	}
+	s.CompletionRate = float64(s.Finished) / float64(s.Saved)
+}
+
+// topSources returns the top n sources by saves and by completion rate.
+// Ties are broken by the other measure, then by name.
+func topSources(sources map[string]*Source, n int) (bySaves, byCompletion []Source) {
+	all := make([]Source, 0, len(sources))
+	for _, s := range sources {
+		all = append(all, *s)
+	}
+
+	bySaves = append([]Source(nil), all...)
+	sort.Slice(bySaves, func(i, j int) bool {
+		a, b := bySaves[i], bySaves[j]
+		if a.Saved != b.Saved {
+			return a.Saved > b.Saved
+		}
+		if a.CompletionRate != b.CompletionRate {
+			return a.CompletionRate > b.CompletionRate
+		}
+		return a.Name < b.Name
+	})
+
+	byCompletion = append([]Source(nil), all...)
+	sort.Slice(byCompletion, func(i, j int) bool {
+		a, b := byCompletion[i], byCompletion[j]
+		if a.CompletionRate != b.CompletionRate {
+			return a.CompletionRate > b.CompletionRate
+		}
+		if a.Saved != b.Saved {
+			return a.Saved > b.Saved
+		}
+		return a.Name < b.Name
+	})
+
+	if len(all) > n {
+		bySaves = bySaves[:n]
+		byCompletion = byCompletion[:n]
+	}
+	return bySaves, byCompletion
+}
+
+func summarizeDurations(durations []time.Duration) Durations {
+	if len(durations) == 0 {
+		return Durations{}
+	}
+
+	days := make([]float64, len(durations))
+	var total float64
+	for i, d := range durations {
+		days[i] = d.Hours() / 24
+		total += days[i]
+	}
+	sort.Float64s(days)
+
+	return Durations{
+		Count:      len(days),
+		MeanDays:   total / float64(len(days)),
+		MedianDays: percentile(days, 50),
+		P90Days:    percentile(days, 90),
+	}
+}
+
+// percentile returns the p-th percentile of sorted values using linear interpolation
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
+
+func median(values []int) float64 {
+	sorted := make([]float64, len(values))
+	for i, v := range values {
+		sorted[i] = float64(v)
+	}
+	sort.Float64s(sorted)
+	return percentile(sorted, 50)
+}
+
+func sum(values []int) int {
+	total := 0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
+
+func clampProgress(progress float64) float64 {
+	return math.Max(0, math.Min(100, progress))
+}
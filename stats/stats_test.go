@@ -0,0 +1,178 @@
+package stats
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	reader "github.com/tcnksm/go-readwise-reader"
+)
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+func day(d int) *time.Time {
+	// 2024-01-01 is a Monday
+	return timePtr(time.Date(2024, 1, d, 12, 0, 0, 0, time.UTC))
+}
+
+func testDocuments() []reader.Document {
+	return []reader.Document{
+		{
+			ID:                     "doc1",
+			Location:               reader.LocationArchive,
+			SiteName:               "Go Blog",
+			Author:                 "Alice",
+			WordCount:              1000,
+			ReadingProgressPercent: 100,
+			SavedAt:                day(1),
+			LastMovedAt:            day(3),
+		},
+		{
+			ID:                     "doc2",
+			Location:               reader.LocationArchive,
+			SiteName:               "Go Blog",
+			Author:                 "Bob",
+			WordCount:              3000,
+			ReadingProgressPercent: 100,
+			SavedAt:                day(2),
+			LastMovedAt:            day(10),
+		},
+		{
+			ID:                     "doc3",
+			Location:               reader.LocationLater,
+			SiteName:               "Go Blog",
+			Author:                 "Alice",
+			WordCount:              2380,
+			ReadingProgressPercent: 50,
+			SavedAt:                day(9),
+		},
+		{
+			ID:        "doc4",
+			Location:  reader.LocationNew,
+			SiteName:  "News",
+			WordCount: 476,
+			SavedAt:   day(10),
+		},
+		{
+			ID:       "highlight1",
+			Category: reader.CategoryHighlight,
+			SavedAt:  day(10),
+		},
+		{
+			ID:        "note1",
+			Category:  reader.CategoryNote,
+			Location:  reader.LocationNew,
+			SiteName:  "News",
+			WordCount: 30,
+			SavedAt:   day(10),
+		},
+	}
+}
+
+func TestCompute(t *testing.T) {
+	report := Compute(testDocuments(), nil)
+
+	if report.Documents != 4 {
+		t.Errorf("Documents = %d, want 4", report.Documents)
+	}
+
+	wantWeekly := []Week{
+		{Start: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Saved: 2, Finished: 1},
+		{Start: time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC), Saved: 2, Finished: 1},
+	}
+	if !reflect.DeepEqual(report.Weekly, wantWeekly) {
+		t.Errorf("Weekly = %+v, want %+v", report.Weekly, wantWeekly)
+	}
+
+	wantWords := WordCount{Documents: 2, Total: 4000, Median: 2000}
+	if report.WordsRead != wantWords {
+		t.Errorf("WordsRead = %+v, want %+v", report.WordsRead, wantWords)
+	}
+
+	wantBacklog := []Backlog{
+		{Location: reader.LocationLater, Documents: 1, Words: 1190, ReadingMinutes: 5},
+		{Location: reader.LocationNew, Documents: 1, Words: 476, ReadingMinutes: 2},
+	}
+	if !reflect.DeepEqual(report.Backlog, wantBacklog) {
+		t.Errorf("Backlog = %+v, want %+v", report.Backlog, wantBacklog)
+	}
+
+	if got := report.TopSitesBySaves[0]; got.Name != "Go Blog" || got.Saved != 3 || got.Finished != 2 {
+		t.Errorf("TopSitesBySaves[0] = %+v", got)
+	}
+	if got := report.TopAuthorsByCompletion[0]; got.Name != "Bob" || got.CompletionRate != 1 {
+		t.Errorf("TopAuthorsByCompletion[0] = %+v", got)
+	}
+	if got := report.TopAuthorsBySaves[0]; got.Name != "Alice" || got.CompletionRate != 0.5 {
+		t.Errorf("TopAuthorsBySaves[0] = %+v", got)
+	}
+
+	wantArchive := Durations{Count: 2, MeanDays: 5, MedianDays: 5, P90Days: 7.4}
+	if report.TimeToArchive.Count != wantArchive.Count ||
+		report.TimeToArchive.MeanDays != wantArchive.MeanDays ||
+		report.TimeToArchive.MedianDays != wantArchive.MedianDays ||
+		math.Abs(report.TimeToArchive.P90Days-wantArchive.P90Days) > 1e-9 {
+		t.Errorf("TimeToArchive = %+v, want %+v", report.TimeToArchive, wantArchive)
+	}
+
+	wantProgress := map[string]int{"0%": 1, "50-74%": 1, "100%": 2}
+	for _, b := range report.Progress {
+		if b.Count != wantProgress[b.Label] {
+			t.Errorf("Progress[%s] = %d, want %d", b.Label, b.Count, wantProgress[b.Label])
+		}
+	}
+}
+
+func TestCompute_Window(t *testing.T) {
+	report := Compute(testDocuments(), &Options{
+		Since: *day(8),
+		Top:   1,
+	})
+
+	if report.Documents != 2 {
+		t.Errorf("Documents = %d, want 2", report.Documents)
+	}
+	if report.WordsRead.Documents != 0 {
+		t.Errorf("WordsRead.Documents = %d, want 0", report.WordsRead.Documents)
+	}
+	if len(report.TopSitesBySaves) != 1 {
+		t.Errorf("len(TopSitesBySaves) = %d, want 1", len(report.TopSitesBySaves))
+	}
+}
+
+func TestCompute_Empty(t *testing.T) {
+	report := Compute(nil, nil)
+
+	if report.Documents != 0 {
+		t.Errorf("Documents = %d, want 0", report.Documents)
+	}
+	if report.WordsRead != (WordCount{}) {
+		t.Errorf("WordsRead = %+v, want zero", report.WordsRead)
+	}
+	if report.TimeToArchive != (Durations{}) {
+		t.Errorf("TimeToArchive = %+v, want zero", report.TimeToArchive)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	tests := []struct {
+		values []float64
+		p      float64
+		want   float64
+	}{
+		{[]float64{1}, 50, 1},
+		{[]float64{1, 2, 3}, 50, 2},
+		{[]float64{1, 2, 3, 4}, 50, 2.5},
+		{[]float64{0, 10}, 90, 9},
+		{nil, 50, 0},
+	}
+
+	for _, tt := range tests {
+		if got := percentile(tt.values, tt.p); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("percentile(%v, %v) = %v, want %v", tt.values, tt.p, got, tt.want)
+		}
+	}
+}
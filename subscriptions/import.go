@@ -0,0 +1,227 @@
+package subscriptions
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sort"
+	"time"
+
+	reader "github.com/tcnksm/go-readwise-reader"
+	"github.com/tcnksm/go-readwise-reader/markdown"
+)
+
+const (
+	// DefaultMaxEntries is the default number of entries saved from a feed
+	// per poll
+	DefaultMaxEntries = 10
+
+	// maxFeedSize is the largest feed document that is read
+	maxFeedSize = 10 << 20
+)
+
+// Importer saves the new entries of subscribed feeds as feed documents
+type Importer struct {
+	// Client saves the entries
+	Client reader.Client
+
+	// HTTPClient fetches the feeds. http.DefaultClient is used if nil.
+	HTTPClient *http.Client
+
+	// State tracks the entries seen in each feed. Polled feeds are added to
+	// it, and it must be saved by the caller.
+	State *State
+
+	// MaxEntries is the number of most recent new entries saved from a feed
+	// per poll, so that the first poll of a feed does not save its whole
+	// history. DefaultMaxEntries is used if 0.
+	MaxEntries int
+
+	// DryRun fetches the feeds and reports the entries that would be saved
+	// without saving them or changing the state
+	DryRun bool
+}
+
+// Result is the outcome of polling a feed
+type Result struct {
+	// Feed is the URL of the feed
+	Feed string `json:"feed"`
+
+	// Title is the title of the subscription
+	Title string `json:"title"`
+
+	// NotModified is true if the feed did not change since the last poll
+	NotModified bool `json:"not_modified,omitempty"`
+
+	// Saved are the entries saved, or that would be saved in a dry run
+	Saved []Entry `json:"saved,omitempty"`
+
+	// Error is the error that stopped polling the feed, if any
+	Error string `json:"error,omitempty"`
+}
+
+// Poll polls each subscription in order and returns the results. A feed
+// that fails does not stop the others. Entries are saved oldest first, and
+// entries that failed to be saved are tried again on the next poll.
+func (im *Importer) Poll(ctx context.Context, subs []Subscription) []Result {
+	im.State.Subscribe(subs...)
+	results := make([]Result, 0, len(subs))
+	for _, sub := range subs {
+		result := Result{Feed: sub.URL, Title: sub.Title}
+		if err := im.poll(ctx, sub, &result); err != nil {
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+func (im *Importer) poll(ctx context.Context, sub Subscription, result *Result) error {
+	state := *im.State.Feeds[sub.URL]
+
+	entries, notModified, err := im.fetch(ctx, sub.URL, &state)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	state.LastPolled = &now
+	if notModified {
+		result.NotModified = true
+		if !im.DryRun {
+			*im.State.Feeds[sub.URL] = state
+		}
+		return nil
+	}
+
+	var ids []string
+	var fresh []Entry
+	for _, e := range entries {
+		ids = append(ids, e.ID)
+		if !slices.Contains(state.Seen, e.ID) && e.URL != "" {
+			fresh = append(fresh, e)
+		}
+	}
+	// Keep the most recent entries and save them oldest first
+	sort.SliceStable(fresh, func(i, j int) bool {
+		return fresh[i].Published.After(fresh[j].Published)
+	})
+	maxEntries := im.MaxEntries
+	if maxEntries <= 0 {
+		maxEntries = DefaultMaxEntries
+	}
+	if len(fresh) > maxEntries {
+		fresh = fresh[:maxEntries]
+	}
+	slices.Reverse(fresh)
+
+	if im.DryRun {
+		result.Saved = fresh
+		return nil
+	}
+
+	for _, e := range fresh {
+		if _, err := im.Client.CreateDocument(ctx, e.URL, createRequest(e)); err != nil {
+			// Keep the validators of the previous fetch so that the feed is
+			// fetched again, and only remember the entries saved so far
+			prev := im.State.Feeds[sub.URL]
+			prev.Seen = seen(entryIDs(result.Saved), prev.Seen, 0)
+			return fmt.Errorf("failed to save %s: %w", e.URL, err)
+		}
+		result.Saved = append(result.Saved, e)
+	}
+
+	// Entries in the feed that were not saved are older than the saved
+	// ones and are skipped for good
+	state.Seen = seen(ids, state.Seen, len(ids))
+	if len(ids) > 0 {
+		state.LastGUID = ids[0]
+	}
+	*im.State.Feeds[sub.URL] = state
+	return nil
+}
+
+// fetch fetches and parses a feed, sending the validators in state. It
+// updates the validators in state and reports whether the feed was not
+// modified.
+func (im *Importer) fetch(ctx context.Context, url string, state *FeedState) ([]Entry, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/xml;q=0.9, text/xml;q=0.8, */*;q=0.5")
+	if state.ETag != "" {
+		req.Header.Set("If-None-Match", state.ETag)
+	}
+	if state.LastModified != "" {
+		req.Header.Set("If-Modified-Since", state.LastModified)
+	}
+
+	httpClient := im.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to fetch feed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, true, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, false, fmt.Errorf("failed to fetch feed: unexpected status %d", resp.StatusCode)
+	}
+
+	entries, err := ParseFeed(io.LimitReader(resp.Body, maxFeedSize))
+	if err != nil {
+		return nil, false, err
+	}
+	state.ETag = resp.Header.Get("ETag")
+	state.LastModified = resp.Header.Get("Last-Modified")
+	return entries, false, nil
+}
+
+// createRequest returns the request that saves an entry as a feed document.
+// The content of the feed is saved if it has any, otherwise Reader fetches
+// the page.
+func createRequest(e Entry) *reader.CreateDocumentRequest {
+	req := &reader.CreateDocumentRequest{
+		HTML:     e.Content,
+		Title:    e.Title,
+		Author:   e.Author,
+		Summary:  markdown.PlainText(e.Summary),
+		Location: reader.LocationFeed,
+		Category: reader.CategoryRSS,
+	}
+	if !e.Published.IsZero() {
+		published := e.Published
+		req.PublishedDate = &published
+	}
+	return req
+}
+
+// seen returns ids followed by the previously seen IDs not among them,
+// keeping at least keep and at most keep+maxSeen IDs
+func seen(ids, prev []string, keep int) []string {
+	all := slices.Clone(ids)
+	for _, id := range prev {
+		if !slices.Contains(all, id) {
+			all = append(all, id)
+		}
+	}
+	if limit := keep + maxSeen; len(all) > limit {
+		all = all[:limit]
+	}
+	return all
+}
+
+func entryIDs(entries []Entry) []string {
+	ids := make([]string, 0, len(entries))
+	for _, e := range entries {
+		ids = append(ids, e.ID)
+	}
+	return ids
+}
@@ -0,0 +1,163 @@
+package subscriptions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	reader "github.com/tcnksm/go-readwise-reader"
+)
+
+// saveClient is a reader.Client that records created documents
+type saveClient struct {
+	reader.Client
+	mu      sync.Mutex
+	saved   []string
+	reqs    []*reader.CreateDocumentRequest
+	failURL string
+}
+
+func (c *saveClient) CreateDocument(ctx context.Context, url string, req *reader.CreateDocumentRequest) (*reader.CreateDocumentResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if url == c.failURL {
+		return nil, errors.New("rate limited")
+	}
+	c.saved = append(c.saved, url)
+	c.reqs = append(c.reqs, req)
+	return &reader.CreateDocumentResponse{ID: fmt.Sprint(len(c.saved)), URL: url}, nil
+}
+
+// feedServer serves an RSS feed of the given items with an ETag
+type feedServer struct {
+	mu    sync.Mutex
+	items []string
+}
+
+func (s *feedServer) set(items ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.items = items
+}
+
+func (s *feedServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	etag := `"` + strings.Join(s.items, "-") + `"`
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Content-Type", "application/rss+xml")
+	fmt.Fprint(w, `<?xml version="1.0"?><rss version="2.0"><channel><title>Test</title>`)
+	// Newest first, one day apart
+	for i, item := range s.items {
+		published := time.Date(2025, 7, 20-i, 0, 0, 0, 0, time.UTC).Format(time.RFC1123Z)
+		fmt.Fprintf(w, `<item><title>%s</title><link>https://example.com/%s</link><guid>%s</guid><pubDate>%s</pubDate></item>`, item, item, item, published)
+	}
+	fmt.Fprint(w, `</channel></rss>`)
+}
+
+func TestImporter_Poll(t *testing.T) {
+	feed := &feedServer{}
+	feed.set("e5", "e4", "e3", "e2", "e1")
+	server := httptest.NewServer(feed)
+	defer server.Close()
+
+	statePath := filepath.Join(t.TempDir(), "feeds.json")
+	state, err := LoadState(statePath)
+	if err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+	client := &saveClient{}
+	im := &Importer{Client: client, HTTPClient: server.Client(), State: state, MaxEntries: 3}
+	subs := []Subscription{{Title: "Test", URL: server.URL + "/feed.xml"}}
+
+	// The first poll saves the most recent entries, oldest first
+	results := im.Poll(context.Background(), subs)
+	if len(results) != 1 || results[0].Error != "" || len(results[0].Saved) != 3 {
+		t.Fatalf("Poll() = %+v", results)
+	}
+	if got := strings.Join(client.saved, ","); got != "https://example.com/e3,https://example.com/e4,https://example.com/e5" {
+		t.Errorf("saved = %s", got)
+	}
+	req := client.reqs[0]
+	if req.Location != reader.LocationFeed || req.Category != reader.CategoryRSS || req.Title != "e3" || req.PublishedDate == nil {
+		t.Errorf("CreateDocument request = %+v", req)
+	}
+	if err := state.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// An unchanged feed is not fetched again
+	state, _ = LoadState(statePath)
+	im.State = state
+	if got := state.Feeds[subs[0].URL]; got.ETag == "" || got.LastGUID != "e5" || got.LastPolled == nil {
+		t.Errorf("feed state = %+v", got)
+	}
+	results = im.Poll(context.Background(), state.Subscriptions())
+	if !results[0].NotModified || len(client.saved) != 3 {
+		t.Errorf("Poll() = %+v, want not modified", results)
+	}
+
+	// Only new entries are saved; e1 and e2 were skipped for good
+	feed.set("e7", "e6", "e5", "e4", "e3", "e2")
+	client.failURL = "https://example.com/e7"
+	results = im.Poll(context.Background(), subs)
+	if results[0].Error == "" || len(results[0].Saved) != 1 || results[0].Saved[0].ID != "e6" {
+		t.Fatalf("Poll() = %+v, want e6 saved and e7 failed", results)
+	}
+
+	// The failed entry is saved on the next poll, and e6 is not saved twice
+	client.failURL = ""
+	results = im.Poll(context.Background(), subs)
+	if results[0].Error != "" || len(results[0].Saved) != 1 || results[0].Saved[0].ID != "e7" {
+		t.Fatalf("Poll() = %+v, want e7 saved", results)
+	}
+	if got := strings.Join(client.saved, ","); !strings.HasSuffix(got, "e5,https://example.com/e6,https://example.com/e7") {
+		t.Errorf("saved = %s", got)
+	}
+}
+
+func TestImporter_Poll_DryRun(t *testing.T) {
+	feed := &feedServer{}
+	feed.set("e2", "e1")
+	server := httptest.NewServer(feed)
+	defer server.Close()
+
+	state, _ := LoadState(filepath.Join(t.TempDir(), "feeds.json"))
+	client := &saveClient{}
+	im := &Importer{Client: client, HTTPClient: server.Client(), State: state, DryRun: true}
+	subs := []Subscription{{Title: "Test", URL: server.URL}}
+
+	results := im.Poll(context.Background(), subs)
+	if len(results[0].Saved) != 2 || len(client.saved) != 0 {
+		t.Errorf("Poll() = %+v, saved %v, want 2 entries and nothing saved", results, client.saved)
+	}
+	if got := state.Feeds[server.URL]; got.ETag != "" || len(got.Seen) != 0 {
+		t.Errorf("feed state = %+v, want unchanged", got)
+	}
+}
+
+func TestImporter_Poll_Error(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	state, _ := LoadState(filepath.Join(t.TempDir(), "feeds.json"))
+	im := &Importer{Client: &saveClient{}, HTTPClient: server.Client(), State: state}
+	results := im.Poll(context.Background(), []Subscription{
+		{Title: "Missing", URL: server.URL + "/missing"},
+		{Title: "Invalid", URL: "://invalid"},
+	})
+	if len(results) != 2 || !strings.Contains(results[0].Error, "unexpected status 404") || results[1].Error == "" {
+		t.Errorf("Poll() = %+v, want an error per feed", results)
+	}
+}
@@ -0,0 +1,68 @@
+// Package subscriptions imports RSS and Atom feed subscriptions into
+// Readwise Reader as feed documents.
+//
+// ParseOPML reads the subscriptions exported by a feed reader. An Importer
+// fetches each feed, saves its new entries with Location feed and Category
+// rss, and keeps the ETag and the GUIDs of seen entries of every feed in a
+// local State, so that polling again only saves entries that are new.
+package subscriptions
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Subscription is a feed to import
+type Subscription struct {
+	// Title is the title of the feed
+	Title string `json:"title"`
+
+	// URL is the URL of the feed document
+	URL string `json:"url"`
+}
+
+type opml struct {
+	Body struct {
+		Outlines []outline `xml:"outline"`
+	} `xml:"body"`
+}
+
+type outline struct {
+	Text     string    `xml:"text,attr"`
+	Title    string    `xml:"title,attr"`
+	XMLURL   string    `xml:"xmlUrl,attr"`
+	Outlines []outline `xml:"outline"`
+}
+
+// ParseOPML returns the feeds in an OPML document, including those nested
+// in folders. Feeds listed more than once are returned once.
+func ParseOPML(r io.Reader) ([]Subscription, error) {
+	var doc opml
+	dec := xml.NewDecoder(r)
+	dec.CharsetReader = charsetReader
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to parse OPML: %w", err)
+	}
+
+	var subs []Subscription
+	seen := make(map[string]bool)
+	var walk func([]outline)
+	walk = func(outlines []outline) {
+		for _, o := range outlines {
+			url := strings.TrimSpace(o.XMLURL)
+			if url != "" && !seen[url] {
+				seen[url] = true
+				title := o.Title
+				if title == "" {
+					title = o.Text
+				}
+				subs = append(subs, Subscription{Title: title, URL: url})
+			}
+			walk(o.Outlines)
+		}
+	}
+	walk(doc.Body.Outlines)
+	return subs, nil
+}
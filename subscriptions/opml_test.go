@@ -0,0 +1,42 @@
+package subscriptions
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseOPML(t *testing.T) {
+	subs, err := ParseOPML(strings.NewReader(`<?xml version="1.0" encoding="UTF-8"?>
+<opml version="2.0">
+  <head><title>Subscriptions</title></head>
+  <body>
+    <outline text="Go Blog" title="The Go Blog" type="rss" xmlUrl="https://go.dev/blog/feed.atom" htmlUrl="https://go.dev/blog"/>
+    <outline text="Tech">
+      <outline text="Example" type="rss" xmlUrl=" https://example.com/feed.xml "/>
+      <outline text="Go Blog again" type="rss" xmlUrl="https://go.dev/blog/feed.atom"/>
+    </outline>
+    <outline text="Not a feed" htmlUrl="https://example.com"/>
+  </body>
+</opml>`))
+	if err != nil {
+		t.Fatalf("ParseOPML() error = %v", err)
+	}
+	want := []Subscription{
+		{Title: "The Go Blog", URL: "https://go.dev/blog/feed.atom"},
+		{Title: "Example", URL: "https://example.com/feed.xml"},
+	}
+	if len(subs) != len(want) {
+		t.Fatalf("ParseOPML() = %+v, want %+v", subs, want)
+	}
+	for i := range want {
+		if subs[i] != want[i] {
+			t.Errorf("subs[%d] = %+v, want %+v", i, subs[i], want[i])
+		}
+	}
+}
+
+func TestParseOPML_Invalid(t *testing.T) {
+	if _, err := ParseOPML(strings.NewReader("not xml")); err == nil {
+		t.Error("ParseOPML() error = nil, want error")
+	}
+}
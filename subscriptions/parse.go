@@ -0,0 +1,201 @@
+package subscriptions
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Entry is an item of an RSS feed or an entry of an Atom feed
+type Entry struct {
+	// ID is the GUID or Atom ID of the entry, or its link if it has none
+	ID string `json:"id"`
+
+	// URL is the link to the entry
+	URL string `json:"url"`
+
+	// Title is the entry title
+	Title string `json:"title"`
+
+	// Author is the entry author
+	Author string `json:"author,omitempty"`
+
+	// Summary is the description or summary of the entry, often HTML
+	Summary string `json:"summary,omitempty"`
+
+	// Content is the full HTML content of the entry, if the feed has it
+	Content string `json:"content,omitempty"`
+
+	// Published is when the entry was published, or zero if unknown
+	Published time.Time `json:"published,omitempty"`
+}
+
+type rawFeed struct {
+	// RSS 2.0 items are in the channel, RSS 1.0 items next to it
+	Channel struct {
+		Items []rawItem `xml:"item"`
+	} `xml:"channel"`
+	Items []rawItem `xml:"item"`
+
+	Entries []rawEntry `xml:"entry"`
+}
+
+type rawItem struct {
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	GUID        string `xml:"guid"`
+	Author      string `xml:"author"`
+	Creator     string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Description string `xml:"description"`
+	Content     string `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	PubDate     string `xml:"pubDate"`
+	Date        string `xml:"http://purl.org/dc/elements/1.1/ date"`
+}
+
+type rawEntry struct {
+	ID    string `xml:"id"`
+	Title string `xml:"title"`
+	Links []struct {
+		Rel  string `xml:"rel,attr"`
+		Href string `xml:"href,attr"`
+	} `xml:"link"`
+	Author struct {
+		Name string `xml:"name"`
+	} `xml:"author"`
+	Summary string `xml:"summary"`
+	Content struct {
+		Type  string `xml:"type,attr"`
+		Text  string `xml:",chardata"`
+		Inner string `xml:",innerxml"`
+	} `xml:"content"`
+	Published string `xml:"published"`
+	Updated   string `xml:"updated"`
+}
+
+// ParseFeed returns the entries of an RSS 2.0, RSS 1.0 or Atom feed in the
+// order of the feed
+func ParseFeed(r io.Reader) ([]Entry, error) {
+	var raw rawFeed
+	dec := xml.NewDecoder(r)
+	dec.CharsetReader = charsetReader
+	// Feeds in the wild often use HTML entities such as &nbsp;
+	dec.Strict = false
+	dec.Entity = xml.HTMLEntity
+	if err := dec.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("failed to parse feed: %w", err)
+	}
+
+	var entries []Entry
+	for _, item := range append(raw.Channel.Items, raw.Items...) {
+		e := Entry{
+			ID:        strings.TrimSpace(item.GUID),
+			URL:       strings.TrimSpace(item.Link),
+			Title:     strings.TrimSpace(item.Title),
+			Author:    strings.TrimSpace(item.Author),
+			Summary:   strings.TrimSpace(item.Description),
+			Content:   strings.TrimSpace(item.Content),
+			Published: parseTime(item.PubDate, item.Date),
+		}
+		if e.Author == "" {
+			e.Author = strings.TrimSpace(item.Creator)
+		}
+		entries = append(entries, e)
+	}
+	for _, entry := range raw.Entries {
+		e := Entry{
+			ID:        strings.TrimSpace(entry.ID),
+			Title:     strings.TrimSpace(entry.Title),
+			Author:    strings.TrimSpace(entry.Author.Name),
+			Summary:   strings.TrimSpace(entry.Summary),
+			Content:   strings.TrimSpace(entry.Content.Text),
+			Published: parseTime(entry.Published, entry.Updated),
+		}
+		if entry.Content.Type == "xhtml" {
+			e.Content = strings.TrimSpace(entry.Content.Inner)
+		}
+		for _, link := range entry.Links {
+			if link.Rel == "" || link.Rel == "alternate" {
+				e.URL = strings.TrimSpace(link.Href)
+				break
+			}
+		}
+		entries = append(entries, e)
+	}
+
+	for i := range entries {
+		if entries[i].ID == "" {
+			entries[i].ID = entries[i].URL
+		}
+		if entries[i].ID == "" {
+			entries[i].ID = entries[i].Title
+		}
+	}
+	return entries, nil
+}
+
+// timeLayouts are the date formats found in feeds
+var timeLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	"2 Jan 2006 15:04:05 MST",
+	time.RFC822Z,
+	time.RFC822,
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+// parseTime returns the first of values that parses as a time, or zero
+func parseTime(values ...string) time.Time {
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		for _, layout := range timeLayouts {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// charsetReader decodes the Latin-1 encoding that some feeds still use,
+// besides UTF-8. Windows-1252 is read as Latin-1, which only differs in
+// punctuation.
+func charsetReader(charset string, input io.Reader) (io.Reader, error) {
+	switch strings.ToLower(charset) {
+	case "utf-8", "utf8", "us-ascii", "ascii":
+		return input, nil
+	case "iso-8859-1", "latin1", "windows-1252", "cp1252":
+		return &latin1Reader{r: bufio.NewReader(input)}, nil
+	}
+	return nil, fmt.Errorf("unsupported charset: %s", charset)
+}
+
+// latin1Reader converts Latin-1 to UTF-8
+type latin1Reader struct {
+	r   *bufio.Reader
+	buf []byte
+}
+
+func (l *latin1Reader) Read(p []byte) (int, error) {
+	for len(l.buf) == 0 {
+		b, err := l.r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		l.buf = utf8.AppendRune(l.buf, rune(b))
+	}
+	n := copy(p, l.buf)
+	l.buf = l.buf[n:]
+	return n, nil
+}
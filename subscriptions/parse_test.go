@@ -0,0 +1,104 @@
+package subscriptions
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const testRSS = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:content="http://purl.org/rss/1.0/modules/content/" xmlns:dc="http://purl.org/dc/elements/1.1/">
+  <channel>
+    <title>Example</title>
+    <link>https://example.com</link>
+    <item>
+      <title>Second&nbsp;post</title>
+      <link>https://example.com/2</link>
+      <guid isPermaLink="false">post-2</guid>
+      <dc:creator>Jane Doe</dc:creator>
+      <description>&lt;p&gt;Summary 2&lt;/p&gt;</description>
+      <content:encoded><![CDATA[<p>Full content 2</p>]]></content:encoded>
+      <pubDate>Tue, 15 Jul 2025 10:00:00 +0000</pubDate>
+    </item>
+    <item>
+      <title>First post</title>
+      <link>https://example.com/1</link>
+      <pubDate>Mon, 7 Jul 2025 10:00:00 GMT</pubDate>
+    </item>
+  </channel>
+</rss>`
+
+const testAtom = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Example</title>
+  <link href="https://example.com/feed.atom" rel="self"/>
+  <entry>
+    <id>tag:example.com,2025:1</id>
+    <title>Atom post</title>
+    <link rel="alternate" href="https://example.com/atom-1"/>
+    <link rel="replies" href="https://example.com/atom-1#comments"/>
+    <author><name>John Roe</name></author>
+    <summary>Short</summary>
+    <content type="xhtml"><div xmlns="http://www.w3.org/1999/xhtml"><p>Body</p></div></content>
+    <updated>2025-07-16T08:00:00Z</updated>
+  </entry>
+</feed>`
+
+func TestParseFeed_RSS(t *testing.T) {
+	entries, err := ParseFeed(strings.NewReader(testRSS))
+	if err != nil {
+		t.Fatalf("ParseFeed() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("ParseFeed() = %+v, want 2 entries", entries)
+	}
+	want := Entry{
+		ID:        "post-2",
+		URL:       "https://example.com/2",
+		Title:     "Second post",
+		Author:    "Jane Doe",
+		Summary:   "<p>Summary 2</p>",
+		Content:   "<p>Full content 2</p>",
+		Published: time.Date(2025, 7, 15, 10, 0, 0, 0, time.UTC),
+	}
+	got := entries[0]
+	if got.ID != want.ID || got.URL != want.URL || got.Title != want.Title || got.Author != want.Author ||
+		got.Summary != want.Summary || got.Content != want.Content || !got.Published.Equal(want.Published) {
+		t.Errorf("entries[0] = %+v, want %+v", got, want)
+	}
+	// Items without a GUID are identified by their link
+	if entries[1].ID != "https://example.com/1" || entries[1].Published.IsZero() {
+		t.Errorf("entries[1] = %+v", entries[1])
+	}
+}
+
+func TestParseFeed_Atom(t *testing.T) {
+	entries, err := ParseFeed(strings.NewReader(testAtom))
+	if err != nil {
+		t.Fatalf("ParseFeed() error = %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("ParseFeed() = %+v, want 1 entry", entries)
+	}
+	e := entries[0]
+	if e.ID != "tag:example.com,2025:1" || e.URL != "https://example.com/atom-1" || e.Author != "John Roe" || e.Summary != "Short" {
+		t.Errorf("entry = %+v", e)
+	}
+	if !strings.Contains(e.Content, "<p>Body</p>") {
+		t.Errorf("Content = %q, want the xhtml content", e.Content)
+	}
+	if !e.Published.Equal(time.Date(2025, 7, 16, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("Published = %v", e.Published)
+	}
+}
+
+func TestParseFeed_Latin1(t *testing.T) {
+	feed := "<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?><rss><channel><item><title>Caf\xe9</title><link>https://example.com/cafe</link></item></channel></rss>"
+	entries, err := ParseFeed(strings.NewReader(feed))
+	if err != nil {
+		t.Fatalf("ParseFeed() error = %v", err)
+	}
+	if len(entries) != 1 || entries[0].Title != "Café" {
+		t.Errorf("ParseFeed() = %+v, want title Café", entries)
+	}
+}
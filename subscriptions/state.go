@@ -0,0 +1,109 @@
+package subscriptions
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// maxSeen is the number of entry IDs kept per feed beyond those in the
+// feed, so that entries dropped from a feed and added back are not saved
+// again
+const maxSeen = 500
+
+// FeedState is what is known about a subscribed feed from previous polls
+type FeedState struct {
+	// Title is the title of the subscription
+	Title string `json:"title"`
+
+	// ETag and LastModified are the validators of the last fetched feed,
+	// sent to only fetch it again when it changed
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+
+	// LastGUID is the ID of the newest entry of the last fetched feed
+	LastGUID string `json:"last_guid,omitempty"`
+
+	// Seen are the IDs of entries that were saved or skipped, newest first
+	Seen []string `json:"seen,omitempty"`
+
+	// LastPolled is when the feed was last fetched successfully
+	LastPolled *time.Time `json:"last_polled,omitempty"`
+}
+
+// State is the subscriptions and their feed states, stored as a JSON file
+type State struct {
+	path string
+
+	// Feeds are the subscribed feeds by URL
+	Feeds map[string]*FeedState `json:"feeds"`
+}
+
+// LoadState reads the state stored at path. A missing file yields an empty
+// state that is created on the first Save.
+func LoadState(path string) (*State, error) {
+	s := &State{path: path, Feeds: make(map[string]*FeedState)}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("failed to read feed state: %w", err)
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("failed to decode feed state: %w", err)
+	}
+	if s.Feeds == nil {
+		s.Feeds = make(map[string]*FeedState)
+	}
+	return s, nil
+}
+
+// Save writes the state to its file
+func (s *State) Save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode feed state: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create feed state directory: %w", err)
+	}
+	// Write atomically so that an interrupted save does not lose the state
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write feed state: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to write feed state: %w", err)
+	}
+	return nil
+}
+
+// Subscribe adds subscriptions. Feeds already subscribed keep their state.
+func (s *State) Subscribe(subs ...Subscription) {
+	for _, sub := range subs {
+		if _, ok := s.Feeds[sub.URL]; !ok {
+			s.Feeds[sub.URL] = &FeedState{Title: sub.Title}
+		}
+	}
+}
+
+// Subscriptions returns the subscribed feeds ordered by title
+func (s *State) Subscriptions() []Subscription {
+	subs := make([]Subscription, 0, len(s.Feeds))
+	for url, feed := range s.Feeds {
+		subs = append(subs, Subscription{Title: feed.Title, URL: url})
+	}
+	sort.Slice(subs, func(i, j int) bool {
+		if subs[i].Title != subs[j].Title {
+			return subs[i].Title < subs[j].Title
+		}
+		return subs[i].URL < subs[j].URL
+	})
+	return subs
+}
@@ -0,0 +1,61 @@
+package reader
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// timeLayouts are the layouts of the times Readwise emits, most common first.
+// Times without a zone are in UTC.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+// ParseTime parses a time in one of the formats Readwise emits: an RFC 3339
+// time such as "2025-09-01T10:00:00.123Z", with or without a zone and with a
+// space instead of the "T", or a date such as "2025-09-01". Times without a
+// zone and dates are in UTC.
+func ParseTime(s string) (time.Time, error) {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time: %q", s)
+}
+
+// PublishedTime returns the published date of the document, and false if it
+// is empty or cannot be parsed (see ParseTime)
+func (d Document) PublishedTime() (time.Time, bool) {
+	if d.PublishedDate == "" {
+		return time.Time{}, false
+	}
+	t, err := ParseTime(d.PublishedDate)
+	return t, err == nil
+}
+
+// flexibleTime is a time decoded from JSON with ParseTime. An empty string
+// decodes to the zero time.
+type flexibleTime time.Time
+
+func (t *flexibleTime) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("invalid time: %s", data)
+	}
+	if s == "" {
+		*t = flexibleTime{}
+		return nil
+	}
+	parsed, err := ParseTime(s)
+	if err != nil {
+		return err
+	}
+	*t = flexibleTime(parsed)
+	return nil
+}
@@ -0,0 +1,90 @@
+package reader
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParseTime(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"2025-09-01T10:20:30Z", time.Date(2025, 9, 1, 10, 20, 30, 0, time.UTC)},
+		{"2025-09-01T10:20:30.123456+00:00", time.Date(2025, 9, 1, 10, 20, 30, 123456000, time.UTC)},
+		{"2025-09-01T12:20:30+02:00", time.Date(2025, 9, 1, 10, 20, 30, 0, time.UTC)},
+		{"2025-09-01T10:20:30.5", time.Date(2025, 9, 1, 10, 20, 30, 500000000, time.UTC)},
+		{"2025-09-01 10:20:30", time.Date(2025, 9, 1, 10, 20, 30, 0, time.UTC)},
+		{"2025-09-01 10:20:30+00:00", time.Date(2025, 9, 1, 10, 20, 30, 0, time.UTC)},
+		{"2025-09-01", time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := ParseTime(tt.in)
+		if err != nil {
+			t.Errorf("ParseTime(%q) error = %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseTime(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "yesterday", "2025-13-01", "1756720830"} {
+		if _, err := ParseTime(in); err == nil {
+			t.Errorf("ParseTime(%q) error = nil, want error", in)
+		}
+	}
+}
+
+func TestDocument_UnmarshalJSON(t *testing.T) {
+	data := `{
+		"id": "doc1",
+		"title": "Essay",
+		"location": "later",
+		"created_at": "2025-09-01",
+		"updated_at": "2025-09-02T10:00:00.000000+00:00",
+		"first_opened_at": null,
+		"saved_at": "",
+		"published_date": "2024-05-06"
+	}`
+	var doc Document
+	if err := json.Unmarshal([]byte(data), &doc); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if doc.ID != "doc1" || doc.Title != "Essay" || doc.Location != LocationLater {
+		t.Errorf("document = %+v", doc)
+	}
+	if doc.CreatedAt == nil || !doc.CreatedAt.Equal(time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("CreatedAt = %v, want the date", doc.CreatedAt)
+	}
+	if doc.UpdatedAt == nil || !doc.UpdatedAt.Equal(time.Date(2025, 9, 2, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("UpdatedAt = %v", doc.UpdatedAt)
+	}
+	if doc.FirstOpenedAt != nil || doc.SavedAt != nil || doc.LastMovedAt != nil {
+		t.Errorf("null, empty and missing times = %v, %v, %v, want nil", doc.FirstOpenedAt, doc.SavedAt, doc.LastMovedAt)
+	}
+	if published, ok := doc.PublishedTime(); !ok || !published.Equal(time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("PublishedTime() = %v, %v", published, ok)
+	}
+
+	// Documents round-trip
+	encoded, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var decoded Document
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !decoded.CreatedAt.Equal(*doc.CreatedAt) || decoded.Title != doc.Title {
+		t.Errorf("round-trip = %+v, want %+v", decoded, doc)
+	}
+
+	if err := json.Unmarshal([]byte(`{"id": "doc1", "updated_at": "soon"}`), &doc); err == nil {
+		t.Error("Unmarshal() error = nil, want an invalid time")
+	}
+	if err := json.Unmarshal([]byte(`{"id": "doc1", "updated_at": 1756720830}`), &doc); err == nil {
+		t.Error("Unmarshal() error = nil, want an invalid time")
+	}
+}
@@ -0,0 +1,173 @@
+// Package trash keeps local copies of deleted Readwise Reader documents so
+// that they can be restored.
+//
+// The API deletes documents permanently. Put stores the full document,
+// including its HTML content, in a directory before it is deleted, and Restore
+// saves it again from its URL, HTML content, notes and metadata.
+package trash
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	reader "github.com/tcnksm/go-readwise-reader"
+	"github.com/tcnksm/go-readwise-reader/journal"
+)
+
+// Item is a document in the trash
+type Item struct {
+	// DeletedAt is when the document was put in the trash
+	DeletedAt time.Time `json:"deleted_at"`
+
+	// Document is the document as it was before deletion
+	Document reader.Document `json:"document"`
+}
+
+// Trash is a trash stored as one JSON file per document in a directory
+type Trash struct {
+	dir string
+}
+
+// Open returns the trash stored in dir. The directory is created on the
+// first Put.
+func Open(dir string) *Trash {
+	return &Trash{dir: dir}
+}
+
+// path returns the file of a document, rejecting IDs that are not plain names
+func (t *Trash) path(documentID string) (string, error) {
+	if documentID == "" || strings.ContainsAny(documentID, `/\`) || documentID == "." || documentID == ".." {
+		return "", fmt.Errorf("invalid document ID: %q", documentID)
+	}
+	return filepath.Join(t.dir, documentID+".json"), nil
+}
+
+// Put stores a document in the trash. The document should have been fetched
+// with its HTML content.
+func (t *Trash) Put(doc reader.Document) error {
+	path, err := t.path(doc.ID)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(Item{DeletedAt: time.Now(), Document: doc})
+	if err != nil {
+		return fmt.Errorf("failed to encode document: %w", err)
+	}
+	if err := os.MkdirAll(t.dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create trash directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to put document in trash: %w", err)
+	}
+	return nil
+}
+
+// Get returns a document from the trash
+func (t *Trash) Get(documentID string) (*Item, error) {
+	path, err := t.path(documentID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("document not in trash: %s", documentID)
+		}
+		return nil, fmt.Errorf("failed to read trash: %w", err)
+	}
+	var item Item
+	if err := json.Unmarshal(data, &item); err != nil {
+		return nil, fmt.Errorf("failed to decode trashed document %s: %w", documentID, err)
+	}
+	return &item, nil
+}
+
+// List returns the documents in the trash, most recently deleted first
+func (t *Trash) List() ([]Item, error) {
+	entries, err := os.ReadDir(t.dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read trash: %w", err)
+	}
+
+	var items []Item
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		item, err := t.Get(id)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].DeletedAt.After(items[j].DeletedAt)
+	})
+	return items, nil
+}
+
+// Remove removes a document from the trash
+func (t *Trash) Remove(documentID string) error {
+	path, err := t.path(documentID)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("document not in trash: %s", documentID)
+		}
+		return fmt.Errorf("failed to remove document from trash: %w", err)
+	}
+	return nil
+}
+
+// Restore saves a trashed document again and removes it from the trash. The
+// restored document gets a new ID, which is returned in the response.
+func (t *Trash) Restore(ctx context.Context, c reader.Client, documentID string) (*reader.CreateDocumentResponse, error) {
+	item, err := t.Get(documentID)
+	if err != nil {
+		return nil, err
+	}
+
+	url, req := journal.RecreateRequest(&item.Document)
+	resp, err := c.CreateDocument(ctx, url, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore document %s: %w", documentID, err)
+	}
+	if err := t.Remove(documentID); err != nil {
+		return resp, err
+	}
+	return resp, nil
+}
+
+// Empty removes the documents deleted before the given time from the trash
+// and returns how many were removed
+func (t *Trash) Empty(before time.Time) (int, error) {
+	items, err := t.List()
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	for _, item := range items {
+		if !item.DeletedAt.Before(before) {
+			continue
+		}
+		if err := t.Remove(item.Document.ID); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
@@ -0,0 +1,119 @@
+package trash
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	reader "github.com/tcnksm/go-readwise-reader"
+)
+
+// createClient is a reader.Client that records created documents
+type createClient struct {
+	reader.Client
+	url string
+	req *reader.CreateDocumentRequest
+	err error
+}
+
+func (c *createClient) CreateDocument(ctx context.Context, url string, req *reader.CreateDocumentRequest) (*reader.CreateDocumentResponse, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	c.url, c.req = url, req
+	return &reader.CreateDocumentResponse{ID: "new-id", URL: url}, nil
+}
+
+func TestTrash(t *testing.T) {
+	tr := Open(filepath.Join(t.TempDir(), "trash"))
+
+	// Empty trash
+	items, err := tr.List()
+	if err != nil || len(items) != 0 {
+		t.Fatalf("List() = %v, %v, want empty", items, err)
+	}
+
+	doc := reader.Document{
+		ID:          "doc1",
+		URL:         "https://read.readwise.io/read/doc1",
+		SourceURL:   "https://example.com/article",
+		Title:       "Article",
+		Notes:       "notes",
+		HTMLContent: "<p>content</p>",
+		Location:    reader.LocationLater,
+		Tags:        map[string]interface{}{"go": map[string]interface{}{"name": "go"}},
+	}
+	if err := tr.Put(doc); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if err := tr.Put(reader.Document{ID: "doc2", URL: "https://example.com/other"}); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	items, err = tr.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("List() returned %d items, want 2", len(items))
+	}
+
+	item, err := tr.Get("doc1")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if item.Document.HTMLContent != doc.HTMLContent || item.DeletedAt.IsZero() {
+		t.Errorf("Get() = %+v", item)
+	}
+
+	client := &createClient{}
+	resp, err := tr.Restore(context.Background(), client, "doc1")
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if resp.ID != "new-id" {
+		t.Errorf("Restore() ID = %v, want new-id", resp.ID)
+	}
+	if client.url != "https://example.com/article" {
+		t.Errorf("restored URL = %v, want the source URL", client.url)
+	}
+	if client.req.HTML != "<p>content</p>" || client.req.Notes != "notes" || client.req.Location != reader.LocationLater || len(client.req.Tags) != 1 {
+		t.Errorf("restore request = %+v", client.req)
+	}
+	if _, err := tr.Get("doc1"); err == nil {
+		t.Error("Expected restored document to be removed from trash")
+	}
+
+	// Emptying only removes documents deleted before the given time
+	if n, err := tr.Empty(time.Now().Add(-time.Hour)); err != nil || n != 0 {
+		t.Errorf("Empty() = %d, %v, want 0", n, err)
+	}
+	if n, err := tr.Empty(time.Now().Add(time.Second)); err != nil || n != 1 {
+		t.Errorf("Empty() = %d, %v, want 1", n, err)
+	}
+}
+
+func TestTrash_RestoreFailure(t *testing.T) {
+	tr := Open(t.TempDir())
+	if err := tr.Put(reader.Document{ID: "doc1", URL: "https://example.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := tr.Restore(context.Background(), &createClient{err: errors.New("boom")}, "doc1"); err == nil {
+		t.Fatal("Expected error, got none")
+	}
+	if _, err := tr.Get("doc1"); err != nil {
+		t.Errorf("Expected document to stay in trash, got %v", err)
+	}
+}
+
+func TestTrash_InvalidID(t *testing.T) {
+	tr := Open(t.TempDir())
+	for _, id := range []string{"", "..", "../escape", `a\b`} {
+		if err := tr.Put(reader.Document{ID: id}); err == nil {
+			t.Errorf("Put(%q) expected error, got none", id)
+		}
+	}
+}
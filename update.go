@@ -33,11 +33,99 @@ type UpdateDocumentRequest struct {
 	// Tags is a list of tags to associate with the document
 	Tags []string `json:"tags,omitempty"`
 
+	// Notes is a top-level note of the document
+	Notes string `json:"notes,omitempty"`
+
 	// Location is where the document should be stored
 	Location Location `json:"location,omitempty"`
 
 	// Category is the document type
 	Category Category `json:"category,omitempty"`
+
+	// Clear lists fields to clear. Fields that are empty above are left out
+	// of the request and stay unchanged, so an empty summary or tag list has
+	// to be cleared explicitly. Cleared text fields are sent as empty strings,
+	// tags as an empty list and the published date as null.
+	Clear []UpdateField `json:"-"`
+}
+
+// UpdateField names a field of UpdateDocumentRequest that can be cleared
+type UpdateField string
+
+// UpdateField constants for clearing document fields
+const (
+	UpdateFieldTitle         UpdateField = "title"
+	UpdateFieldAuthor        UpdateField = "author"
+	UpdateFieldSummary       UpdateField = "summary"
+	UpdateFieldPublishedDate UpdateField = "published_date"
+	UpdateFieldImageURL      UpdateField = "image_url"
+	UpdateFieldTags          UpdateField = "tags"
+	UpdateFieldNotes         UpdateField = "notes"
+)
+
+// clearedValues are the JSON values sent for cleared fields
+var clearedValues = map[UpdateField]json.RawMessage{
+	UpdateFieldTitle:         json.RawMessage(`""`),
+	UpdateFieldAuthor:        json.RawMessage(`""`),
+	UpdateFieldSummary:       json.RawMessage(`""`),
+	UpdateFieldPublishedDate: json.RawMessage(`null`),
+	UpdateFieldImageURL:      json.RawMessage(`""`),
+	UpdateFieldTags:          json.RawMessage(`[]`),
+	UpdateFieldNotes:         json.RawMessage(`""`),
+}
+
+// MarshalJSON encodes the fields that are set and the cleared fields, leaving
+// out the others
+func (r UpdateDocumentRequest) MarshalJSON() ([]byte, error) {
+	type request UpdateDocumentRequest
+	data, err := json.Marshal(request(r))
+	if err != nil || len(r.Clear) == 0 {
+		return data, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for _, field := range r.Clear {
+		value, ok := clearedValues[field]
+		if !ok {
+			return nil, fmt.Errorf("unknown field to clear: %s", field)
+		}
+		fields[string(field)] = value
+	}
+	return json.Marshal(fields)
+}
+
+// Validate checks that the location and category are valid and that cleared
+// fields are known and not also set.
+// UpdateDocument validates requests before sending them.
+func (r *UpdateDocumentRequest) Validate() error {
+	if r.Location != "" && !r.Location.Valid() {
+		return invalidLocation(string(r.Location))
+	}
+	if r.Category != "" && !r.Category.Valid() {
+		return invalidCategory(string(r.Category))
+	}
+	set := map[UpdateField]bool{
+		UpdateFieldTitle:         r.Title != "",
+		UpdateFieldAuthor:        r.Author != "",
+		UpdateFieldSummary:       r.Summary != "",
+		UpdateFieldPublishedDate: r.PublishedDate != nil,
+		UpdateFieldImageURL:      r.ImageURL != "",
+		UpdateFieldTags:          len(r.Tags) > 0,
+		UpdateFieldNotes:         r.Notes != "",
+	}
+	for _, field := range r.Clear {
+		isSet, ok := set[field]
+		if !ok {
+			return fmt.Errorf("unknown field to clear: %s", field)
+		}
+		if isSet {
+			return fmt.Errorf("field %s cannot be both set and cleared", field)
+		}
+	}
+	return nil
 }
 
 // UpdateDocumentResponse represents the response from updating a document
@@ -65,6 +153,13 @@ func (c *client) UpdateDocument(ctx context.Context, documentID string, req *Upd
 		}
 	}
 
+	if err := req.Validate(); err != nil {
+		return nil, &ClientError{
+			Type:    "invalid_parameter",
+			Message: err.Error(),
+		}
+	}
+
 	// Build URL
 	url := fmt.Sprintf("%s/update/%s/", c.baseURL, documentID)
 
@@ -86,7 +181,7 @@ func (c *client) UpdateDocument(ctx context.Context, documentID string, req *Upd
 	httpReq.Header.Set("Content-Type", "application/json")
 
 	// Execute request
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.do(httpReq, "update", documentID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
@@ -3,6 +3,8 @@ package reader
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -234,6 +236,147 @@ func TestUpdateDocument_ContextCancellation(t *testing.T) {
 	}
 }
 
+func TestUpdateDocumentRequest_MarshalJSON(t *testing.T) {
+	publishedDate := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		req  UpdateDocumentRequest
+		want string
+	}{
+		{name: "empty", req: UpdateDocumentRequest{}, want: `{}`},
+		{name: "set", req: UpdateDocumentRequest{Summary: "New", Tags: []string{"a"}}, want: `{"summary":"New","tags":["a"]}`},
+		{name: "empty tags are left out", req: UpdateDocumentRequest{Tags: []string{}}, want: `{}`},
+		{name: "clear text", req: UpdateDocumentRequest{Clear: []UpdateField{UpdateFieldSummary}}, want: `{"summary":""}`},
+		{name: "clear tags", req: UpdateDocumentRequest{Tags: []string{}, Clear: []UpdateField{UpdateFieldTags}}, want: `{"tags":[]}`},
+		{name: "clear published date", req: UpdateDocumentRequest{Clear: []UpdateField{UpdateFieldPublishedDate}}, want: `{"published_date":null}`},
+		{
+			name: "set and clear",
+			req: UpdateDocumentRequest{
+				Title:         "Title",
+				PublishedDate: &publishedDate,
+				Seen:          boolPtr(false),
+				Location:      LocationArchive,
+				Clear:         []UpdateField{UpdateFieldAuthor, UpdateFieldImageURL, UpdateFieldNotes},
+			},
+			want: `{"author":"","image_url":"","location":"archive","notes":"","published_date":"2024-01-15T00:00:00Z","seen":false,"title":"Title"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(&tt.req)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Marshal() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateDocumentRequest_MarshalJSON_Combinations(t *testing.T) {
+	publishedDate := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	// Each field is left untouched, set or cleared in every combination
+	fields := []struct {
+		field   UpdateField
+		set     func(*UpdateDocumentRequest)
+		setJSON string
+		cleared string
+	}{
+		{UpdateFieldTitle, func(r *UpdateDocumentRequest) { r.Title = "T" }, `"T"`, `""`},
+		{UpdateFieldAuthor, func(r *UpdateDocumentRequest) { r.Author = "A" }, `"A"`, `""`},
+		{UpdateFieldSummary, func(r *UpdateDocumentRequest) { r.Summary = "S" }, `"S"`, `""`},
+		{UpdateFieldPublishedDate, func(r *UpdateDocumentRequest) { r.PublishedDate = &publishedDate }, `"2024-01-15T00:00:00Z"`, `null`},
+		{UpdateFieldImageURL, func(r *UpdateDocumentRequest) { r.ImageURL = "I" }, `"I"`, `""`},
+		{UpdateFieldTags, func(r *UpdateDocumentRequest) { r.Tags = []string{"t"} }, `["t"]`, `[]`},
+		{UpdateFieldNotes, func(r *UpdateDocumentRequest) { r.Notes = "N" }, `"N"`, `""`},
+	}
+
+	combinations := 1
+	for range fields {
+		combinations *= 3
+	}
+	for n := 0; n < combinations; n++ {
+		req := &UpdateDocumentRequest{}
+		want := map[string]string{}
+		state := n
+		for _, f := range fields {
+			switch state % 3 {
+			case 1:
+				f.set(req)
+				want[string(f.field)] = f.setJSON
+			case 2:
+				req.Clear = append(req.Clear, f.field)
+				want[string(f.field)] = f.cleared
+			}
+			state /= 3
+		}
+
+		if err := req.Validate(); err != nil {
+			t.Fatalf("Validate() with %+v error = %v", req, err)
+		}
+		data, err := json.Marshal(req)
+		if err != nil {
+			t.Fatalf("Marshal() with %+v error = %v", req, err)
+		}
+		var got map[string]json.RawMessage
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("Unmarshal(%s) error = %v", data, err)
+		}
+		if len(got) != len(want) {
+			t.Errorf("Marshal() = %s, want fields %v", data, want)
+			continue
+		}
+		for field, value := range want {
+			if string(got[field]) != value {
+				t.Errorf("Marshal() = %s, want %s = %s", data, field, value)
+			}
+		}
+	}
+}
+
+func TestUpdateDocument_Clear(t *testing.T) {
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		json.NewEncoder(w).Encode(UpdateDocumentResponse{ID: "doc123"})
+	}))
+	defer server.Close()
+
+	client := &client{
+		baseURL:    server.URL,
+		token:      "test-token",
+		httpClient: &http.Client{},
+	}
+	ctx := context.Background()
+
+	req := &UpdateDocumentRequest{Location: LocationLater, Clear: []UpdateField{UpdateFieldSummary, UpdateFieldTags}}
+	if _, err := client.UpdateDocument(ctx, "doc123", req); err != nil {
+		t.Fatalf("UpdateDocument() error = %v", err)
+	}
+	if want := `{"location":"later","summary":"","tags":[]}`; body != want {
+		t.Errorf("request body = %s, want %s", body, want)
+	}
+
+	for _, req := range []*UpdateDocumentRequest{
+		{Summary: "New", Clear: []UpdateField{UpdateFieldSummary}},
+		{Clear: []UpdateField{"location"}},
+	} {
+		body = ""
+		_, err := client.UpdateDocument(ctx, "doc123", req)
+		var clientErr *ClientError
+		if !errors.As(err, &clientErr) {
+			t.Errorf("UpdateDocument(%+v) error = %v, want ClientError", req, err)
+		}
+		if body != "" {
+			t.Errorf("UpdateDocument(%+v) sent a request", req)
+		}
+	}
+}
+
 // boolPtr returns a pointer to the given bool value
 func boolPtr(b bool) *bool {
 	return &b
@@ -0,0 +1,233 @@
+package reader
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+// Event types reported by Watch for changes that webhooks do not report
+const (
+	// EventDocumentMoved is reported when a document is moved to a location
+	// without a more specific event, such as the feed
+	EventDocumentMoved WebhookEventType = "reader.document.moved"
+
+	// EventDocumentProgressUpdated is reported when the reading progress of
+	// a document changes without finishing it
+	EventDocumentProgressUpdated WebhookEventType = "reader.document.progress_updated"
+
+	// EventDocumentMetadataUpdated is reported when the title, author,
+	// summary, notes or other metadata of a document change
+	EventDocumentMetadataUpdated WebhookEventType = "reader.document.metadata_updated"
+)
+
+// WatchEvent is a change to a document found by polling
+type WatchEvent struct {
+	// Type is the kind of change. Changes that webhooks report have the
+	// same event types as webhooks.
+	Type WebhookEventType `json:"event_type"`
+
+	// Document is the document after the change
+	Document Document `json:"document"`
+
+	// Previous is the document before the change, or nil if it was created
+	Previous *Document `json:"previous,omitempty"`
+
+	// From and To are the locations of a moved document
+	From Location `json:"from,omitempty"`
+	To   Location `json:"to,omitempty"`
+}
+
+// Payload returns the event as a webhook payload, so that a
+// WebhookHandlerFunc can handle events found by polling
+func (e WatchEvent) Payload() *DocumentWebhookPayload {
+	d := e.Document
+	p := &DocumentWebhookPayload{
+		EventType:       e.Type,
+		ID:              d.ID,
+		URL:             d.URL,
+		Title:           d.Title,
+		Author:          d.Author,
+		Category:        d.Category,
+		Location:        d.Location,
+		Tags:            d.Tags,
+		SiteName:        d.SiteName,
+		WordCount:       d.WordCount,
+		ReadingTime:     d.ReadingTime,
+		CreatedAt:       d.CreatedAt,
+		UpdatedAt:       d.UpdatedAt,
+		PublishedDate:   d.PublishedDate,
+		Summary:         d.Summary,
+		SourceURL:       d.SourceURL,
+		Notes:           d.Notes,
+		ReadingProgress: d.ReadingProgressPercent,
+		FirstOpenedAt:   d.FirstOpenedAt,
+		LastOpenedAt:    d.LastOpenedAt,
+		SavedAt:         d.SavedAt,
+		LastMovedAt:     d.LastMovedAt,
+	}
+	if d.Source != "" {
+		p.Source = &d.Source
+	}
+	if d.ImageURL != "" {
+		p.ImageURL = &d.ImageURL
+	}
+	if d.HTMLContent != "" {
+		p.Content = &d.HTMLContent
+	}
+	if d.ParentID != "" {
+		p.ParentID = &d.ParentID
+	}
+	return p
+}
+
+// Watcher finds changes to documents by polling ListDocuments
+type Watcher struct {
+	client Client
+	opts   ListDocumentsOptions
+
+	started   time.Time
+	watermark *time.Time
+	docs      map[string]Document
+}
+
+// NewWatcher returns a watcher of the documents matching opts. Moves out of
+// a location or category in opts cannot be seen, so watching the whole
+// library finds the most changes.
+func NewWatcher(c Client, opts *ListDocumentsOptions) *Watcher {
+	w := &Watcher{client: c}
+	if opts != nil {
+		w.opts = *opts
+	}
+	w.opts.PageCursor = ""
+	return w
+}
+
+// Poll lists the documents updated since the last poll, following every
+// page, and returns their changes. The first poll records the state of the
+// documents matching the options and returns no events. Deleted documents
+// are not listed by the API and produce no events.
+func (w *Watcher) Poll(ctx context.Context) ([]WatchEvent, error) {
+	// Documents updated while listing are listed again by the next poll and
+	// produce no events if they have not changed since
+	next := time.Now()
+
+	opts := w.opts
+	if w.watermark != nil {
+		opts.UpdatedAfter = w.watermark
+	}
+	docs, err := ListAllDocuments(ctx, w.client, &opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []WatchEvent
+	if w.docs == nil {
+		w.docs = make(map[string]Document, len(docs))
+		w.started = next
+	} else {
+		for _, doc := range docs {
+			events = append(events, w.changes(doc)...)
+		}
+	}
+	for _, doc := range docs {
+		w.docs[doc.ID] = doc
+	}
+	w.watermark = &next
+	return events, nil
+}
+
+// changes returns the events for the new state of a document
+func (w *Watcher) changes(doc Document) []WatchEvent {
+	prev, ok := w.docs[doc.ID]
+	if !ok {
+		// Documents updated but not created since the first poll were
+		// outside the options, and their previous state is unknown
+		if doc.CreatedAt != nil && doc.CreatedAt.Before(w.started) {
+			return nil
+		}
+		created := EventNonFeedDocumentCreated
+		if doc.Location == LocationFeed {
+			created = EventFeedDocumentCreated
+		}
+		return []WatchEvent{
+			{Type: EventAnyDocumentCreated, Document: doc},
+			{Type: created, Document: doc},
+		}
+	}
+
+	var events []WatchEvent
+	event := func(t WebhookEventType) WatchEvent {
+		return WatchEvent{Type: t, Document: doc, Previous: &prev}
+	}
+
+	if doc.Location != prev.Location {
+		e := event(EventDocumentMoved)
+		switch doc.Location {
+		case LocationArchive:
+			e.Type = EventDocumentArchived
+		case LocationLater:
+			e.Type = EventDocumentMovedToLater
+		case LocationNew:
+			e.Type = EventDocumentMovedToInbox
+		}
+		e.From, e.To = prev.Location, doc.Location
+		events = append(events, e)
+	}
+	if !slices.Equal(doc.TagNames(), prev.TagNames()) {
+		events = append(events, event(EventDocumentTagsUpdated))
+	}
+	switch {
+	case doc.Progress() == ProgressFinished && prev.Progress() != ProgressFinished:
+		events = append(events, event(EventDocumentFinished))
+	case doc.ReadingProgressPercent != prev.ReadingProgressPercent:
+		events = append(events, event(EventDocumentProgressUpdated))
+	}
+	if doc.Title != prev.Title || doc.Author != prev.Author || doc.Summary != prev.Summary ||
+		doc.Notes != prev.Notes || doc.ImageURL != prev.ImageURL || doc.PublishedDate != prev.PublishedDate ||
+		doc.Category != prev.Category || doc.SiteName != prev.SiteName {
+		events = append(events, event(EventDocumentMetadataUpdated))
+	}
+	return events
+}
+
+// Watch polls for changes to the documents matching opts at the given
+// interval and sends them on the returned channel until ctx is done. The
+// first poll records the current state without sending events. Failed polls
+// are retried at the next interval and reported on the error channel, where
+// errors are dropped while one is unread. Both channels are closed when ctx
+// is done.
+func Watch(ctx context.Context, c Client, opts *ListDocumentsOptions, interval time.Duration) (<-chan WatchEvent, <-chan error) {
+	events := make(chan WatchEvent)
+	errs := make(chan error, 1)
+	w := NewWatcher(c, opts)
+
+	go func() {
+		defer close(events)
+		defer close(errs)
+		for {
+			changes, err := w.Poll(ctx)
+			if err != nil && ctx.Err() == nil {
+				select {
+				case errs <- err:
+				default:
+				}
+			}
+			for _, e := range changes {
+				select {
+				case events <- e:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+			}
+		}
+	}()
+
+	return events, errs
+}
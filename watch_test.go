@@ -0,0 +1,155 @@
+package reader
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeLibrary serves a mutable set of documents from ListDocuments
+type fakeLibrary struct {
+	mu   sync.Mutex
+	docs []Document
+
+	// next replaces docs after they are served once
+	next []Document
+}
+
+func (l *fakeLibrary) set(docs ...Document) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.docs = docs
+}
+
+func (l *fakeLibrary) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ListDocumentsResponse{Count: len(l.docs), Results: l.docs})
+	if l.next != nil {
+		l.docs, l.next = l.next, nil
+	}
+}
+
+func newWatchTestClient(t *testing.T, lib *fakeLibrary) Client {
+	server := httptest.NewServer(lib)
+	t.Cleanup(server.Close)
+	return &client{
+		baseURL:    server.URL,
+		token:      "test-token",
+		httpClient: &http.Client{},
+	}
+}
+
+func TestWatcher_Poll(t *testing.T) {
+	old := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	now := time.Now().Add(time.Minute)
+
+	lib := &fakeLibrary{}
+	lib.set(
+		Document{ID: "moved", Location: LocationNew, CreatedAt: &old},
+		Document{ID: "feed", Location: LocationNew, CreatedAt: &old},
+		Document{ID: "tagged", Location: LocationNew, CreatedAt: &old},
+		Document{ID: "read", Location: LocationLater, ReadingProgressPercent: 20, CreatedAt: &old},
+		Document{ID: "finished", Location: LocationLater, ReadingProgressPercent: 50, CreatedAt: &old},
+		Document{ID: "renamed", Title: "Old", Location: LocationNew, CreatedAt: &old},
+		Document{ID: "unchanged", Location: LocationNew, CreatedAt: &old},
+	)
+	w := NewWatcher(newWatchTestClient(t, lib), nil)
+
+	events, err := w.Poll(context.Background())
+	if err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+	if len(events) != 0 {
+		t.Fatalf("first Poll() = %d events, want 0", len(events))
+	}
+
+	lib.set(
+		Document{ID: "moved", Location: LocationArchive, CreatedAt: &old},
+		Document{ID: "feed", Location: LocationFeed, CreatedAt: &old},
+		Document{ID: "tagged", Location: LocationNew, Tags: map[string]interface{}{"go": map[string]interface{}{"name": "go"}}, CreatedAt: &old},
+		Document{ID: "read", Location: LocationLater, ReadingProgressPercent: 40, CreatedAt: &old},
+		Document{ID: "finished", Location: LocationLater, ReadingProgressPercent: 100, CreatedAt: &old},
+		Document{ID: "renamed", Title: "New", Location: LocationNew, CreatedAt: &old},
+		Document{ID: "unchanged", Location: LocationNew, CreatedAt: &old},
+		Document{ID: "created", Location: LocationFeed, CreatedAt: &now},
+		Document{ID: "unknown", Location: LocationNew, CreatedAt: &old},
+	)
+	events, err = w.Poll(context.Background())
+	if err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+
+	want := []struct {
+		id  string
+		typ WebhookEventType
+	}{
+		{"moved", EventDocumentArchived},
+		{"feed", EventDocumentMoved},
+		{"tagged", EventDocumentTagsUpdated},
+		{"read", EventDocumentProgressUpdated},
+		{"finished", EventDocumentFinished},
+		{"renamed", EventDocumentMetadataUpdated},
+		{"created", EventAnyDocumentCreated},
+		{"created", EventFeedDocumentCreated},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("Poll() = %+v, want %d events", events, len(want))
+	}
+	for i, w := range want {
+		if events[i].Document.ID != w.id || events[i].Type != w.typ {
+			t.Errorf("events[%d] = %s %s, want %s %s", i, events[i].Document.ID, events[i].Type, w.id, w.typ)
+		}
+	}
+	if e := events[1]; e.From != LocationNew || e.To != LocationFeed || e.Previous == nil {
+		t.Errorf("moved event = from %q to %q, previous %v", e.From, e.To, e.Previous)
+	}
+	if e := events[6]; e.Previous != nil {
+		t.Errorf("created event previous = %+v, want nil", e.Previous)
+	}
+
+	// Changes are only reported once
+	events, err = w.Poll(context.Background())
+	if err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+	if len(events) != 0 {
+		t.Errorf("third Poll() = %+v, want no events", events)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	now := time.Now().Add(time.Minute)
+	lib := &fakeLibrary{next: []Document{{ID: "doc1", Location: LocationNew, CreatedAt: &now}}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, errs := Watch(ctx, newWatchTestClient(t, lib), nil, 10*time.Millisecond)
+
+	select {
+	case e := <-events:
+		if e.Type != EventAnyDocumentCreated || e.Document.ID != "doc1" {
+			t.Errorf("event = %s %s, want %s doc1", e.Type, e.Document.ID, EventAnyDocumentCreated)
+		}
+		payload := e.Payload()
+		if payload.EventType != EventAnyDocumentCreated || payload.ID != "doc1" {
+			t.Errorf("Payload() = %+v", payload)
+		}
+	case err := <-errs:
+		t.Fatalf("Watch() error = %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for event")
+	}
+
+	cancel()
+	for range events {
+	}
+	if _, ok := <-errs; ok {
+		t.Error("error channel not closed")
+	}
+}
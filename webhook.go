@@ -1,9 +1,18 @@
 package reader
 
 import (
+	"context"
+	"crypto/subtle"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -124,12 +133,205 @@ type DocumentWebhookPayload struct {
 	LastMovedAt *time.Time `json:"last_moved_at"`
 }
 
+// webhookEventTypes are the event types of the webhooks Readwise Reader sends
+var webhookEventTypes = []WebhookEventType{
+	EventAnyDocumentCreated,
+	EventFeedDocumentCreated,
+	EventNonFeedDocumentCreated,
+	EventDocumentTagsUpdated,
+	EventDocumentFinished,
+	EventDocumentArchived,
+	EventDocumentMovedToLater,
+	EventDocumentMovedToInbox,
+	EventDocumentShortlisted,
+}
+
+// UnmarshalJSON decodes a payload, parsing its times with ParseTime. The
+// event type, location and category are not validated, see
+// DisallowUnknownEventTypes.
+func (p *DocumentWebhookPayload) UnmarshalJSON(data []byte) error {
+	// The shared fields shadow the fields of the payload, which are embedded
+	// one level deeper
+	type payload DocumentWebhookPayload
+	type fields struct{ *payload }
+	var shared documentFields
+	aux := struct {
+		fields
+		*documentFields
+		EventType string `json:"event_type"`
+	}{fields: fields{(*payload)(p)}, documentFields: &shared}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	p.EventType = WebhookEventType(aux.EventType)
+	shared.set(&p.Location, &p.Category, &p.CreatedAt, &p.UpdatedAt, &p.FirstOpenedAt, &p.LastOpenedAt, &p.SavedAt, &p.LastMovedAt)
+	return nil
+}
+
+// ToDocument returns the document of the payload, so that code written for
+// documents fetched by polling can handle webhook events too. Every field of
+// the payload but the event type and secret has a Document field: Source,
+// ImageURL, Content and ParentID are empty when they are null, and
+// ReadingProgress is ReadingProgressPercent. See WatchEvent.Payload for the
+// reverse conversion.
+func (p *DocumentWebhookPayload) ToDocument() Document {
+	doc := Document{
+		ID:                     p.ID,
+		URL:                    p.URL,
+		SourceURL:              p.SourceURL,
+		Title:                  p.Title,
+		Author:                 p.Author,
+		Category:               p.Category,
+		Location:               p.Location,
+		Tags:                   p.Tags,
+		SiteName:               p.SiteName,
+		WordCount:              p.WordCount,
+		ReadingTime:            p.ReadingTime,
+		CreatedAt:              p.CreatedAt,
+		UpdatedAt:              p.UpdatedAt,
+		PublishedDate:          p.PublishedDate,
+		Summary:                p.Summary,
+		Notes:                  p.Notes,
+		ReadingProgressPercent: p.ReadingProgress,
+		FirstOpenedAt:          p.FirstOpenedAt,
+		LastOpenedAt:           p.LastOpenedAt,
+		SavedAt:                p.SavedAt,
+		LastMovedAt:            p.LastMovedAt,
+	}
+	if p.Source != nil {
+		doc.Source = *p.Source
+	}
+	if p.ImageURL != nil {
+		doc.ImageURL = *p.ImageURL
+	}
+	if p.Content != nil {
+		doc.HTMLContent = *p.Content
+	}
+	if p.ParentID != nil {
+		doc.ParentID = *p.ParentID
+	}
+	return doc
+}
+
+// DecodeOption configures the decoding of webhook payloads
+type DecodeOption func(*decodeOptions)
+
+type decodeOptions struct {
+	disallowUnknownFields     bool
+	disallowUnknownEventTypes bool
+}
+
+// DisallowUnknownFields makes decoding fail for payloads with fields that
+// DocumentWebhookPayload does not have, e.g. to notice changes to the
+// webhooks
+func DisallowUnknownFields() DecodeOption {
+	return func(o *decodeOptions) {
+		o.disallowUnknownFields = true
+	}
+}
+
+// DisallowUnknownEventTypes makes decoding fail for payloads with an event
+// type that Readwise Reader does not send, including the event types only
+// reported by Watch
+func DisallowUnknownEventTypes() DecodeOption {
+	return func(o *decodeOptions) {
+		o.disallowUnknownEventTypes = true
+	}
+}
+
+// payloadFields are the JSON field names of DocumentWebhookPayload
+var payloadFields = sync.OnceValue(func() map[string]bool {
+	fields := make(map[string]bool)
+	t := reflect.TypeFor[DocumentWebhookPayload]()
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		fields[name] = true
+	}
+	return fields
+})
+
 // DecodeDocumentWebhookPayload decodes a JSON webhook payload into a DocumentWebhookPayload struct.
 // This function is typically used in webhook handlers to parse incoming POST request bodies.
-func DecodeDocumentWebhookPayload(r io.Reader) (*DocumentWebhookPayload, error) {
+// Times are parsed with ParseTime. By default unknown fields are ignored and
+// any event type is accepted, see DisallowUnknownFields and
+// DisallowUnknownEventTypes.
+func DecodeDocumentWebhookPayload(r io.Reader, opts ...DecodeOption) (*DocumentWebhookPayload, error) {
+	var o decodeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var raw json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook payload: %w", err)
+	}
 	var payload DocumentWebhookPayload
-	if err := json.NewDecoder(r).Decode(&payload); err != nil {
+	if err := json.Unmarshal(raw, &payload); err != nil {
 		return nil, fmt.Errorf("failed to decode webhook payload: %w", err)
 	}
+
+	if o.disallowUnknownFields {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			return nil, fmt.Errorf("failed to decode webhook payload: %w", err)
+		}
+		var unknown []string
+		for name := range fields {
+			if !payloadFields()[name] {
+				unknown = append(unknown, name)
+			}
+		}
+		if len(unknown) > 0 {
+			sort.Strings(unknown)
+			return nil, fmt.Errorf("failed to decode webhook payload: unknown fields: %s", strings.Join(unknown, ", "))
+		}
+	}
+	if o.disallowUnknownEventTypes && !slices.Contains(webhookEventTypes, payload.EventType) {
+		return nil, fmt.Errorf("failed to decode webhook payload: unknown event type: %q", payload.EventType)
+	}
 	return &payload, nil
 }
+
+// WebhookHandlerFunc handles a decoded webhook payload. Returning an error makes
+// the webhook handler respond with an error status so that Readwise retries.
+type WebhookHandlerFunc func(ctx context.Context, payload *DocumentWebhookPayload) error
+
+// MaxWebhookPayloadSize is the size of the largest request body accepted by
+// NewWebhookHandler
+const MaxWebhookPayloadSize = 10 << 20
+
+// NewWebhookHandler returns an http.Handler that receives Readwise Reader
+// webhooks. It decodes the payload with the given options, verifies its
+// secret when secret is not empty, and passes it to fn. Bodies larger than
+// MaxWebhookPayloadSize are rejected.
+func NewWebhookHandler(secret string, fn WebhookHandlerFunc, opts ...DecodeOption) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		payload, err := DecodeDocumentWebhookPayload(http.MaxBytesReader(w, r.Body, MaxWebhookPayloadSize), opts...)
+		if err != nil {
+			status := http.StatusBadRequest
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				status = http.StatusRequestEntityTooLarge
+			}
+			http.Error(w, err.Error(), status)
+			return
+		}
+
+		if secret != "" && subtle.ConstantTimeCompare([]byte(payload.Secret), []byte(secret)) != 1 {
+			http.Error(w, "invalid secret", http.StatusUnauthorized)
+			return
+		}
+
+		if err := fn(r.Context(), payload); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	})
+}
@@ -1,6 +1,11 @@
 package reader
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -222,3 +227,197 @@ func TestDecodeDocumentWebhookPayload(t *testing.T) {
 		})
 	}
 }
+
+func TestNewWebhookHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		handlerErr error
+		wantStatus int
+		wantCalled bool
+	}{
+		{
+			name:       "valid payload",
+			method:     http.MethodPost,
+			body:       `{"event_type": "reader.document.finished", "secret": "s3cret", "id": "doc1"}`,
+			wantStatus: http.StatusOK,
+			wantCalled: true,
+		},
+		{
+			name:       "invalid secret",
+			method:     http.MethodPost,
+			body:       `{"event_type": "reader.document.finished", "secret": "wrong", "id": "doc1"}`,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "invalid JSON",
+			method:     http.MethodPost,
+			body:       `{`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "wrong method",
+			method:     http.MethodGet,
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:       "payload too large",
+			method:     http.MethodPost,
+			body:       `{"event_type": "reader.document.finished", "secret": "s3cret", "id": "doc1", "content": "` + strings.Repeat("x", MaxWebhookPayloadSize) + `"}`,
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:       "handler error",
+			method:     http.MethodPost,
+			body:       `{"event_type": "reader.document.finished", "secret": "s3cret", "id": "doc1"}`,
+			handlerErr: errors.New("sink down"),
+			wantStatus: http.StatusInternalServerError,
+			wantCalled: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var called bool
+			handler := NewWebhookHandler("s3cret", func(ctx context.Context, p *DocumentWebhookPayload) error {
+				called = true
+				if p.ID != "doc1" {
+					t.Errorf("ID = %v, want doc1", p.ID)
+				}
+				return tt.handlerErr
+			})
+
+			req := httptest.NewRequest(tt.method, "/webhook", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if called != tt.wantCalled {
+				t.Errorf("called = %v, want %v", called, tt.wantCalled)
+			}
+		})
+	}
+}
+
+func TestDecodeDocumentWebhookPayload_Strict(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		opts    []DecodeOption
+		wantErr string
+	}{
+		{
+			name: "unknown field allowed by default",
+			json: `{"event_type": "reader.document.finished", "id": "doc1", "new_field": 1}`,
+		},
+		{
+			name:    "unknown fields",
+			json:    `{"event_type": "reader.document.finished", "id": "doc1", "new_field": 1, "another": "x"}`,
+			opts:    []DecodeOption{DisallowUnknownFields()},
+			wantErr: "unknown fields: another, new_field",
+		},
+		{
+			name: "known fields",
+			json: `{"event_type": "reader.document.finished", "secret": "s", "id": "doc1", "reading_progress": 100, "content": null}`,
+			opts: []DecodeOption{DisallowUnknownFields(), DisallowUnknownEventTypes()},
+		},
+		{
+			name:    "unknown event type",
+			json:    `{"event_type": "reader.document.exploded", "id": "doc1"}`,
+			opts:    []DecodeOption{DisallowUnknownEventTypes()},
+			wantErr: `unknown event type: "reader.document.exploded"`,
+		},
+		{
+			name:    "watch event type",
+			json:    `{"event_type": "reader.document.moved", "id": "doc1"}`,
+			opts:    []DecodeOption{DisallowUnknownEventTypes()},
+			wantErr: "unknown event type",
+		},
+		{
+			name: "date-only time",
+			json: `{"event_type": "reader.document.finished", "id": "doc1", "created_at": "2025-09-01"}`,
+			opts: []DecodeOption{DisallowUnknownFields()},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, err := DecodeDocumentWebhookPayload(strings.NewReader(tt.json), tt.opts...)
+			if tt.wantErr == "" {
+				if err != nil || payload.ID != "doc1" {
+					t.Errorf("DecodeDocumentWebhookPayload() = %+v, %v", payload, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("DecodeDocumentWebhookPayload() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	// The handler rejects payloads that fail strict decoding
+	handler := NewWebhookHandler("", func(ctx context.Context, p *DocumentWebhookPayload) error {
+		t.Error("handler called for an unknown event type")
+		return nil
+	}, DisallowUnknownEventTypes())
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(`{"event_type": "reader.unknown", "id": "doc1"}`))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestDocumentWebhookPayload_ToDocument(t *testing.T) {
+	ts := func(day int) *time.Time {
+		t := time.Date(2025, 9, day, 10, 0, 0, 0, time.UTC)
+		return &t
+	}
+	doc := Document{
+		ID:                     "doc1",
+		URL:                    "https://read.readwise.io/read/doc1",
+		SourceURL:              "https://example.com/essay",
+		Title:                  "Essay",
+		Author:                 "Jane",
+		Notes:                  "note",
+		Tags:                   map[string]interface{}{"ai": map[string]interface{}{"name": "ai"}},
+		Category:               CategoryArticle,
+		Location:               LocationLater,
+		CreatedAt:              ts(1),
+		UpdatedAt:              ts(2),
+		Summary:                "summary",
+		HTMLContent:            "<p>Essay</p>",
+		ReadingProgressPercent: 45,
+		WordCount:              1200,
+		Source:                 "reader-extension",
+		SiteName:               "Example",
+		ImageURL:               "https://example.com/cover.png",
+		FirstOpenedAt:          ts(3),
+		LastOpenedAt:           ts(4),
+		SavedAt:                ts(5),
+		LastMovedAt:            ts(6),
+		PublishedDate:          "2025-08-30",
+		ReadingTime:            "5 mins",
+		ParentID:               "parent1",
+	}
+
+	// Every field but the plain text content, which webhooks do not carry,
+	// converts to a payload and back
+	v := reflect.ValueOf(doc)
+	for i := range v.NumField() {
+		if name := v.Type().Field(i).Name; name != "Content" && v.Field(i).IsZero() {
+			t.Fatalf("field %s is not set in the test document", name)
+		}
+	}
+	payload := WatchEvent{Type: EventDocumentFinished, Document: doc}.Payload()
+	if got := payload.ToDocument(); !reflect.DeepEqual(got, doc) {
+		t.Errorf("ToDocument() = %+v, want %+v", got, doc)
+	}
+
+	// Null pointers convert to empty strings
+	if got := (&DocumentWebhookPayload{ID: "doc1"}).ToDocument(); !reflect.DeepEqual(got, Document{ID: "doc1"}) {
+		t.Errorf("ToDocument() = %+v, want only the ID", got)
+	}
+}